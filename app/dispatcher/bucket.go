@@ -0,0 +1,92 @@
+package dispatcher
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common/ratelimit"
+)
+
+type userBucket struct {
+	bucket  *ratelimit.Bucket
+	refs    uint32
+	pending bool
+}
+
+// BucketPool keeps the token buckets shared by connections of the same user. A bucket is removed once no connection uses
+// it and it is full again, as it is then no different from a new one.
+type BucketPool struct {
+	access  sync.Mutex
+	buckets map[string]*userBucket
+}
+
+// NewBucketPool creates a new BucketPool.
+func NewBucketPool() *BucketPool {
+	return &BucketPool{
+		buckets: make(map[string]*userBucket),
+	}
+}
+
+// Acquire returns the bucket of the given name for a new connection, with its limit updated to the given one.
+// It also returns a function to be called when the connection is closed.
+func (p *BucketPool) Acquire(name string, limit core.RateLimit) (*ratelimit.Bucket, func()) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	b, found := p.buckets[name]
+	if found {
+		if rate, burst := b.bucket.Limit(); rate != limit.BytesPerSecond || burst != limit.Burst {
+			b.bucket.SetLimit(limit.BytesPerSecond, limit.Burst)
+		}
+	} else {
+		b = &userBucket{
+			bucket: ratelimit.NewBucket(limit.BytesPerSecond, limit.Burst),
+		}
+		p.buckets[name] = b
+	}
+	b.refs++
+
+	var once sync.Once
+	return b.bucket, func() {
+		once.Do(func() {
+			p.release(name, b)
+		})
+	}
+}
+
+// Size returns the number of buckets in this pool.
+func (p *BucketPool) Size() int {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	return len(p.buckets)
+}
+
+func (p *BucketPool) release(name string, b *userBucket) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	b.refs--
+	p.remove(name, b)
+}
+
+// remove removes the bucket if it is unused and full. If it is unused but not full yet, it is checked again once it is full.
+// It must be called with access locked.
+func (p *BucketPool) remove(name string, b *userBucket) {
+	if b.refs > 0 || b.pending {
+		return
+	}
+	if d := b.bucket.RefillTime(); d > 0 {
+		b.pending = true
+		time.AfterFunc(d, func() {
+			p.access.Lock()
+			defer p.access.Unlock()
+
+			b.pending = false
+			p.remove(name, b)
+		})
+		return
+	}
+	delete(p.buckets, name)
+}
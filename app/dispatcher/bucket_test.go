@@ -0,0 +1,56 @@
+package dispatcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	. "v2ray.com/core/app/dispatcher"
+	. "v2ray.com/ext/assert"
+)
+
+func TestBucketPoolShared(t *testing.T) {
+	assert := With(t)
+
+	pool := NewBucketPool()
+	limit := core.RateLimit{BytesPerSecond: 1, Burst: 1024}
+
+	bucket1, release1 := pool.Acquire("love@v2ray.com>>>uplink", limit)
+	bucket2, release2 := pool.Acquire("love@v2ray.com>>>uplink", limit)
+	assert(bucket1 == bucket2, IsTrue)
+	bucket3, release3 := pool.Acquire("other@v2ray.com>>>uplink", limit)
+	assert(bucket1 == bucket3, IsFalse)
+	assert(pool.Size(), Equals, 2)
+
+	// An unused bucket that is full is removed.
+	release3()
+	release3()
+	assert(pool.Size(), Equals, 1)
+
+	// An unused bucket that is not full is kept, so that its limit still applies to new connections.
+	assert(bucket1.Wait(context.Background(), 1024), IsNil)
+	release1()
+	release2()
+	assert(pool.Size(), Equals, 1)
+	bucket4, release4 := pool.Acquire("love@v2ray.com>>>uplink", limit)
+	assert(bucket4 == bucket1, IsTrue)
+	release4()
+}
+
+func TestBucketPoolRefilled(t *testing.T) {
+	assert := With(t)
+
+	pool := NewBucketPool()
+	bucket, release := pool.Acquire("love@v2ray.com>>>uplink", core.RateLimit{BytesPerSecond: 10240, Burst: 10240})
+	assert(bucket.Wait(context.Background(), 1024), IsNil)
+	release()
+	assert(pool.Size(), Equals, 1)
+
+	// The bucket is removed once it is full again, in about 100ms.
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.Size() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert(pool.Size(), Equals, 0)
+}
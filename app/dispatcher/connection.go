@@ -0,0 +1,81 @@
+package dispatcher
+
+import (
+	"sync"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common/net"
+)
+
+type userConnections struct {
+	count   uint32
+	sources map[string]uint32
+}
+
+// ConnectionLimiter tracks active connections of users, and rejects new ones that exceed the ConnectionPolicy.
+type ConnectionLimiter struct {
+	access sync.Mutex
+	users  map[string]*userConnections
+}
+
+// NewConnectionLimiter creates a new ConnectionLimiter.
+func NewConnectionLimiter() *ConnectionLimiter {
+	return &ConnectionLimiter{
+		users: make(map[string]*userConnections),
+	}
+}
+
+// Acquire registers a new connection from the given source for the user with the given email.
+// It returns a function to be called when the connection is closed, or an error if the connection exceeds the limits.
+func (l *ConnectionLimiter) Acquire(email string, source net.Address, policy core.ConnectionPolicy) (func(), error) {
+	ip := ""
+	if source != nil {
+		ip = source.String()
+	}
+
+	l.access.Lock()
+	defer l.access.Unlock()
+
+	u, found := l.users[email]
+	if !found {
+		u = &userConnections{
+			sources: make(map[string]uint32),
+		}
+	}
+
+	if policy.MaxConcurrent > 0 && u.count >= policy.MaxConcurrent {
+		return nil, newError("user ", email, " exceeds max concurrent connections of ", policy.MaxConcurrent)
+	}
+	if _, known := u.sources[ip]; !known && policy.MaxSourceIP > 0 && uint32(len(u.sources)) >= policy.MaxSourceIP {
+		return nil, newError("user ", email, " exceeds max source IPs of ", policy.MaxSourceIP)
+	}
+
+	u.count++
+	u.sources[ip]++
+	l.users[email] = u
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.release(email, ip)
+		})
+	}, nil
+}
+
+func (l *ConnectionLimiter) release(email string, ip string) {
+	l.access.Lock()
+	defer l.access.Unlock()
+
+	u, found := l.users[email]
+	if !found {
+		return
+	}
+
+	u.count--
+	if u.sources[ip]--; u.sources[ip] == 0 {
+		delete(u.sources, ip)
+	}
+	if u.count == 0 {
+		delete(l.users, email)
+	}
+}
@@ -0,0 +1,65 @@
+package dispatcher_test
+
+import (
+	"testing"
+
+	"v2ray.com/core"
+	. "v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/ext/assert"
+)
+
+func TestConnectionLimiterMaxConcurrent(t *testing.T) {
+	assert := With(t)
+
+	limiter := NewConnectionLimiter()
+	policy := core.ConnectionPolicy{MaxConcurrent: 2}
+	source := net.ParseAddress("10.0.0.1")
+
+	release1, err := limiter.Acquire("love@v2ray.com", source, policy)
+	assert(err, IsNil)
+	release2, err := limiter.Acquire("love@v2ray.com", source, policy)
+	assert(err, IsNil)
+
+	_, err = limiter.Acquire("love@v2ray.com", source, policy)
+	assert(err, IsNotNil)
+
+	_, err = limiter.Acquire("other@v2ray.com", source, policy)
+	assert(err, IsNil)
+
+	release1()
+	release1()
+
+	release3, err := limiter.Acquire("love@v2ray.com", source, policy)
+	assert(err, IsNil)
+
+	_, err = limiter.Acquire("love@v2ray.com", source, policy)
+	assert(err, IsNotNil)
+
+	release2()
+	release3()
+}
+
+func TestConnectionLimiterMaxSourceIP(t *testing.T) {
+	assert := With(t)
+
+	limiter := NewConnectionLimiter()
+	policy := core.ConnectionPolicy{MaxSourceIP: 1}
+
+	release1, err := limiter.Acquire("love@v2ray.com", net.ParseAddress("10.0.0.1"), policy)
+	assert(err, IsNil)
+	release2, err := limiter.Acquire("love@v2ray.com", net.ParseAddress("10.0.0.1"), policy)
+	assert(err, IsNil)
+
+	_, err = limiter.Acquire("love@v2ray.com", net.ParseAddress("10.0.0.2"), policy)
+	assert(err, IsNotNil)
+
+	release1()
+	_, err = limiter.Acquire("love@v2ray.com", net.ParseAddress("10.0.0.2"), policy)
+	assert(err, IsNotNil)
+
+	release2()
+	release3, err := limiter.Acquire("love@v2ray.com", net.ParseAddress("10.0.0.2"), policy)
+	assert(err, IsNil)
+	release3()
+}
@@ -8,12 +8,17 @@ import (
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/quota"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/ratelimit"
+	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/stats"
 	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/pipe"
 )
 
@@ -53,20 +58,28 @@ func (r *cachedReader) CloseError() {
 
 // DefaultDispatcher is a default implementation of Dispatcher.
 type DefaultDispatcher struct {
+	v      *core.Instance
 	ohm    core.OutboundHandlerManager
 	router core.Router
 	policy core.PolicyManager
 	stats  core.StatManager
+
+	buckets     *BucketPool
+	connections *ConnectionLimiter
+	quota       *quota.Manager
 }
 
 // NewDefaultDispatcher create a new DefaultDispatcher.
 func NewDefaultDispatcher(ctx context.Context, config *Config) (*DefaultDispatcher, error) {
 	v := core.MustFromContext(ctx)
 	d := &DefaultDispatcher{
-		ohm:    v.OutboundHandlerManager(),
-		router: v.Router(),
-		policy: v.PolicyManager(),
-		stats:  v.Stats(),
+		v:           v,
+		ohm:         v.OutboundHandlerManager(),
+		router:      v.Router(),
+		policy:      v.PolicyManager(),
+		stats:       v.Stats(),
+		buckets:     NewBucketPool(),
+		connections: NewConnectionLimiter(),
 	}
 
 	if err := v.RegisterFeature((*core.Dispatcher)(nil), d); err != nil {
@@ -76,14 +89,71 @@ func NewDefaultDispatcher(ctx context.Context, config *Config) (*DefaultDispatch
 }
 
 // Start implements common.Runnable.
-func (*DefaultDispatcher) Start() error {
+func (d *DefaultDispatcher) Start() error {
+	// Quota manager is optional, and may be registered after the dispatcher.
+	if q, ok := d.v.GetFeature((*quota.Manager)(nil)).(*quota.Manager); ok {
+		d.quota = q
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (*DefaultDispatcher) Close() error { return nil }
 
-func (d *DefaultDispatcher) getLink(ctx context.Context) (*core.Link, *core.Link) {
+// getBucket returns the token bucket for the user in the given direction, and a function to be called when the connection is closed.
+// Connections of the same user share the same bucket. Users without email can't be told apart, so each of their connections gets
+// its own bucket.
+func (d *DefaultDispatcher) getBucket(user *protocol.User, direction string, limit core.RateLimit) (*ratelimit.Bucket, func()) {
+	if len(user.Email) == 0 {
+		return ratelimit.NewBucket(limit.BytesPerSecond, limit.Burst), func() {}
+	}
+	return d.buckets.Acquire(user.Email+">>>"+direction, limit)
+}
+
+// acquireConnection checks the connection limits of the user in the context.
+// It returns a function to release the connection, or nil if the connection is not subject to any limit.
+func (d *DefaultDispatcher) acquireConnection(ctx context.Context, destination net.Destination) (func(), error) {
+	user := protocol.UserFromContext(ctx)
+	if user == nil || len(user.Email) == 0 {
+		return nil, nil
+	}
+	// A Mux connection only carries sub-connections, which are dispatched, and limited, on their own.
+	if destination.Address.Family().IsDomain() && destination.Address.Domain() == "v1.mux.cool" {
+		return nil, nil
+	}
+	p := d.policy.ForLevel(user.Level)
+	if !p.Connection.IsLimited() {
+		return nil, nil
+	}
+
+	var sourceAddr net.Address
+	source, hasSource := proxy.SourceFromContext(ctx)
+	if hasSource {
+		sourceAddr = source.Address
+	}
+
+	release, err := d.connections.Acquire(user.Email, sourceAddr, p.Connection)
+	if err != nil {
+		if hasSource {
+			log.Record(&log.AccessMessage{
+				From:   source,
+				To:     destination,
+				Status: log.AccessLimited,
+				Reason: err,
+			})
+		}
+		return nil, err
+	}
+	return release, nil
+}
+
+// getLink creates the links for a new connection. release, if not nil, is called once both directions of the connection are closed,
+// or the context is done. Sub-connections of Mux share the context of their carrier, so the context alone is not enough.
+func (d *DefaultDispatcher) getLink(ctx context.Context, release func()) (*core.Link, *core.Link, error) {
+	var releases []func()
+	if release != nil {
+		releases = append(releases, release)
+	}
 	uplinkReader, uplinkWriter := pipe.New()
 	downlinkReader, downlinkWriter := pipe.New()
 
@@ -98,9 +168,51 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*core.Link, *core.Link
 	}
 
 	user := protocol.UserFromContext(ctx)
-	if user != nil && len(user.Email) > 0 {
+	if user != nil {
+		if d.quota != nil {
+			conn, err := d.quota.Open(user, func() {
+				uplinkWriter.CloseError()
+				downlinkWriter.CloseError()
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			if conn != nil {
+				go func() {
+					<-ctx.Done()
+					conn.Close()
+				}()
+				inboundLink.Writer = &quota.Writer{
+					Connection: conn,
+					Writer:     inboundLink.Writer,
+				}
+				outboundLink.Writer = &quota.Writer{
+					Connection: conn,
+					Writer:     outboundLink.Writer,
+				}
+			}
+		}
+
 		p := d.policy.ForLevel(user.Level)
-		if p.Stats.UserUplink {
+		if p.Bandwidth.Uplink.IsLimited() {
+			bucket, release := d.getBucket(user, "uplink", p.Bandwidth.Uplink)
+			releases = append(releases, release)
+			inboundLink.Writer = &ratelimit.Writer{
+				Context: ctx,
+				Bucket:  bucket,
+				Writer:  inboundLink.Writer,
+			}
+		}
+		if p.Bandwidth.Downlink.IsLimited() {
+			bucket, release := d.getBucket(user, "downlink", p.Bandwidth.Downlink)
+			releases = append(releases, release)
+			outboundLink.Writer = &ratelimit.Writer{
+				Context: ctx,
+				Bucket:  bucket,
+				Writer:  outboundLink.Writer,
+			}
+		}
+		if len(user.Email) > 0 && p.Stats.UserUplink {
 			name := "user>>>" + user.Email + ">>>traffic>>>uplink"
 			if c, _ := core.GetOrRegisterStatCounter(d.stats, name); c != nil {
 				inboundLink.Writer = &stats.SizeStatWriter{
@@ -109,7 +221,7 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*core.Link, *core.Link
 				}
 			}
 		}
-		if p.Stats.UserDownlink {
+		if len(user.Email) > 0 && p.Stats.UserDownlink {
 			name := "user>>>" + user.Email + ">>>traffic>>>downlink"
 			if c, _ := core.GetOrRegisterStatCounter(d.stats, name); c != nil {
 				outboundLink.Writer = &stats.SizeStatWriter{
@@ -120,7 +232,24 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*core.Link, *core.Link
 		}
 	}
 
-	return inboundLink, outboundLink
+	if len(releases) > 0 {
+		go func() {
+			defer func() {
+				for _, release := range releases {
+					release()
+				}
+			}()
+			for _, done := range []<-chan struct{}{uplinkWriter.Done(), downlinkReader.Done()} {
+				select {
+				case <-done:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return inboundLink, outboundLink, nil
 }
 
 // Dispatch implements core.Dispatcher.
@@ -130,7 +259,31 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	}
 	ctx = proxy.ContextWithTarget(ctx, destination)
 
-	inbound, outbound := d.getLink(ctx)
+	release, err := d.acquireConnection(ctx, destination)
+	if err != nil {
+		return nil, newError("connection rejected").Base(err)
+	}
+
+	inbound, outbound, err := d.getLink(ctx, release)
+	if err != nil {
+		if release != nil {
+			release()
+		}
+		if source, ok := proxy.SourceFromContext(ctx); ok {
+			log.Record(&log.AccessMessage{
+				From:   source,
+				To:     destination,
+				Status: log.AccessRejected,
+				Reason: err,
+			})
+		}
+		return nil, newError("connection rejected").Base(err)
+	}
+	if conn, ok := proxy.BindConnectionFromContext(ctx); ok {
+		go d.bindDispatch(ctx, outbound, conn)
+		return inbound, nil
+	}
+
 	snifferList := proxyman.ProtocolSniffersFromContext(ctx)
 	if destination.Address.Family().IsDomain() || len(snifferList) == 0 {
 		go d.routedDispatch(ctx, outbound, destination)
@@ -200,6 +353,27 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *core.Link,
 	dispatcher.Dispatch(ctx, link)
 }
 
+// bindDispatch relays the link to a connection accepted for a BIND request. The link is limited and accounted
+// as any other, but goes through no outbound handler.
+func (d *DefaultDispatcher) bindDispatch(ctx context.Context, link *core.Link, conn internet.Connection) {
+	defer conn.Close()
+
+	requestDone := func() error {
+		return buf.Copy(link.Reader, buf.NewWriter(conn))
+	}
+
+	responseDone := func() error {
+		defer common.Close(link.Writer)
+		return buf.Copy(buf.NewReader(conn), link.Writer)
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestDone, responseDone); err != nil {
+		newError("bind connection ends").Base(err).WithContext(ctx).WriteToLog()
+		pipe.CloseError(link.Reader)
+		pipe.CloseError(link.Writer)
+	}
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return NewDefaultDispatcher(ctx, config.(*Config))
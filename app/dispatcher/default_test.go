@@ -0,0 +1,135 @@
+package dispatcher_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	. "v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/blackhole"
+	. "v2ray.com/ext/assert"
+)
+
+func TestDispatchBindConnection(t *testing.T) {
+	assert := With(t)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&policy.Config{
+				Level: map[uint32]*policy.Policy{
+					0: {
+						Stats: &policy.Policy_Stats{
+							UserUplink:   true,
+							UserDownlink: true,
+						},
+					},
+				},
+			}),
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.LocalHostIP.IP()})
+	assert(err, IsNil)
+	defer listener.Close()
+
+	client, err := net.DialTCP("tcp", nil, listener.Addr().(*net.TCPAddr))
+	assert(err, IsNil)
+	defer client.Close()
+	peer, err := listener.Accept()
+	assert(err, IsNil)
+
+	ctx := protocol.ContextWithUser(context.Background(), &protocol.User{Email: "love@v2ray.com"})
+	ctx = proxy.ContextWithBindConnection(ctx, peer)
+	link, err := v.Dispatcher().Dispatch(ctx, net.TCPDestination(net.LocalHostIP, net.Port(1234)))
+	assert(err, IsNil)
+
+	payload := buf.New()
+	payload.Write([]byte("request"))
+	assert(link.Writer.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+	b := make([]byte, 7)
+	_, err = io.ReadFull(client, b)
+	assert(err, IsNil)
+	assert(string(b), Equals, "request")
+
+	_, err = client.Write([]byte("response!"))
+	assert(err, IsNil)
+	mb, err := link.Reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "response!")
+
+	assert(v.Stats().GetCounter("user>>>love@v2ray.com>>>traffic>>>uplink").Value(), Equals, int64(7))
+	assert(v.Stats().GetCounter("user>>>love@v2ray.com>>>traffic>>>downlink").Value(), Equals, int64(9))
+}
+
+func TestDispatchConnectionRelease(t *testing.T) {
+	assert := With(t)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{
+				Level: map[uint32]*policy.Policy{
+					0: {
+						Connection: &policy.Policy_Connection{
+							MaxConcurrent: 1,
+						},
+					},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	// Sub-connections of Mux share a long-living context.
+	ctx, cancel := context.WithCancel(protocol.ContextWithUser(context.Background(), &protocol.User{Email: "love@v2ray.com"}))
+	defer cancel()
+	destination := net.TCPDestination(net.LocalHostIP, net.Port(1234))
+
+	link, err := v.Dispatcher().Dispatch(ctx, destination)
+	assert(err, IsNil)
+	_, err = v.Dispatcher().Dispatch(ctx, destination)
+	assert(err, IsNotNil)
+
+	// The Mux carrier itself doesn't count.
+	_, err = v.Dispatcher().Dispatch(ctx, net.TCPDestination(net.DomainAddress("v1.mux.cool"), net.Port(9527)))
+	assert(err, IsNil)
+
+	// The connection is released once both directions are closed, even if the context is not done.
+	assert(common.Close(link.Writer), IsNil)
+	_, err = link.Reader.ReadMultiBuffer()
+	assert(err, IsNotNil)
+	time.Sleep(time.Millisecond * 100)
+
+	_, err = v.Dispatcher().Dispatch(ctx, destination)
+	assert(err, IsNil)
+}
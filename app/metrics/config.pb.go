@@ -0,0 +1,63 @@
+package metrics
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Config is the settings for the metrics endpoint.
+type Config struct {
+	// Address of the HTTP listener, such as "127.0.0.1:9100".
+	Listen string `protobuf:"bytes,1,opt,name=listen" json:"listen,omitempty"`
+	// HTTP path that serves the metrics. Default to "/metrics" if unset.
+	Path string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Config) GetListen() string {
+	if m != nil {
+		return m.Listen
+	}
+	return ""
+}
+
+func (m *Config) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.metrics.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/metrics/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 158 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2f, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0x2c, 0x28, 0xd0, 0xcf,
+	0x4d, 0x2d, 0x29, 0xca, 0x4c, 0x2e, 0xd6, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28,
+	0xca, 0x2f, 0xc9, 0x17, 0x12, 0x83, 0x29, 0x2c, 0x4a, 0xd5, 0x4b, 0x2c, 0x28, 0xd0, 0x83, 0x2a,
+	0x52, 0x32, 0xe1, 0x62, 0x73, 0x06, 0xab, 0x13, 0x12, 0xe3, 0x62, 0xcb, 0xc9, 0x2c, 0x2e, 0x49,
+	0xcd, 0x93, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x82, 0xf2, 0x84, 0x84, 0xb8, 0x58, 0x0a, 0x12,
+	0x4b, 0x32, 0x24, 0x98, 0xc0, 0xa2, 0x60, 0xb6, 0x93, 0x03, 0x97, 0x54, 0x72, 0x7e, 0xae, 0x1e,
+	0x76, 0x33, 0x03, 0x18, 0xa3, 0xd8, 0xa1, 0xcc, 0x55, 0x4c, 0x62, 0x61, 0x46, 0x41, 0x89, 0x95,
+	0x7a, 0xce, 0x20, 0x35, 0x8e, 0x05, 0x05, 0x7a, 0xbe, 0x10, 0x89, 0x24, 0x36, 0xb0, 0xb3, 0x8c,
+	0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0xeb, 0x67, 0x7f, 0x6c, 0xc1, 0x00, 0x00, 0x00,
+}
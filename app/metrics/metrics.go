@@ -0,0 +1,101 @@
+package metrics
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg metrics -path App,Metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+)
+
+// Metrics is a V2Ray feature that exposes stats counters and runtime metrics in Prometheus text format.
+type Metrics struct {
+	sync.Mutex
+	config    Config
+	stats     core.StatManager
+	server    *http.Server
+	startTime time.Time
+}
+
+// NewMetrics creates a new Metrics based on the given config.
+func NewMetrics(ctx context.Context, config *Config) (*Metrics, error) {
+	v := core.MustFromContext(ctx)
+	m := &Metrics{
+		config:    *config,
+		stats:     v.Stats(),
+		startTime: time.Now(),
+	}
+	if err := v.RegisterFeature((*Metrics)(nil), m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Type implements common.HasType.
+func (m *Metrics) Type() interface{} {
+	return (*Metrics)(nil)
+}
+
+// ServeHTTP implements http.Handler.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := WriteMetrics(w, m.stats, m.startTime); err != nil {
+		newError("failed to write metrics").Base(err).AtWarning().WriteToLog()
+	}
+}
+
+// Start implements common.Runnable.
+func (m *Metrics) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	listener, err := net.Listen("tcp", m.config.Listen)
+	if err != nil {
+		return newError("failed to listen on ", m.config.Listen).Base(err)
+	}
+
+	path := m.config.Path
+	if len(path) == 0 {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, m)
+	m.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			newError("failed to serve metrics").Base(err).AtError().WriteToLog()
+		}
+	}(m.server)
+
+	newError("metrics listening on ", listener.Addr(), path).AtInfo().WriteToLog()
+	return nil
+}
+
+// Close implements common.Closable.
+func (m *Metrics) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.server != nil {
+		m.server.Close()
+		m.server = nil
+	}
+
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		return NewMetrics(ctx, cfg.(*Config))
+	}))
+}
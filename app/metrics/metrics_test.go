@@ -0,0 +1,42 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	. "v2ray.com/core/app/metrics"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	. "v2ray.com/ext/assert"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	assert := With(t)
+
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	c, err := manager.RegisterCounter("user>>>love@v2ray.com>>>traffic>>>uplink")
+	common.Must(err)
+	c.Add(1234567890)
+
+	c, err = manager.RegisterCounter("inbound>>>api>>>traffic>>>downlink")
+	common.Must(err)
+	c.Add(10)
+
+	c, err = manager.RegisterCounter("custom\"counter")
+	common.Must(err)
+	c.Add(1)
+
+	var b bytes.Buffer
+	assert(WriteMetrics(&b, manager, time.Now()), IsNil)
+
+	output := b.String()
+	assert(output, HasSubstring, "# TYPE v2ray_traffic_uplink_bytes_total counter\n")
+	assert(output, HasSubstring, "v2ray_traffic_uplink_bytes_total{dimension=\"user\",target=\"love@v2ray.com\"} 1234567890\n")
+	assert(output, HasSubstring, "v2ray_traffic_downlink_bytes_total{dimension=\"inbound\",target=\"api\"} 10\n")
+	assert(output, HasSubstring, "v2ray_stats_counter{name=\"custom\\\"counter\"} 1\n")
+	assert(output, HasSubstring, "# TYPE go_goroutines gauge\n")
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"v2ray.com/core"
+)
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+type familySet map[string]*family
+
+func (s familySet) add(name, kind, help, labels string, value float64) {
+	f, found := s[name]
+	if !found {
+		f = &family{
+			name: name,
+			kind: kind,
+			help: help,
+		}
+		s[name] = f
+	}
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// sanitizeName turns an arbitrary string into a valid Prometheus metric name component.
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(kv ...string) string {
+	parts := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, kv[i]+`="`+labelEscaper.Replace(kv[i+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// addCounter translates a stats counter into a metric sample.
+// Counters named in the form of "dimension>>>target>>>category>>>name", such as "user>>>love@v2ray.com>>>traffic>>>uplink",
+// become "v2ray_category_name_total{dimension="...",target="..."}". Traffic counters carry a "_bytes" unit suffix.
+// Counters in other forms are exported as "v2ray_stats_counter{name="..."}".
+func (s familySet) addCounter(name string, value int64) {
+	parts := strings.Split(name, ">>>")
+	if len(parts) != 4 {
+		s.add("v2ray_stats_counter", "counter", "Stats counters whose name can't be translated.", formatLabels("name", name), float64(value))
+		return
+	}
+
+	metric := "v2ray_" + sanitizeName(parts[2]) + "_" + sanitizeName(parts[3])
+	help := "Stats counters in the form of " + parts[2] + ">>>" + parts[3] + "."
+	if parts[2] == "traffic" {
+		metric += "_bytes"
+		help = "Number of " + parts[3] + " bytes."
+	}
+	metric += "_total"
+	s.add(metric, "counter", help, formatLabels("dimension", parts[0], "target", parts[1]), float64(value))
+}
+
+func (s familySet) addRuntime(startTime time.Time) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	s.add("go_goroutines", "gauge", "Number of goroutines that currently exist.", "", float64(runtime.NumGoroutine()))
+	s.add("go_memstats_alloc_bytes", "gauge", "Number of bytes allocated and still in use.", "", float64(rtm.Alloc))
+	s.add("go_memstats_alloc_bytes_total", "counter", "Total number of bytes allocated, even if freed.", "", float64(rtm.TotalAlloc))
+	s.add("go_memstats_sys_bytes", "gauge", "Number of bytes obtained from system.", "", float64(rtm.Sys))
+	s.add("go_memstats_heap_objects", "gauge", "Number of allocated objects.", "", float64(rtm.Mallocs-rtm.Frees))
+	s.add("go_memstats_mallocs_total", "counter", "Total number of mallocs.", "", float64(rtm.Mallocs))
+	s.add("go_memstats_frees_total", "counter", "Total number of frees.", "", float64(rtm.Frees))
+	s.add("go_gc_cycles_total", "counter", "Number of completed GC cycles.", "", float64(rtm.NumGC))
+	s.add("go_gc_pause_seconds_total", "counter", "Total time spent in GC stop-the-world pauses.", "", float64(rtm.PauseTotalNs)/1e9)
+	s.add("v2ray_uptime_seconds", "gauge", "Number of seconds since V2Ray started.", "", time.Since(startTime).Seconds())
+}
+
+// WriteMetrics writes all counters in the StatManager, as well as runtime metrics, in Prometheus text exposition format.
+func WriteMetrics(w io.Writer, stats core.StatManager, startTime time.Time) error {
+	set := make(familySet)
+	stats.Visit(func(name string, c core.StatCounter) bool {
+		set.addCounter(name, c.Value())
+		return true
+	})
+	set.addRuntime(startTime)
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := bufio.NewWriter(w)
+	for _, name := range names {
+		f := set[name]
+		sort.Slice(f.samples, func(i, j int) bool {
+			return f.samples[i].labels < f.samples[j].labels
+		})
+		fmt.Fprintf(writer, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(writer, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.samples {
+			fmt.Fprintf(writer, "%s%s %s\n", f.name, s.labels, strconv.FormatFloat(s.value, 'f', -1, 64))
+		}
+	}
+	return writer.Flush()
+}
@@ -50,6 +50,31 @@ func (p *Policy) overrideWith(another *Policy) {
 		p.Stats = new(Policy_Stats)
 		*p.Stats = *another.Stats
 	}
+	if another.Bandwidth != nil && p.Bandwidth == nil {
+		p.Bandwidth = &Policy_Bandwidth{
+			Uplink:   another.Bandwidth.Uplink,
+			Downlink: another.Bandwidth.Downlink,
+		}
+	}
+	if another.Connection != nil && p.Connection == nil {
+		p.Connection = new(Policy_Connection)
+		*p.Connection = *another.Connection
+	}
+}
+
+// ToCoreRateLimit converts this Rate to core.RateLimit.
+func (r *Policy_Rate) ToCoreRateLimit() core.RateLimit {
+	if r == nil {
+		return core.RateLimit{}
+	}
+	limit := core.RateLimit{
+		BytesPerSecond: r.BytesPerSecond,
+		Burst:          r.Burst,
+	}
+	if limit.Burst == 0 {
+		limit.Burst = limit.BytesPerSecond
+	}
+	return limit
 }
 
 // ToCorePolicy converts this Policy to core.Policy.
@@ -65,6 +90,14 @@ func (p *Policy) ToCorePolicy() core.Policy {
 		cp.Stats.UserUplink = p.Stats.UserUplink
 		cp.Stats.UserDownlink = p.Stats.UserDownlink
 	}
+	if p.Bandwidth != nil {
+		cp.Bandwidth.Uplink = p.Bandwidth.Uplink.ToCoreRateLimit()
+		cp.Bandwidth.Downlink = p.Bandwidth.Downlink.ToCoreRateLimit()
+	}
+	if p.Connection != nil {
+		cp.Connection.MaxConcurrent = p.Connection.MaxConcurrent
+		cp.Connection.MaxSourceIP = p.Connection.MaxSourceIp
+	}
 	return cp
 }
 
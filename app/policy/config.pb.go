@@ -32,8 +32,10 @@ func (m *Second) GetValue() uint32 {
 }
 
 type Policy struct {
-	Timeout *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout" json:"timeout,omitempty"`
-	Stats   *Policy_Stats   `protobuf:"bytes,2,opt,name=stats" json:"stats,omitempty"`
+	Timeout    *Policy_Timeout    `protobuf:"bytes,1,opt,name=timeout" json:"timeout,omitempty"`
+	Stats      *Policy_Stats      `protobuf:"bytes,2,opt,name=stats" json:"stats,omitempty"`
+	Bandwidth  *Policy_Bandwidth  `protobuf:"bytes,3,opt,name=bandwidth" json:"bandwidth,omitempty"`
+	Connection *Policy_Connection `protobuf:"bytes,4,opt,name=connection" json:"connection,omitempty"`
 }
 
 func (m *Policy) Reset()                    { *m = Policy{} }
@@ -55,6 +57,20 @@ func (m *Policy) GetStats() *Policy_Stats {
 	return nil
 }
 
+func (m *Policy) GetBandwidth() *Policy_Bandwidth {
+	if m != nil {
+		return m.Bandwidth
+	}
+	return nil
+}
+
+func (m *Policy) GetConnection() *Policy_Connection {
+	if m != nil {
+		return m.Connection
+	}
+	return nil
+}
+
 // Timeout is a message for timeout settings in various stages, in seconds.
 type Policy_Timeout struct {
 	Handshake      *Second `protobuf:"bytes,1,opt,name=handshake" json:"handshake,omitempty"`
@@ -120,6 +136,85 @@ func (m *Policy_Stats) GetUserDownlink() bool {
 	return false
 }
 
+// Rate is a token bucket rate limit.
+type Policy_Rate struct {
+	// Number of bytes allowed per second. Unlimited if 0.
+	BytesPerSecond uint64 `protobuf:"varint,1,opt,name=bytes_per_second,json=bytesPerSecond" json:"bytes_per_second,omitempty"`
+	// Max number of bytes that can be sent at once. Default to bytes_per_second if 0.
+	Burst uint64 `protobuf:"varint,2,opt,name=burst" json:"burst,omitempty"`
+}
+
+func (m *Policy_Rate) Reset()                    { *m = Policy_Rate{} }
+func (m *Policy_Rate) String() string            { return proto.CompactTextString(m) }
+func (*Policy_Rate) ProtoMessage()               {}
+func (*Policy_Rate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 2} }
+
+func (m *Policy_Rate) GetBytesPerSecond() uint64 {
+	if m != nil {
+		return m.BytesPerSecond
+	}
+	return 0
+}
+
+func (m *Policy_Rate) GetBurst() uint64 {
+	if m != nil {
+		return m.Burst
+	}
+	return 0
+}
+
+// Bandwidth limits traffic of a user, across all its concurrent connections.
+type Policy_Bandwidth struct {
+	Uplink   *Policy_Rate `protobuf:"bytes,1,opt,name=uplink" json:"uplink,omitempty"`
+	Downlink *Policy_Rate `protobuf:"bytes,2,opt,name=downlink" json:"downlink,omitempty"`
+}
+
+func (m *Policy_Bandwidth) Reset()                    { *m = Policy_Bandwidth{} }
+func (m *Policy_Bandwidth) String() string            { return proto.CompactTextString(m) }
+func (*Policy_Bandwidth) ProtoMessage()               {}
+func (*Policy_Bandwidth) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 3} }
+
+func (m *Policy_Bandwidth) GetUplink() *Policy_Rate {
+	if m != nil {
+		return m.Uplink
+	}
+	return nil
+}
+
+func (m *Policy_Bandwidth) GetDownlink() *Policy_Rate {
+	if m != nil {
+		return m.Downlink
+	}
+	return nil
+}
+
+// Connection limits connections of a user.
+type Policy_Connection struct {
+	// Max number of concurrent connections of a user. Unlimited if 0.
+	MaxConcurrent uint32 `protobuf:"varint,1,opt,name=max_concurrent,json=maxConcurrent" json:"max_concurrent,omitempty"`
+	// Max number of distinct source IPs that a user connects from at the same time. Unlimited if 0.
+	MaxSourceIp uint32 `protobuf:"varint,2,opt,name=max_source_ip,json=maxSourceIp" json:"max_source_ip,omitempty"`
+}
+
+func (m *Policy_Connection) Reset()                    { *m = Policy_Connection{} }
+func (m *Policy_Connection) String() string            { return proto.CompactTextString(m) }
+func (*Policy_Connection) ProtoMessage()               {}
+func (*Policy_Connection) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 4} }
+
+func (m *Policy_Connection) GetMaxConcurrent() uint32 {
+	if m != nil {
+		return m.MaxConcurrent
+	}
+	return 0
+}
+
+func (m *Policy_Connection) GetMaxSourceIp() uint32 {
+	if m != nil {
+		return m.MaxSourceIp
+	}
+	return 0
+}
+
 type SystemPolicy struct {
 	Stats *SystemPolicy_Stats `protobuf:"bytes,1,opt,name=stats" json:"stats,omitempty"`
 }
@@ -189,6 +284,9 @@ func init() {
 	proto.RegisterType((*Policy)(nil), "v2ray.core.app.policy.Policy")
 	proto.RegisterType((*Policy_Timeout)(nil), "v2ray.core.app.policy.Policy.Timeout")
 	proto.RegisterType((*Policy_Stats)(nil), "v2ray.core.app.policy.Policy.Stats")
+	proto.RegisterType((*Policy_Rate)(nil), "v2ray.core.app.policy.Policy.Rate")
+	proto.RegisterType((*Policy_Bandwidth)(nil), "v2ray.core.app.policy.Policy.Bandwidth")
+	proto.RegisterType((*Policy_Connection)(nil), "v2ray.core.app.policy.Policy.Connection")
 	proto.RegisterType((*SystemPolicy)(nil), "v2ray.core.app.policy.SystemPolicy")
 	proto.RegisterType((*SystemPolicy_Stats)(nil), "v2ray.core.app.policy.SystemPolicy.Stats")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.Config")
@@ -197,35 +295,45 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/app/policy/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 478 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xdf, 0x6e, 0xd3, 0x30,
-	0x18, 0xc5, 0x95, 0x96, 0x66, 0xe3, 0x6b, 0xbb, 0x4d, 0x16, 0x93, 0x4a, 0x25, 0x60, 0xea, 0x34,
-	0xd4, 0xdd, 0xb8, 0x52, 0x76, 0x03, 0x4c, 0x0c, 0x31, 0xfe, 0x48, 0x48, 0x20, 0x26, 0x97, 0x3f,
-	0x82, 0x9b, 0xca, 0x73, 0x0c, 0x8b, 0xea, 0xd8, 0x56, 0xe2, 0x14, 0xe5, 0x35, 0x78, 0x8c, 0x3d,
-	0x14, 0xd7, 0x3c, 0x06, 0x8a, 0xed, 0x2c, 0x1b, 0x5a, 0xb7, 0xde, 0x25, 0x47, 0xbf, 0x73, 0x74,
-	0x3e, 0xdb, 0x1f, 0x3c, 0x5e, 0x44, 0x19, 0x2d, 0x31, 0x53, 0xe9, 0x84, 0xa9, 0x8c, 0x4f, 0xa8,
-	0xd6, 0x13, 0xad, 0x44, 0xc2, 0xca, 0x09, 0x53, 0xf2, 0x47, 0xf2, 0x13, 0xeb, 0x4c, 0x19, 0x85,
-	0xb6, 0x6b, 0x2e, 0xe3, 0x98, 0x6a, 0x8d, 0x1d, 0x33, 0x7a, 0x08, 0xe1, 0x94, 0x33, 0x25, 0x63,
-	0x74, 0x0f, 0x3a, 0x0b, 0x2a, 0x0a, 0x3e, 0x08, 0x76, 0x82, 0x71, 0x9f, 0xb8, 0x9f, 0xd1, 0xdf,
-	0x36, 0x84, 0x27, 0x16, 0x45, 0x2f, 0x60, 0xcd, 0x24, 0x29, 0x57, 0x85, 0xb1, 0x48, 0x37, 0xda,
-	0xc3, 0xd7, 0x66, 0x62, 0xc7, 0xe3, 0x4f, 0x0e, 0x26, 0xb5, 0x0b, 0x3d, 0x85, 0x4e, 0x6e, 0xa8,
-	0xc9, 0x07, 0x2d, 0x6b, 0xdf, 0xbd, 0xd9, 0x3e, 0xad, 0x50, 0xe2, 0x1c, 0xc3, 0xdf, 0x2d, 0x58,
-	0xf3, 0x79, 0xe8, 0x10, 0xee, 0x9e, 0x51, 0x19, 0xe7, 0x67, 0x74, 0xce, 0x7d, 0x93, 0x07, 0x4b,
-	0xa2, 0xdc, 0x68, 0xa4, 0xe1, 0xd1, 0x5b, 0xd8, 0x64, 0x4a, 0x4a, 0xce, 0x4c, 0xa2, 0xe4, 0x2c,
-	0x89, 0x05, 0xf7, 0x6d, 0x6e, 0x89, 0xd8, 0x68, 0x5c, 0xef, 0x62, 0xc1, 0xd1, 0x11, 0x74, 0x0b,
-	0x2d, 0x12, 0x39, 0x9f, 0x29, 0x29, 0xca, 0x41, 0x7b, 0x95, 0x0c, 0x70, 0x8e, 0x8f, 0x52, 0x94,
-	0xe8, 0x18, 0xfa, 0xb1, 0xfa, 0x25, 0x9b, 0x84, 0x3b, 0xab, 0x24, 0xf4, 0x6a, 0x4f, 0x95, 0x31,
-	0xfc, 0x00, 0x1d, 0x7b, 0x48, 0xe8, 0x11, 0x74, 0x8b, 0x9c, 0x67, 0x33, 0x97, 0x6f, 0xcf, 0x64,
-	0x9d, 0x40, 0x25, 0x7d, 0xb6, 0x0a, 0xda, 0x85, 0xbe, 0x05, 0x6a, 0xbb, 0x9d, 0x79, 0x9d, 0xf4,
-	0x2a, 0xf1, 0xb5, 0xd7, 0x46, 0xe7, 0x01, 0xf4, 0xa6, 0x65, 0x6e, 0x78, 0x7a, 0x71, 0xe1, 0xfe,
-	0xbe, 0xdc, 0x21, 0xef, 0x2f, 0xeb, 0x76, 0xc9, 0x73, 0xf5, 0xd6, 0xbe, 0xd5, 0x05, 0xf7, 0x60,
-	0x23, 0x91, 0xa7, 0xaa, 0x90, 0xf1, 0xd5, 0x8e, 0x7d, 0xaf, 0xfa, 0x9a, 0xfb, 0xb0, 0x55, 0x63,
-	0xff, 0x35, 0xdd, 0xf4, 0xfa, 0x45, 0xd9, 0x3f, 0x01, 0x84, 0xaf, 0xec, 0xfb, 0x46, 0x47, 0xd0,
-	0x11, 0x7c, 0xc1, 0xc5, 0x20, 0xd8, 0x69, 0x8f, 0xbb, 0xd1, 0x78, 0x49, 0x4d, 0x47, 0xe3, 0xf7,
-	0x15, 0xfa, 0x46, 0x9a, 0xac, 0x24, 0xce, 0x86, 0x0e, 0x21, 0xcc, 0xed, 0x08, 0xb7, 0xbc, 0xcb,
-	0xcb, 0x73, 0x12, 0x6f, 0x19, 0x7e, 0x05, 0x68, 0x12, 0xd1, 0x16, 0xb4, 0xe7, 0xbc, 0xf4, 0x1b,
-	0x54, 0x7d, 0xa2, 0x83, 0x7a, 0xab, 0x6e, 0x7e, 0x65, 0x3e, 0xd5, 0xb1, 0xcf, 0x5a, 0x4f, 0x82,
-	0xe3, 0xe7, 0x70, 0x9f, 0xa9, 0xf4, 0x7a, 0xfc, 0x24, 0xf8, 0x1e, 0xba, 0xaf, 0xf3, 0xd6, 0xf6,
-	0x97, 0x88, 0xd0, 0x6a, 0xba, 0x8c, 0xe3, 0x97, 0x5a, 0xfb, 0xa4, 0xd3, 0xd0, 0x6e, 0xfd, 0xc1,
-	0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa9, 0x9f, 0x00, 0x31, 0x1f, 0x04, 0x00, 0x00,
+	// 633 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdf, 0x4e, 0xdb, 0x30,
+	0x14, 0xc6, 0x95, 0xfe, 0x09, 0x70, 0x4a, 0x0b, 0xb2, 0x86, 0x94, 0x55, 0xda, 0x86, 0x8a, 0xd8,
+	0xe0, 0x26, 0x95, 0xca, 0xcd, 0x06, 0x1a, 0xd3, 0x60, 0xa0, 0x21, 0x6d, 0x1a, 0x72, 0xf7, 0x47,
+	0xdb, 0x4d, 0xe4, 0x26, 0xde, 0x88, 0x48, 0xec, 0xc8, 0x71, 0x80, 0x3c, 0xc1, 0xee, 0xf7, 0x0c,
+	0xbb, 0xe2, 0xa1, 0xf6, 0x2c, 0x53, 0x6c, 0xa7, 0x69, 0x27, 0xc8, 0x7a, 0x97, 0x1c, 0x7d, 0xbf,
+	0xcf, 0x3e, 0xfe, 0x7c, 0x0c, 0x4f, 0xaf, 0x46, 0x82, 0xe4, 0xae, 0xcf, 0xe3, 0xa1, 0xcf, 0x05,
+	0x1d, 0x92, 0x24, 0x19, 0x26, 0x3c, 0x0a, 0xfd, 0x7c, 0xe8, 0x73, 0xf6, 0x3d, 0xfc, 0xe1, 0x26,
+	0x82, 0x4b, 0x8e, 0x36, 0x4a, 0x9d, 0xa0, 0x2e, 0x49, 0x12, 0x57, 0x6b, 0x06, 0x8f, 0xc1, 0x1e,
+	0x53, 0x9f, 0xb3, 0x00, 0x3d, 0x80, 0xf6, 0x15, 0x89, 0x32, 0xea, 0x58, 0x9b, 0xd6, 0x4e, 0x17,
+	0xeb, 0x9f, 0xc1, 0xef, 0x25, 0xb0, 0xcf, 0x95, 0x14, 0xbd, 0x82, 0x25, 0x19, 0xc6, 0x94, 0x67,
+	0x52, 0x49, 0x3a, 0xa3, 0x6d, 0xf7, 0x4e, 0x4f, 0x57, 0xeb, 0xdd, 0x8f, 0x5a, 0x8c, 0x4b, 0x0a,
+	0xbd, 0x80, 0x76, 0x2a, 0x89, 0x4c, 0x9d, 0x86, 0xc2, 0xb7, 0xea, 0xf1, 0x71, 0x21, 0xc5, 0x9a,
+	0x40, 0x27, 0xb0, 0x32, 0x21, 0x2c, 0xb8, 0x0e, 0x03, 0x79, 0xe1, 0x34, 0x15, 0xfe, 0xac, 0x1e,
+	0x3f, 0x2a, 0xe5, 0xb8, 0x22, 0xd1, 0x5b, 0x00, 0x9f, 0x33, 0x46, 0x7d, 0x19, 0x72, 0xe6, 0xb4,
+	0x94, 0xcf, 0x4e, 0xbd, 0xcf, 0xf1, 0x54, 0x8f, 0x67, 0xd8, 0xfe, 0xaf, 0x06, 0x2c, 0x99, 0x06,
+	0xd1, 0x01, 0xac, 0x5c, 0x10, 0x16, 0xa4, 0x17, 0xe4, 0x92, 0x9a, 0xa3, 0x79, 0x74, 0x8f, 0xa9,
+	0x3e, 0x6b, 0x5c, 0xe9, 0xd1, 0x29, 0xac, 0x55, 0xb6, 0x5e, 0x18, 0x44, 0xd4, 0x69, 0x2c, 0x62,
+	0xd1, 0xab, 0xa8, 0xb3, 0x20, 0xa2, 0xe8, 0x10, 0x3a, 0x59, 0x12, 0x85, 0xec, 0xd2, 0xe3, 0x2c,
+	0xca, 0x9d, 0xe6, 0x22, 0x1e, 0xa0, 0x89, 0x0f, 0x2c, 0xca, 0xd1, 0x11, 0x74, 0x03, 0x7e, 0xcd,
+	0x2a, 0x87, 0xd6, 0x22, 0x0e, 0xab, 0x25, 0x53, 0x78, 0xf4, 0xdf, 0x43, 0x5b, 0xa5, 0x86, 0x9e,
+	0x40, 0x27, 0x4b, 0xa9, 0xf0, 0xb4, 0xbf, 0x3a, 0x93, 0x65, 0x0c, 0x45, 0xe9, 0x93, 0xaa, 0xa0,
+	0x2d, 0xe8, 0x2a, 0x41, 0x89, 0xab, 0x9e, 0x97, 0xf1, 0x6a, 0x51, 0x7c, 0x63, 0x6a, 0xfd, 0x53,
+	0x68, 0x61, 0x22, 0x29, 0xda, 0x81, 0xf5, 0x49, 0x2e, 0x69, 0xea, 0x25, 0x54, 0x78, 0xa9, 0x5a,
+	0x58, 0x59, 0xb6, 0x70, 0x4f, 0xd5, 0xcf, 0xa9, 0xa8, 0xee, 0xf0, 0x24, 0x13, 0xa9, 0x54, 0x76,
+	0x2d, 0xac, 0x7f, 0xfa, 0x3f, 0x2d, 0x58, 0x99, 0x5e, 0x07, 0xb4, 0x0f, 0xf6, 0xcc, 0xb6, 0x3a,
+	0xa3, 0x41, 0x7d, 0xfe, 0xc5, 0x0e, 0xb0, 0x21, 0xd0, 0x21, 0x2c, 0xcf, 0xed, 0x78, 0x31, 0x7a,
+	0xca, 0xf4, 0xbf, 0x00, 0x54, 0xf7, 0x09, 0x6d, 0x43, 0x2f, 0x26, 0x37, 0x9e, 0xcf, 0x99, 0x9f,
+	0x09, 0x41, 0x99, 0x34, 0xa3, 0xd7, 0x8d, 0xc9, 0xcd, 0xf1, 0xb4, 0x88, 0x06, 0x50, 0x14, 0xbc,
+	0x94, 0x67, 0xc2, 0xa7, 0x5e, 0x98, 0xa8, 0x95, 0xbb, 0xb8, 0x13, 0x93, 0x9b, 0xb1, 0xaa, 0x9d,
+	0x25, 0x83, 0x5b, 0x0b, 0x56, 0xc7, 0x79, 0x2a, 0x69, 0x3c, 0x1d, 0x56, 0x33, 0x6b, 0xba, 0xc9,
+	0xdd, 0xfb, 0x62, 0x9c, 0x61, 0xe6, 0x26, 0xae, 0xff, 0xb5, 0xcc, 0x72, 0x1b, 0x7a, 0x21, 0x9b,
+	0xf0, 0x8c, 0x05, 0xf3, 0x71, 0x76, 0x4d, 0xd5, 0x24, 0xba, 0x0b, 0xeb, 0xa5, 0xec, 0x9f, 0x50,
+	0xd7, 0x4c, 0xbd, 0xcc, 0x75, 0xf0, 0xc7, 0x02, 0xfb, 0x58, 0xbd, 0x4d, 0xe8, 0x10, 0xda, 0x11,
+	0xbd, 0xa2, 0x91, 0x63, 0x6d, 0x36, 0x6b, 0x66, 0x51, 0xab, 0xdd, 0x77, 0x85, 0xf4, 0x84, 0x49,
+	0x91, 0x63, 0x8d, 0xa1, 0x03, 0xb0, 0x53, 0xd5, 0xc2, 0x7f, 0xde, 0x94, 0xd9, 0x3e, 0xb1, 0x41,
+	0x8a, 0x34, 0x2a, 0x47, 0xb4, 0x0e, 0xcd, 0x4b, 0x9a, 0x9b, 0x08, 0x8a, 0x4f, 0xb4, 0x57, 0xbe,
+	0x88, 0xf5, 0x03, 0x69, 0x5c, 0xb5, 0x76, 0xbf, 0xf1, 0xdc, 0x3a, 0x7a, 0x09, 0x0f, 0x7d, 0x1e,
+	0xdf, 0x2d, 0x3f, 0xb7, 0xbe, 0xd9, 0xfa, 0xeb, 0xb6, 0xb1, 0xf1, 0x79, 0x84, 0x49, 0xd1, 0x9d,
+	0xa0, 0xee, 0xeb, 0x24, 0x31, 0x4e, 0x13, 0x5b, 0xbd, 0xd8, 0x7b, 0x7f, 0x03, 0x00, 0x00, 0xff,
+	0xff, 0x40, 0xd0, 0xb3, 0x88, 0xdb, 0x05, 0x00, 0x00,
 }
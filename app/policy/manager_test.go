@@ -35,3 +35,34 @@ func TestPolicy(t *testing.T) {
 	p1 := manager.ForLevel(1)
 	assert(p1.Timeouts.Handshake, Equals, pDefault.Timeouts.Handshake)
 }
+
+func TestPolicyBandwidth(t *testing.T) {
+	assert := With(t)
+
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			0: {
+				Bandwidth: &Policy_Bandwidth{
+					Uplink: &Policy_Rate{
+						BytesPerSecond: 1024,
+					},
+					Downlink: &Policy_Rate{
+						BytesPerSecond: 2048,
+						Burst:          8192,
+					},
+				},
+			},
+		},
+	})
+	assert(err, IsNil)
+
+	p0 := manager.ForLevel(0)
+	assert(p0.Bandwidth.Uplink.BytesPerSecond, Equals, uint64(1024))
+	assert(p0.Bandwidth.Uplink.Burst, Equals, uint64(1024))
+	assert(p0.Bandwidth.Downlink.BytesPerSecond, Equals, uint64(2048))
+	assert(p0.Bandwidth.Downlink.Burst, Equals, uint64(8192))
+
+	p1 := manager.ForLevel(1)
+	assert(p1.Bandwidth.Uplink.IsLimited(), IsFalse)
+	assert(p1.Bandwidth.Downlink.IsLimited(), IsFalse)
+}
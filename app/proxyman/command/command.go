@@ -6,6 +6,7 @@ import (
 	grpc "google.golang.org/grpc"
 	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
 	"v2ray.com/core/proxy"
 )
 
@@ -21,6 +22,15 @@ type OutboundOperation interface {
 	ApplyOutbound(context.Context, core.OutboundHandler) error
 }
 
+// maxExternalPorts is the max number of ports that SetPortsOperation assigns to a handler at once.
+const maxExternalPorts = 1024
+
+// ExternalPortHandler is an inbound handler whose ports are assigned externally.
+type ExternalPortHandler interface {
+	// SetPorts replaces the ports that the handler listens on.
+	SetPorts(ctx context.Context, ports []net.Port) error
+}
+
 func getInbound(handler core.InboundHandler) (proxy.Inbound, error) {
 	gi, ok := handler.(proxy.GetInbound)
 	if !ok {
@@ -55,6 +65,27 @@ func (op *RemoveUserOperation) ApplyInbound(ctx context.Context, handler core.In
 	return um.RemoveUser(ctx, op.Email)
 }
 
+// ApplyInbound implements InboundOperation.
+func (op *SetPortsOperation) ApplyInbound(ctx context.Context, handler core.InboundHandler) error {
+	ph, ok := handler.(ExternalPortHandler)
+	if !ok {
+		return newError("handler doesn't support external port allocation")
+	}
+	var ports []net.Port
+	for _, pr := range op.Ports {
+		if pr.From == 0 || pr.From > pr.To || pr.To > 65535 {
+			return newError("invalid port range: ", pr.From, "-", pr.To)
+		}
+		if len(ports)+int(pr.To-pr.From+1) > maxExternalPorts {
+			return newError("too many ports, at most ", maxExternalPorts, " are allowed")
+		}
+		for port := pr.From; port <= pr.To; port++ {
+			ports = append(ports, net.Port(port))
+		}
+	}
+	return ph.SetPorts(ctx, ports)
+}
+
 type handlerServer struct {
 	s   *core.Instance
 	ihm core.InboundHandlerManager
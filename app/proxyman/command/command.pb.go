@@ -3,6 +3,7 @@ package command
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_protocol "v2ray.com/core/common/protocol"
 import v2ray_core_common_serial "v2ray.com/core/common/serial"
 import v2ray_core "v2ray.com/core"
@@ -56,6 +57,24 @@ func (m *RemoveUserOperation) GetEmail() string {
 	return ""
 }
 
+// SetPortsOperation replaces the ports of an inbound handler with External
+// allocation strategy.
+type SetPortsOperation struct {
+	Ports []*v2ray_core_common_net.PortRange `protobuf:"bytes,1,rep,name=ports" json:"ports,omitempty"`
+}
+
+func (m *SetPortsOperation) Reset()                    { *m = SetPortsOperation{} }
+func (m *SetPortsOperation) String() string            { return proto.CompactTextString(m) }
+func (*SetPortsOperation) ProtoMessage()               {}
+func (*SetPortsOperation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *SetPortsOperation) GetPorts() []*v2ray_core_common_net.PortRange {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
 type AddInboundRequest struct {
 	Inbound *v2ray_core.InboundHandlerConfig `protobuf:"bytes,1,opt,name=inbound" json:"inbound,omitempty"`
 }
@@ -63,7 +82,7 @@ type AddInboundRequest struct {
 func (m *AddInboundRequest) Reset()                    { *m = AddInboundRequest{} }
 func (m *AddInboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*AddInboundRequest) ProtoMessage()               {}
-func (*AddInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*AddInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *AddInboundRequest) GetInbound() *v2ray_core.InboundHandlerConfig {
 	if m != nil {
@@ -78,7 +97,7 @@ type AddInboundResponse struct {
 func (m *AddInboundResponse) Reset()                    { *m = AddInboundResponse{} }
 func (m *AddInboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*AddInboundResponse) ProtoMessage()               {}
-func (*AddInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*AddInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 type RemoveInboundRequest struct {
 	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
@@ -87,7 +106,7 @@ type RemoveInboundRequest struct {
 func (m *RemoveInboundRequest) Reset()                    { *m = RemoveInboundRequest{} }
 func (m *RemoveInboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*RemoveInboundRequest) ProtoMessage()               {}
-func (*RemoveInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*RemoveInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *RemoveInboundRequest) GetTag() string {
 	if m != nil {
@@ -102,7 +121,7 @@ type RemoveInboundResponse struct {
 func (m *RemoveInboundResponse) Reset()                    { *m = RemoveInboundResponse{} }
 func (m *RemoveInboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*RemoveInboundResponse) ProtoMessage()               {}
-func (*RemoveInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*RemoveInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 type AlterInboundRequest struct {
 	Tag       string                                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
@@ -112,7 +131,7 @@ type AlterInboundRequest struct {
 func (m *AlterInboundRequest) Reset()                    { *m = AlterInboundRequest{} }
 func (m *AlterInboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*AlterInboundRequest) ProtoMessage()               {}
-func (*AlterInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*AlterInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *AlterInboundRequest) GetTag() string {
 	if m != nil {
@@ -134,7 +153,7 @@ type AlterInboundResponse struct {
 func (m *AlterInboundResponse) Reset()                    { *m = AlterInboundResponse{} }
 func (m *AlterInboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*AlterInboundResponse) ProtoMessage()               {}
-func (*AlterInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*AlterInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 type AddOutboundRequest struct {
 	Outbound *v2ray_core.OutboundHandlerConfig `protobuf:"bytes,1,opt,name=outbound" json:"outbound,omitempty"`
@@ -143,7 +162,7 @@ type AddOutboundRequest struct {
 func (m *AddOutboundRequest) Reset()                    { *m = AddOutboundRequest{} }
 func (m *AddOutboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*AddOutboundRequest) ProtoMessage()               {}
-func (*AddOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*AddOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *AddOutboundRequest) GetOutbound() *v2ray_core.OutboundHandlerConfig {
 	if m != nil {
@@ -158,7 +177,7 @@ type AddOutboundResponse struct {
 func (m *AddOutboundResponse) Reset()                    { *m = AddOutboundResponse{} }
 func (m *AddOutboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*AddOutboundResponse) ProtoMessage()               {}
-func (*AddOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*AddOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 type RemoveOutboundRequest struct {
 	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
@@ -167,7 +186,7 @@ type RemoveOutboundRequest struct {
 func (m *RemoveOutboundRequest) Reset()                    { *m = RemoveOutboundRequest{} }
 func (m *RemoveOutboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*RemoveOutboundRequest) ProtoMessage()               {}
-func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *RemoveOutboundRequest) GetTag() string {
 	if m != nil {
@@ -182,7 +201,7 @@ type RemoveOutboundResponse struct {
 func (m *RemoveOutboundResponse) Reset()                    { *m = RemoveOutboundResponse{} }
 func (m *RemoveOutboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*RemoveOutboundResponse) ProtoMessage()               {}
-func (*RemoveOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*RemoveOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 type AlterOutboundRequest struct {
 	Tag       string                                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
@@ -192,7 +211,7 @@ type AlterOutboundRequest struct {
 func (m *AlterOutboundRequest) Reset()                    { *m = AlterOutboundRequest{} }
 func (m *AlterOutboundRequest) String() string            { return proto.CompactTextString(m) }
 func (*AlterOutboundRequest) ProtoMessage()               {}
-func (*AlterOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*AlterOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *AlterOutboundRequest) GetTag() string {
 	if m != nil {
@@ -214,7 +233,7 @@ type AlterOutboundResponse struct {
 func (m *AlterOutboundResponse) Reset()                    { *m = AlterOutboundResponse{} }
 func (m *AlterOutboundResponse) String() string            { return proto.CompactTextString(m) }
 func (*AlterOutboundResponse) ProtoMessage()               {}
-func (*AlterOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*AlterOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 type Config struct {
 }
@@ -222,11 +241,12 @@ type Config struct {
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func init() {
 	proto.RegisterType((*AddUserOperation)(nil), "v2ray.core.app.proxyman.command.AddUserOperation")
 	proto.RegisterType((*RemoveUserOperation)(nil), "v2ray.core.app.proxyman.command.RemoveUserOperation")
+	proto.RegisterType((*SetPortsOperation)(nil), "v2ray.core.app.proxyman.command.SetPortsOperation")
 	proto.RegisterType((*AddInboundRequest)(nil), "v2ray.core.app.proxyman.command.AddInboundRequest")
 	proto.RegisterType((*AddInboundResponse)(nil), "v2ray.core.app.proxyman.command.AddInboundResponse")
 	proto.RegisterType((*RemoveInboundRequest)(nil), "v2ray.core.app.proxyman.command.RemoveInboundRequest")
@@ -482,40 +502,43 @@ var _HandlerService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("v2ray.com/core/app/proxyman/command/command.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 557 bytes of a gzipped FileDescriptorProto
+	// 602 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdf, 0x6b, 0xd3, 0x40,
-	0x1c, 0xb7, 0x53, 0xbb, 0xed, 0x3b, 0x1d, 0xf3, 0xda, 0x6e, 0x25, 0x3e, 0x6c, 0x46, 0x90, 0x0d,
-	0xe1, 0xa2, 0x59, 0x37, 0x41, 0xf0, 0xa1, 0xd6, 0x87, 0xf9, 0x20, 0x1d, 0x99, 0xfa, 0xe0, 0x8b,
-	0xdc, 0x92, 0xb3, 0x04, 0x92, 0xbb, 0xf3, 0x92, 0x56, 0x2b, 0x08, 0x82, 0xff, 0x80, 0x7f, 0x87,
-	0x7f, 0xa5, 0x24, 0x77, 0xd7, 0x25, 0x69, 0x21, 0x0d, 0xf8, 0xd4, 0xf4, 0xfa, 0xf9, 0xf5, 0xfd,
-	0xde, 0x27, 0x14, 0x9e, 0xcf, 0x5c, 0x49, 0xe6, 0xd8, 0xe7, 0xb1, 0xe3, 0x73, 0x49, 0x1d, 0x22,
-	0x84, 0x23, 0x24, 0xff, 0x3e, 0x8f, 0x09, 0x73, 0x7c, 0x1e, 0xc7, 0x84, 0x05, 0xe6, 0x13, 0x0b,
-	0xc9, 0x53, 0x8e, 0x0e, 0x0d, 0x45, 0x52, 0x4c, 0x84, 0xc0, 0x06, 0x8e, 0x35, 0xcc, 0x3a, 0xa9,
-	0x68, 0x66, 0xe7, 0x9c, 0x39, 0x39, 0xdb, 0xe7, 0x91, 0x33, 0x4d, 0xa8, 0x54, 0x5a, 0xd6, 0xb3,
-	0xd5, 0xd0, 0x84, 0xca, 0x90, 0x44, 0x4e, 0x3a, 0x17, 0x34, 0xf8, 0x1c, 0xd3, 0x24, 0x21, 0x13,
-	0xaa, 0x19, 0x0f, 0x97, 0x18, 0xec, 0x4b, 0x38, 0x51, 0x3f, 0xda, 0x17, 0xb0, 0x37, 0x0c, 0x82,
-	0x0f, 0x09, 0x95, 0x63, 0x41, 0x25, 0x49, 0x43, 0xce, 0xd0, 0x00, 0xee, 0x64, 0x86, 0xfd, 0xd6,
-	0x51, 0xeb, 0x78, 0xc7, 0x3d, 0xc2, 0x85, 0xf4, 0xca, 0x0d, 0x9b, 0x60, 0x38, 0x23, 0x7a, 0x39,
-	0xda, 0x7e, 0x0a, 0x1d, 0x8f, 0xc6, 0x7c, 0x46, 0xcb, 0x62, 0x5d, 0xb8, 0x4b, 0x63, 0x12, 0x46,
-	0xb9, 0xda, 0xb6, 0xa7, 0xbe, 0xd8, 0x63, 0x78, 0x30, 0x0c, 0x82, 0xb7, 0xec, 0x9a, 0x4f, 0x59,
-	0xe0, 0xd1, 0xaf, 0x53, 0x9a, 0xa4, 0xe8, 0x25, 0x6c, 0x86, 0xea, 0x64, 0x95, 0xb5, 0x06, 0x5f,
-	0x10, 0x16, 0x44, 0x54, 0x8e, 0xf2, 0x21, 0x3c, 0x43, 0xb0, 0xbb, 0x80, 0x8a, 0x82, 0x89, 0xe0,
-	0x2c, 0xa1, 0xf6, 0x31, 0x74, 0x55, 0xa6, 0x8a, 0xd3, 0x1e, 0xdc, 0x4e, 0xc9, 0x44, 0x47, 0xca,
-	0x1e, 0xed, 0x03, 0xe8, 0x55, 0x90, 0x5a, 0x22, 0x86, 0xce, 0x30, 0x4a, 0xa9, 0xac, 0x53, 0x40,
-	0x6f, 0x60, 0x9b, 0x9b, 0xa9, 0xfb, 0x1b, 0x79, 0xfe, 0x27, 0x2b, 0x56, 0xa7, 0x2e, 0x0a, 0xbf,
-	0xcf, 0x2e, 0xea, 0x9d, 0xba, 0x27, 0xef, 0x86, 0x68, 0xef, 0x43, 0xb7, 0x6c, 0xa7, 0x63, 0x5c,
-	0xe5, 0xf3, 0x8d, 0xa7, 0x69, 0x29, 0xc5, 0x2b, 0xd8, 0xe2, 0xfa, 0x48, 0xaf, 0xec, 0x51, 0xd1,
-	0xd2, 0xc0, 0xcb, 0x3b, 0x5b, 0x50, 0xec, 0x1e, 0x74, 0x4a, 0xa2, 0xda, 0xeb, 0xc4, 0xec, 0xa2,
-	0x6a, 0xb7, 0xbc, 0xb6, 0x3e, 0xec, 0x57, 0xa1, 0x5a, 0x84, 0xe9, 0x41, 0x6a, 0x35, 0xfe, 0xd3,
-	0xe2, 0x0e, 0xa0, 0x57, 0xf1, 0xd3, 0x41, 0xb6, 0xa0, 0xad, 0x06, 0x77, 0xff, 0xb4, 0x61, 0x57,
-	0xaf, 0xe2, 0x8a, 0xca, 0x59, 0xe8, 0x53, 0xf4, 0x0d, 0xe0, 0xa6, 0x36, 0xc8, 0xc5, 0x35, 0x2f,
-	0x2a, 0x5e, 0x2a, 0xad, 0x75, 0xda, 0x88, 0xa3, 0x33, 0xdd, 0x42, 0xbf, 0x5a, 0x70, 0xbf, 0x54,
-	0x38, 0x74, 0x56, 0x2b, 0xb4, 0xaa, 0xca, 0xd6, 0x79, 0x53, 0xda, 0x22, 0xc2, 0x4f, 0xb8, 0x57,
-	0xac, 0x1a, 0x1a, 0xd4, 0x4f, 0xb2, 0xfc, 0x22, 0x58, 0x67, 0x0d, 0x59, 0x0b, 0xfb, 0x1f, 0xb0,
-	0x53, 0x28, 0x1f, 0x5a, 0x6b, 0x8f, 0x95, 0x32, 0x59, 0x83, 0x66, 0xa4, 0x85, 0xf7, 0xef, 0x16,
-	0xec, 0x96, 0x7b, 0x8b, 0xd6, 0xdd, 0x63, 0x35, 0xc2, 0x8b, 0xc6, 0xbc, 0x52, 0x07, 0x4a, 0x9d,
-	0x45, 0x6b, 0x2e, 0xb3, 0x9a, 0xe1, 0xbc, 0x29, 0xcd, 0x44, 0x78, 0xed, 0xc1, 0x63, 0x9f, 0xc7,
-	0x75, 0xf4, 0xcb, 0xd6, 0xa7, 0x4d, 0xfd, 0xf8, 0x77, 0xe3, 0xf0, 0xa3, 0xeb, 0x91, 0x39, 0x1e,
-	0x65, 0xe0, 0xa1, 0x10, 0xf8, 0xd2, 0x80, 0x47, 0x0a, 0x71, 0xdd, 0xce, 0xff, 0x1d, 0x4e, 0xff,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0x2f, 0x05, 0xaa, 0x44, 0x29, 0x07, 0x00, 0x00,
+	0x1c, 0x37, 0x9b, 0xeb, 0xb6, 0xef, 0x74, 0x6c, 0xd7, 0x76, 0x2b, 0xf1, 0x61, 0x35, 0x8a, 0x6c,
+	0x08, 0x17, 0xed, 0xba, 0x0a, 0x82, 0x0f, 0xb5, 0x3e, 0x4c, 0x44, 0x5a, 0x52, 0xf5, 0xc1, 0x17,
+	0xb9, 0x25, 0x67, 0x09, 0x34, 0x77, 0xe7, 0xe5, 0x5a, 0xad, 0x20, 0x08, 0xfe, 0x03, 0xfe, 0x1d,
+	0xfe, 0x95, 0x92, 0xe4, 0xd2, 0x36, 0x69, 0x25, 0x0d, 0xf8, 0xb4, 0xec, 0xf2, 0xf9, 0xf1, 0xfd,
+	0x7e, 0xee, 0xd3, 0x16, 0x9e, 0x4e, 0x5b, 0x92, 0xcc, 0xb0, 0xcb, 0x03, 0xdb, 0xe5, 0x92, 0xda,
+	0x44, 0x08, 0x5b, 0x48, 0xfe, 0x6d, 0x16, 0x10, 0x66, 0xbb, 0x3c, 0x08, 0x08, 0xf3, 0xd2, 0xbf,
+	0x58, 0x48, 0xae, 0x38, 0x3a, 0x4b, 0x29, 0x92, 0x62, 0x22, 0x04, 0x4e, 0xe1, 0x58, 0xc3, 0xcc,
+	0x87, 0x39, 0xcd, 0xe8, 0x9c, 0x33, 0x9b, 0x51, 0x65, 0x0b, 0x2e, 0x55, 0x22, 0x63, 0x5e, 0xac,
+	0x47, 0xc5, 0x2f, 0x5d, 0x3e, 0xb6, 0x27, 0x21, 0x95, 0x1a, 0xfa, 0x64, 0x3d, 0x34, 0xa4, 0xd2,
+	0x27, 0x63, 0x5b, 0xcd, 0x04, 0xf5, 0x3e, 0x05, 0x34, 0x0c, 0xc9, 0x88, 0x6a, 0xc6, 0xbd, 0x15,
+	0x06, 0xfb, 0xec, 0x8f, 0x92, 0x97, 0xd6, 0x35, 0x1c, 0x75, 0x3d, 0xef, 0x7d, 0x48, 0x65, 0x5f,
+	0x50, 0x49, 0x94, 0xcf, 0x19, 0x6a, 0xc3, 0xed, 0xc8, 0xb0, 0x61, 0x34, 0x8d, 0xf3, 0x83, 0x56,
+	0x13, 0x2f, 0xed, 0x98, 0xb8, 0xe1, 0x74, 0x30, 0x1c, 0x11, 0x9d, 0x18, 0x6d, 0x3d, 0x86, 0xaa,
+	0x43, 0x03, 0x3e, 0xa5, 0x59, 0xb1, 0x1a, 0xec, 0xd0, 0x80, 0xf8, 0xe3, 0x58, 0x6d, 0xdf, 0x49,
+	0xfe, 0xb1, 0xde, 0xc0, 0xf1, 0x90, 0xaa, 0x01, 0x97, 0x2a, 0x5c, 0x40, 0x3b, 0xb0, 0x13, 0x65,
+	0x12, 0x36, 0x8c, 0xe6, 0xf6, 0x3f, 0x8c, 0x19, 0x55, 0x38, 0x62, 0x39, 0x84, 0x8d, 0xa8, 0x93,
+	0xc0, 0xad, 0x3e, 0x1c, 0x77, 0x3d, 0xef, 0x35, 0xbb, 0xe1, 0x13, 0xe6, 0x39, 0xf4, 0xcb, 0x84,
+	0x86, 0x0a, 0x3d, 0x87, 0x5d, 0x3f, 0x39, 0x59, 0xb7, 0x87, 0x06, 0x5f, 0x13, 0xe6, 0x8d, 0xa9,
+	0xec, 0xc5, 0x89, 0x38, 0x29, 0xc1, 0xaa, 0x01, 0x5a, 0x16, 0x0c, 0x05, 0x67, 0x21, 0xb5, 0xce,
+	0xa1, 0x96, 0x2c, 0x98, 0x73, 0x3a, 0x82, 0x6d, 0x45, 0x46, 0x7a, 0xbf, 0xe8, 0xd1, 0x3a, 0x85,
+	0x7a, 0x0e, 0xa9, 0x25, 0x02, 0xa8, 0x76, 0xc7, 0x8a, 0xca, 0x22, 0x05, 0xf4, 0x0a, 0xf6, 0x79,
+	0x9a, 0x4b, 0x63, 0x2b, 0x9e, 0xff, 0xd1, 0x9a, 0x38, 0x92, 0x5b, 0xc7, 0xef, 0xa2, 0x5b, 0x7f,
+	0x9b, 0x5c, 0xba, 0xb3, 0x20, 0x5a, 0x27, 0x50, 0xcb, 0xda, 0xe9, 0x31, 0x86, 0xf1, 0x7e, 0xfd,
+	0x89, 0xca, 0x4c, 0xf1, 0x02, 0xf6, 0xb8, 0x3e, 0xd2, 0x91, 0xdd, 0x5f, 0xb6, 0x4c, 0xe1, 0xd9,
+	0xcc, 0xe6, 0x14, 0xab, 0x0e, 0xd5, 0x8c, 0xa8, 0xf6, 0xba, 0x48, 0xb3, 0xc8, 0xdb, 0xad, 0xc6,
+	0xd6, 0x80, 0x93, 0x3c, 0x54, 0x8b, 0x30, 0xbd, 0x48, 0xa1, 0xc6, 0x7f, 0x0a, 0xee, 0x14, 0xea,
+	0x39, 0x3f, 0x3d, 0xc8, 0x1e, 0x54, 0x92, 0xc5, 0x5b, 0xbf, 0x2b, 0x70, 0xa8, 0xa3, 0x18, 0x52,
+	0x39, 0xf5, 0x5d, 0x8a, 0xbe, 0x02, 0x2c, 0x6a, 0x83, 0x5a, 0xb8, 0xe0, 0xbb, 0x01, 0xaf, 0x94,
+	0xd6, 0xbc, 0x2c, 0xc5, 0xd1, 0x33, 0xdd, 0x42, 0x3f, 0x0d, 0xb8, 0x9b, 0x29, 0x1c, 0xba, 0x2a,
+	0x14, 0x5a, 0x57, 0x65, 0xb3, 0x53, 0x96, 0x36, 0x1f, 0xe1, 0x07, 0xdc, 0x59, 0xae, 0x1a, 0x6a,
+	0x17, 0x6f, 0xb2, 0xfa, 0x41, 0x30, 0xaf, 0x4a, 0xb2, 0xe6, 0xf6, 0xdf, 0xe1, 0x60, 0xa9, 0x7c,
+	0x68, 0xa3, 0x1c, 0x73, 0x65, 0x32, 0xdb, 0xe5, 0x48, 0x73, 0xef, 0x5f, 0x06, 0x1c, 0x66, 0x7b,
+	0x8b, 0x36, 0xcd, 0x31, 0x3f, 0xc2, 0xb3, 0xd2, 0xbc, 0x4c, 0x07, 0x32, 0x9d, 0x45, 0x1b, 0x86,
+	0x99, 0x9f, 0xa1, 0x53, 0x96, 0x96, 0x8e, 0xf0, 0xd2, 0x81, 0x07, 0x2e, 0x0f, 0x8a, 0xe8, 0x03,
+	0xe3, 0xe3, 0xae, 0x7e, 0xfc, 0xb3, 0x75, 0xf6, 0xa1, 0xe5, 0x90, 0x19, 0xee, 0x45, 0xe0, 0xae,
+	0x10, 0x78, 0x90, 0x82, 0x7b, 0x09, 0xe2, 0xa6, 0x12, 0xff, 0xd4, 0x5c, 0xfe, 0x0d, 0x00, 0x00,
+	0xff, 0xff, 0xe5, 0x9c, 0x3a, 0x51, 0x9c, 0x07, 0x00, 0x00,
 }
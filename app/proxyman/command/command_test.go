@@ -0,0 +1,68 @@
+package command_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	. "v2ray.com/core/app/proxyman/command"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/ext/assert"
+)
+
+type portHandler struct {
+	ports []net.Port
+}
+
+func (h *portHandler) Start() error { return nil }
+func (h *portHandler) Close() error { return nil }
+func (h *portHandler) Tag() string  { return "external" }
+
+func (h *portHandler) GetRandomInboundProxy() (interface{}, net.Port, int) {
+	return nil, 0, 0
+}
+
+func (h *portHandler) SetPorts(ctx context.Context, ports []net.Port) error {
+	h.ports = ports
+	return nil
+}
+
+func TestSetPortsOperation(t *testing.T) {
+	assert := With(t)
+
+	handler := &portHandler{}
+	op := &SetPortsOperation{
+		Ports: []*net.PortRange{
+			{From: 1000, To: 1002},
+			{From: 2000, To: 2000},
+		},
+	}
+	assert(op.ApplyInbound(context.Background(), handler), IsNil)
+	assert(len(handler.ports), Equals, 4)
+	assert(handler.ports[3], Equals, net.Port(2000))
+
+	for _, pr := range []*net.PortRange{
+		{From: 2000, To: 1000},
+		{From: 65535, To: 65536},
+		{From: 1, To: math.MaxUint32},
+		{From: 0, To: 10},
+		{From: 1, To: 65535},
+	} {
+		handler := &portHandler{}
+		op := &SetPortsOperation{
+			Ports: []*net.PortRange{pr},
+		}
+		assert(op.ApplyInbound(context.Background(), handler), IsNotNil)
+		assert(len(handler.ports), Equals, 0)
+	}
+
+	handler = &portHandler{}
+	op = &SetPortsOperation{
+		Ports: []*net.PortRange{
+			{From: 10000, To: 10600},
+			{From: 20000, To: 20600},
+		},
+	}
+	assert(op.ApplyInbound(context.Background(), handler), IsNotNil)
+	assert(len(handler.ports), Equals, 0)
+}
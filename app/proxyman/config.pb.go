@@ -7,6 +7,7 @@ import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_net1 "v2ray.com/core/common/net"
 import v2ray_core_transport_internet "v2ray.com/core/transport/internet"
 import v2ray_core_common_serial "v2ray.com/core/common/serial"
+import v2ray_core_app_router "v2ray.com/core/app/router"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -47,7 +48,8 @@ const (
 	AllocationStrategy_Always AllocationStrategy_Type = 0
 	// Randomly allocate specific range of handlers.
 	AllocationStrategy_Random AllocationStrategy_Type = 1
-	// External. Not supported yet.
+	// Ports are assigned by an external controller, through
+	// HandlerService.AlterInbound with a SetPortsOperation, or in port_file.
 	AllocationStrategy_External AllocationStrategy_Type = 2
 )
 
@@ -67,6 +69,35 @@ func (x AllocationStrategy_Type) String() string {
 }
 func (AllocationStrategy_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
 
+type ReceiverConfig_ProxyProtocol int32
+
+const (
+	// PROXY protocol headers are not accepted.
+	ReceiverConfig_Disabled ReceiverConfig_ProxyProtocol = 0
+	// Connections may start with a PROXY protocol v1 or v2 header.
+	ReceiverConfig_Optional ReceiverConfig_ProxyProtocol = 1
+	// Connections must start with a PROXY protocol v1 or v2 header.
+	ReceiverConfig_Required ReceiverConfig_ProxyProtocol = 2
+)
+
+var ReceiverConfig_ProxyProtocol_name = map[int32]string{
+	0: "Disabled",
+	1: "Optional",
+	2: "Required",
+}
+var ReceiverConfig_ProxyProtocol_value = map[string]int32{
+	"Disabled": 0,
+	"Optional": 1,
+	"Required": 2,
+}
+
+func (x ReceiverConfig_ProxyProtocol) String() string {
+	return proto.EnumName(ReceiverConfig_ProxyProtocol_name, int32(x))
+}
+func (ReceiverConfig_ProxyProtocol) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{2, 0}
+}
+
 type InboundConfig struct {
 }
 
@@ -83,6 +114,10 @@ type AllocationStrategy struct {
 	// Number of minutes before a handler is regenerated.
 	// Default value is 5 if unset.
 	Refresh *AllocationStrategy_AllocationStrategyRefresh `protobuf:"bytes,3,opt,name=refresh" json:"refresh,omitempty"`
+	// Path of a file that lists the ports to listen on, for External strategy.
+	// Each line is either a port or a range like "10000-10010". The file is
+	// reloaded when it changes.
+	PortFile string `protobuf:"bytes,4,opt,name=port_file,json=portFile" json:"port_file,omitempty"`
 }
 
 func (m *AllocationStrategy) Reset()                    { *m = AllocationStrategy{} }
@@ -111,6 +146,13 @@ func (m *AllocationStrategy) GetRefresh() *AllocationStrategy_AllocationStrategy
 	return nil
 }
 
+func (m *AllocationStrategy) GetPortFile() string {
+	if m != nil {
+		return m.PortFile
+	}
+	return ""
+}
+
 type AllocationStrategy_AllocationStrategyConcurrency struct {
 	Value uint32 `protobuf:"varint,1,opt,name=value" json:"value,omitempty"`
 }
@@ -164,6 +206,19 @@ type ReceiverConfig struct {
 	StreamSettings             *v2ray_core_transport_internet.StreamConfig `protobuf:"bytes,4,opt,name=stream_settings,json=streamSettings" json:"stream_settings,omitempty"`
 	ReceiveOriginalDestination bool                                        `protobuf:"varint,5,opt,name=receive_original_destination,json=receiveOriginalDestination" json:"receive_original_destination,omitempty"`
 	DomainOverride             []KnownProtocols                            `protobuf:"varint,7,rep,packed,name=domain_override,json=domainOverride,enum=v2ray.core.app.proxyman.KnownProtocols" json:"domain_override,omitempty"`
+	// Whether the original source address of TCP based connections is taken
+	// from PROXY protocol headers, as sent by HAProxy or load balancers.
+	ProxyProtocol ReceiverConfig_ProxyProtocol `protobuf:"varint,8,opt,name=proxy_protocol,json=proxyProtocol,enum=v2ray.core.app.proxyman.ReceiverConfig_ProxyProtocol" json:"proxy_protocol,omitempty"`
+	// If not empty, only connections from these source IPs are accepted.
+	AllowedSource []*v2ray_core_app_router.CIDR `protobuf:"bytes,9,rep,name=allowed_source,json=allowedSource" json:"allowed_source,omitempty"`
+	// Connections from these source IPs are rejected.
+	DeniedSource []*v2ray_core_app_router.CIDR `protobuf:"bytes,10,rep,name=denied_source,json=deniedSource" json:"denied_source,omitempty"`
+	// Temporarily bans source IPs that fail to authenticate too many times.
+	AuthFailureBan *AuthFailureBan `protobuf:"bytes,11,opt,name=auth_failure_ban,json=authFailureBan" json:"auth_failure_ban,omitempty"`
+	// Peers that PROXY protocol headers are accepted from, such as load balancers. If empty, only loopback peers
+	// are trusted. Headers from other peers are not read, and their connections are rejected if PROXY protocol
+	// is required.
+	ProxyProtocolTrusted []*v2ray_core_app_router.CIDR `protobuf:"bytes,12,rep,name=proxy_protocol_trusted,json=proxyProtocolTrusted" json:"proxy_protocol_trusted,omitempty"`
 }
 
 func (m *ReceiverConfig) Reset()                    { *m = ReceiverConfig{} }
@@ -213,6 +268,77 @@ func (m *ReceiverConfig) GetDomainOverride() []KnownProtocols {
 	return nil
 }
 
+func (m *ReceiverConfig) GetProxyProtocol() ReceiverConfig_ProxyProtocol {
+	if m != nil {
+		return m.ProxyProtocol
+	}
+	return ReceiverConfig_Disabled
+}
+
+func (m *ReceiverConfig) GetAllowedSource() []*v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.AllowedSource
+	}
+	return nil
+}
+
+func (m *ReceiverConfig) GetDeniedSource() []*v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.DeniedSource
+	}
+	return nil
+}
+
+func (m *ReceiverConfig) GetAuthFailureBan() *AuthFailureBan {
+	if m != nil {
+		return m.AuthFailureBan
+	}
+	return nil
+}
+
+func (m *ReceiverConfig) GetProxyProtocolTrusted() []*v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.ProxyProtocolTrusted
+	}
+	return nil
+}
+
+type AuthFailureBan struct {
+	// Number of authentication failures after which a source IP is banned.
+	// Disabled if 0.
+	MaxFailures uint32 `protobuf:"varint,1,opt,name=max_failures,json=maxFailures" json:"max_failures,omitempty"`
+	// Seconds in which authentication failures are counted. Default to 600 if 0.
+	Window uint32 `protobuf:"varint,2,opt,name=window" json:"window,omitempty"`
+	// Seconds for which a source IP is banned. Default to 3600 if 0.
+	Duration uint32 `protobuf:"varint,3,opt,name=duration" json:"duration,omitempty"`
+}
+
+func (m *AuthFailureBan) Reset()                    { *m = AuthFailureBan{} }
+func (m *AuthFailureBan) String() string            { return proto.CompactTextString(m) }
+func (*AuthFailureBan) ProtoMessage()               {}
+func (*AuthFailureBan) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *AuthFailureBan) GetMaxFailures() uint32 {
+	if m != nil {
+		return m.MaxFailures
+	}
+	return 0
+}
+
+func (m *AuthFailureBan) GetWindow() uint32 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+func (m *AuthFailureBan) GetDuration() uint32 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
 type InboundHandlerConfig struct {
 	Tag              string                                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 	ReceiverSettings *v2ray_core_common_serial.TypedMessage `protobuf:"bytes,2,opt,name=receiver_settings,json=receiverSettings" json:"receiver_settings,omitempty"`
@@ -222,7 +348,7 @@ type InboundHandlerConfig struct {
 func (m *InboundHandlerConfig) Reset()                    { *m = InboundHandlerConfig{} }
 func (m *InboundHandlerConfig) String() string            { return proto.CompactTextString(m) }
 func (*InboundHandlerConfig) ProtoMessage()               {}
-func (*InboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*InboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *InboundHandlerConfig) GetTag() string {
 	if m != nil {
@@ -251,7 +377,7 @@ type OutboundConfig struct {
 func (m *OutboundConfig) Reset()                    { *m = OutboundConfig{} }
 func (m *OutboundConfig) String() string            { return proto.CompactTextString(m) }
 func (*OutboundConfig) ProtoMessage()               {}
-func (*OutboundConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*OutboundConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 type SenderConfig struct {
 	// Send traffic through the given IP. Only IP is allowed.
@@ -264,7 +390,7 @@ type SenderConfig struct {
 func (m *SenderConfig) Reset()                    { *m = SenderConfig{} }
 func (m *SenderConfig) String() string            { return proto.CompactTextString(m) }
 func (*SenderConfig) ProtoMessage()               {}
-func (*SenderConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*SenderConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *SenderConfig) GetVia() *v2ray_core_common_net.IPOrDomain {
 	if m != nil {
@@ -299,12 +425,22 @@ type MultiplexingConfig struct {
 	Enabled bool `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
 	// Max number of concurrent connections that one Mux connection can handle.
 	Concurrency uint32 `protobuf:"varint,2,opt,name=concurrency" json:"concurrency,omitempty"`
+	// Seconds after which a Mux connection stops taking new connections, and
+	// closes when existing ones finish. Unlimited if 0.
+	MaxLifetime uint32 `protobuf:"varint,3,opt,name=max_lifetime,json=maxLifetime" json:"max_lifetime,omitempty"`
+	// Max number of connections that one Mux connection handles in total,
+	// before it retires the same way as in max_lifetime. Default to 128 if 0.
+	MaxReuse uint32 `protobuf:"varint,4,opt,name=max_reuse,json=maxReuse" json:"max_reuse,omitempty"`
+	// Seconds of idleness after which a keepalive frame is sent. The Mux
+	// connection is closed if the peer has answered keepalive frames before,
+	// but doesn't answer in another interval. Disabled if 0.
+	KeepaliveInterval uint32 `protobuf:"varint,5,opt,name=keepalive_interval,json=keepaliveInterval" json:"keepalive_interval,omitempty"`
 }
 
 func (m *MultiplexingConfig) Reset()                    { *m = MultiplexingConfig{} }
 func (m *MultiplexingConfig) String() string            { return proto.CompactTextString(m) }
 func (*MultiplexingConfig) ProtoMessage()               {}
-func (*MultiplexingConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*MultiplexingConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *MultiplexingConfig) GetEnabled() bool {
 	if m != nil {
@@ -320,71 +456,112 @@ func (m *MultiplexingConfig) GetConcurrency() uint32 {
 	return 0
 }
 
+func (m *MultiplexingConfig) GetMaxLifetime() uint32 {
+	if m != nil {
+		return m.MaxLifetime
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetMaxReuse() uint32 {
+	if m != nil {
+		return m.MaxReuse
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetKeepaliveInterval() uint32 {
+	if m != nil {
+		return m.KeepaliveInterval
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*InboundConfig)(nil), "v2ray.core.app.proxyman.InboundConfig")
 	proto.RegisterType((*AllocationStrategy)(nil), "v2ray.core.app.proxyman.AllocationStrategy")
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyConcurrency)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyConcurrency")
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyRefresh)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyRefresh")
 	proto.RegisterType((*ReceiverConfig)(nil), "v2ray.core.app.proxyman.ReceiverConfig")
+	proto.RegisterType((*AuthFailureBan)(nil), "v2ray.core.app.proxyman.AuthFailureBan")
 	proto.RegisterType((*InboundHandlerConfig)(nil), "v2ray.core.app.proxyman.InboundHandlerConfig")
 	proto.RegisterType((*OutboundConfig)(nil), "v2ray.core.app.proxyman.OutboundConfig")
 	proto.RegisterType((*SenderConfig)(nil), "v2ray.core.app.proxyman.SenderConfig")
 	proto.RegisterType((*MultiplexingConfig)(nil), "v2ray.core.app.proxyman.MultiplexingConfig")
 	proto.RegisterEnum("v2ray.core.app.proxyman.KnownProtocols", KnownProtocols_name, KnownProtocols_value)
 	proto.RegisterEnum("v2ray.core.app.proxyman.AllocationStrategy_Type", AllocationStrategy_Type_name, AllocationStrategy_Type_value)
+	proto.RegisterEnum("v2ray.core.app.proxyman.ReceiverConfig_ProxyProtocol", ReceiverConfig_ProxyProtocol_name, ReceiverConfig_ProxyProtocol_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/app/proxyman/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 772 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0x5f, 0x6f, 0xeb, 0x34,
-	0x18, 0xc6, 0x4f, 0x9a, 0x9e, 0xb6, 0xe7, 0xdd, 0x69, 0x96, 0x63, 0x26, 0xad, 0x14, 0x90, 0x4a,
-	0x41, 0xac, 0x1a, 0x28, 0x19, 0x9d, 0xb8, 0xe0, 0x0a, 0x46, 0x37, 0x69, 0x03, 0xa6, 0x06, 0xb7,
-	0xe2, 0x62, 0x42, 0x8a, 0xbc, 0xc4, 0x0b, 0x11, 0x89, 0x1d, 0x39, 0x6e, 0xb7, 0x7c, 0x25, 0x3e,
-	0x05, 0x97, 0x5c, 0xf0, 0x09, 0xf8, 0x34, 0x28, 0x71, 0xd2, 0x3f, 0x6b, 0x3b, 0xce, 0xb4, 0x3b,
-	0x67, 0x7b, 0x9e, 0x9f, 0xed, 0xe7, 0x7d, 0xfd, 0x16, 0x06, 0xf3, 0xa1, 0x20, 0x99, 0xe5, 0xf1,
-	0xd8, 0xf6, 0xb8, 0xa0, 0x36, 0x49, 0x12, 0x3b, 0x11, 0xfc, 0x21, 0x8b, 0x09, 0xb3, 0x3d, 0xce,
-	0xee, 0xc2, 0xc0, 0x4a, 0x04, 0x97, 0x1c, 0x1d, 0x56, 0x4a, 0x41, 0x2d, 0x92, 0x24, 0x56, 0xa5,
-	0xea, 0x1e, 0x3d, 0x42, 0x78, 0x3c, 0x8e, 0x39, 0xb3, 0x19, 0x95, 0x36, 0xf1, 0x7d, 0x41, 0xd3,
-	0x54, 0x11, 0xba, 0x9f, 0xef, 0x16, 0x26, 0x5c, 0xc8, 0x52, 0x65, 0x3d, 0x52, 0x49, 0x41, 0x58,
-	0x9a, 0xff, 0xdf, 0x0e, 0x99, 0xa4, 0x22, 0x57, 0xaf, 0x9e, 0xab, 0x7b, 0xb2, 0x9d, 0x9a, 0x52,
-	0x11, 0x92, 0xc8, 0x96, 0x59, 0x42, 0x7d, 0x37, 0xa6, 0x69, 0x4a, 0x02, 0xaa, 0x1c, 0xfd, 0x7d,
-	0x68, 0x5f, 0xb1, 0x5b, 0x3e, 0x63, 0xfe, 0xa8, 0x00, 0xf5, 0xff, 0xd2, 0x01, 0x9d, 0x45, 0x11,
-	0xf7, 0x88, 0x0c, 0x39, 0x9b, 0x48, 0x41, 0x24, 0x0d, 0x32, 0x74, 0x0e, 0xf5, 0xdc, 0xde, 0xd1,
-	0x7a, 0xda, 0xc0, 0x18, 0x9e, 0x58, 0x3b, 0x02, 0xb0, 0x36, 0xad, 0xd6, 0x34, 0x4b, 0x28, 0x2e,
-	0xdc, 0xe8, 0x0f, 0xd8, 0xf3, 0x38, 0xf3, 0x66, 0x42, 0x50, 0xe6, 0x65, 0x9d, 0x5a, 0x4f, 0x1b,
-	0xec, 0x0d, 0xaf, 0x9e, 0x03, 0xdb, 0xfc, 0xd3, 0x68, 0x09, 0xc4, 0xab, 0x74, 0xe4, 0x42, 0x53,
-	0xd0, 0x3b, 0x41, 0xd3, 0xdf, 0x3b, 0x7a, 0xb1, 0xd1, 0xc5, 0xcb, 0x36, 0xc2, 0x0a, 0x86, 0x2b,
-	0x6a, 0xf7, 0x1b, 0xf8, 0xe4, 0xc9, 0xe3, 0xa0, 0x03, 0x78, 0x3d, 0x27, 0xd1, 0x4c, 0xa5, 0xd6,
-	0xc6, 0xea, 0xa3, 0xfb, 0x35, 0x7c, 0xb8, 0x13, 0xbe, 0xdd, 0xd2, 0xff, 0x0a, 0xea, 0x79, 0x8a,
-	0x08, 0xa0, 0x71, 0x16, 0xdd, 0x93, 0x2c, 0x35, 0x5f, 0xe5, 0x6b, 0x4c, 0x98, 0xcf, 0x63, 0x53,
-	0x43, 0x6f, 0xa1, 0x75, 0xf1, 0x90, 0x37, 0x04, 0x89, 0xcc, 0x5a, 0xff, 0x5f, 0x1d, 0x0c, 0x4c,
-	0x3d, 0x1a, 0xce, 0xa9, 0x50, 0x55, 0x45, 0xdf, 0x01, 0xe4, 0x6d, 0xe3, 0x0a, 0xc2, 0x02, 0xc5,
-	0xde, 0x1b, 0xf6, 0x56, 0xe3, 0x50, 0x9d, 0x62, 0x31, 0x2a, 0x2d, 0x87, 0x0b, 0x89, 0x73, 0x1d,
-	0x7e, 0x93, 0x54, 0x4b, 0xf4, 0x2d, 0x34, 0xa2, 0x30, 0x95, 0x94, 0x95, 0x45, 0xfb, 0x74, 0x87,
-	0xf9, 0xca, 0x19, 0x8b, 0x73, 0x1e, 0x93, 0x90, 0xe1, 0xd2, 0x80, 0x7e, 0x83, 0x0f, 0xc8, 0xe2,
-	0xbe, 0x6e, 0x5a, 0x5e, 0xb8, 0xac, 0xc9, 0x97, 0xcf, 0xa8, 0x09, 0x46, 0x64, 0xb3, 0x31, 0xa7,
-	0xb0, 0x9f, 0x4a, 0x41, 0x49, 0xec, 0xa6, 0x54, 0xca, 0x90, 0x05, 0x69, 0xa7, 0xbe, 0x49, 0x5e,
-	0x3c, 0x1c, 0xab, 0x7a, 0x38, 0xd6, 0xa4, 0x70, 0xa9, 0x7c, 0xb0, 0xa1, 0x18, 0x93, 0x12, 0x81,
-	0xbe, 0x87, 0x8f, 0x85, 0x4a, 0xd0, 0xe5, 0x22, 0x0c, 0x42, 0x46, 0x22, 0xd7, 0xa7, 0xa9, 0x0c,
-	0x59, 0xb1, 0x7b, 0xe7, 0x75, 0x4f, 0x1b, 0xb4, 0x70, 0xb7, 0xd4, 0x8c, 0x4b, 0xc9, 0xf9, 0x52,
-	0x81, 0x1c, 0xd8, 0xf7, 0x8b, 0x1c, 0x5c, 0x3e, 0xa7, 0x42, 0x84, 0x3e, 0xed, 0x34, 0x7b, 0xfa,
-	0xc0, 0x18, 0x1e, 0xed, 0xbc, 0xf1, 0x4f, 0x8c, 0xdf, 0x33, 0x27, 0x7f, 0x96, 0x1e, 0x8f, 0x52,
-	0x6c, 0x28, 0xff, 0xb8, 0xb4, 0xff, 0x58, 0x6f, 0x35, 0xcc, 0x66, 0xff, 0x1f, 0x0d, 0x0e, 0xca,
-	0x17, 0x7b, 0x49, 0x98, 0x1f, 0x2d, 0x4a, 0x6c, 0x82, 0x2e, 0x49, 0x50, 0xd4, 0xf6, 0x0d, 0xce,
-	0x97, 0x68, 0x02, 0xef, 0xca, 0x03, 0x8a, 0x65, 0x38, 0xaa, 0x7c, 0x5f, 0x6c, 0x29, 0x9f, 0x9a,
-	0x12, 0xc5, 0x73, 0xf5, 0xaf, 0xd5, 0x90, 0xc0, 0x66, 0x05, 0x58, 0x24, 0x73, 0x0d, 0x46, 0x71,
-	0xe0, 0x25, 0x51, 0x7f, 0x16, 0xb1, 0x5d, 0xb8, 0x2b, 0x5c, 0xdf, 0x04, 0x63, 0x3c, 0x93, 0xab,
-	0x03, 0xe8, 0xef, 0x1a, 0xbc, 0x9d, 0x50, 0xe6, 0x2f, 0x2e, 0x76, 0x0a, 0xfa, 0x3c, 0x24, 0x65,
-	0xd3, 0xbe, 0x47, 0xdf, 0xe5, 0xea, 0x6d, 0x6d, 0x51, 0x7b, 0x79, 0x5b, 0xfc, 0xb2, 0xe3, 0xf2,
-	0xc7, 0xff, 0x03, 0x75, 0x72, 0x53, 0xc9, 0x5c, 0x0f, 0x00, 0xdd, 0x00, 0x8a, 0x67, 0x91, 0x0c,
-	0x93, 0x88, 0x3e, 0x3c, 0xd9, 0xc2, 0x6b, 0xad, 0x72, 0x5d, 0x59, 0x42, 0x16, 0x94, 0xdc, 0x77,
-	0x0b, 0xcc, 0x22, 0x5c, 0x07, 0xd0, 0xa6, 0x10, 0x75, 0xa0, 0x49, 0x19, 0xb9, 0x8d, 0xa8, 0x5f,
-	0x64, 0xda, 0xc2, 0xd5, 0x27, 0xea, 0x6d, 0x8e, 0xe7, 0xf6, 0xda, 0x4c, 0x3d, 0xfe, 0x0c, 0x8c,
-	0xf5, 0x2e, 0x45, 0x2d, 0xa8, 0x5f, 0x4e, 0xa7, 0x8e, 0xf9, 0x0a, 0x35, 0x41, 0x9f, 0xfe, 0x3c,
-	0x31, 0xb5, 0x1f, 0x46, 0xf0, 0x91, 0xc7, 0xe3, 0x5d, 0x67, 0x77, 0xb4, 0x9b, 0x56, 0xb5, 0xfe,
-	0xb3, 0x76, 0xf8, 0xeb, 0x10, 0x93, 0xcc, 0x1a, 0xe5, 0xaa, 0xb3, 0x24, 0x51, 0x49, 0xc5, 0x84,
-	0xdd, 0x36, 0x8a, 0xdf, 0xa7, 0xd3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x6a, 0xe5, 0x6f, 0xed,
-	0x95, 0x07, 0x00, 0x00,
+	// 1068 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x5d, 0x6f, 0xdb, 0x36,
+	0x17, 0x8e, 0x3f, 0x1a, 0x3b, 0xc7, 0xb1, 0xa3, 0xf0, 0x0d, 0x5a, 0xbd, 0xce, 0x06, 0xb8, 0xde,
+	0xd0, 0x1a, 0xdd, 0x26, 0x77, 0x2e, 0x7a, 0xd1, 0xab, 0x35, 0x1f, 0x2d, 0x9a, 0xad, 0x41, 0x1c,
+	0xda, 0xd8, 0x45, 0x51, 0x40, 0x60, 0x24, 0xc6, 0x21, 0x2a, 0x91, 0x1a, 0x45, 0x39, 0xf6, 0xaf,
+	0xd9, 0xfd, 0xfe, 0xc4, 0x6e, 0x77, 0xb1, 0xbf, 0xb0, 0xff, 0x32, 0x48, 0xa4, 0x9c, 0x28, 0x8e,
+	0x9b, 0x05, 0xbd, 0xe3, 0x21, 0x9f, 0xf3, 0x90, 0xe7, 0xe1, 0xf9, 0x80, 0xde, 0x74, 0x20, 0xc9,
+	0xdc, 0xf1, 0x44, 0xd8, 0xf7, 0x84, 0xa4, 0x7d, 0x12, 0x45, 0xfd, 0x48, 0x8a, 0xd9, 0x3c, 0x24,
+	0xbc, 0xef, 0x09, 0x7e, 0xce, 0x26, 0x4e, 0x24, 0x85, 0x12, 0xe8, 0x51, 0x8e, 0x94, 0xd4, 0x21,
+	0x51, 0xe4, 0xe4, 0xa8, 0xf6, 0xd3, 0x1b, 0x14, 0x9e, 0x08, 0x43, 0xc1, 0xfb, 0x9c, 0xaa, 0x3e,
+	0xf1, 0x7d, 0x49, 0xe3, 0x58, 0x33, 0xb4, 0xbf, 0x5d, 0x0d, 0x8c, 0x84, 0x54, 0x06, 0xe5, 0xdc,
+	0x40, 0x29, 0x49, 0x78, 0x9c, 0x9e, 0xf7, 0x19, 0x57, 0x54, 0xa6, 0xe8, 0xeb, 0xef, 0x6a, 0x3f,
+	0xbf, 0x9d, 0x35, 0xa6, 0x92, 0x91, 0xa0, 0xaf, 0xe6, 0x11, 0xf5, 0xdd, 0x90, 0xc6, 0x31, 0x99,
+	0x50, 0xe3, 0xf1, 0xe4, 0x96, 0x98, 0xa5, 0x48, 0x14, 0x95, 0x05, 0xe6, 0xee, 0x16, 0x34, 0x8f,
+	0xf8, 0x99, 0x48, 0xb8, 0x7f, 0x90, 0x6d, 0x77, 0xff, 0xa9, 0x00, 0xda, 0x0b, 0x02, 0xe1, 0x11,
+	0xc5, 0x04, 0x1f, 0x29, 0x49, 0x14, 0x9d, 0xcc, 0xd1, 0x21, 0x54, 0xd3, 0x6b, 0xec, 0x52, 0xa7,
+	0xd4, 0x6b, 0x0d, 0x9e, 0x3b, 0x2b, 0x84, 0x72, 0x96, 0x5d, 0x9d, 0xf1, 0x3c, 0xa2, 0x38, 0xf3,
+	0x46, 0x9f, 0xa0, 0xe1, 0x09, 0xee, 0x25, 0x52, 0x52, 0xee, 0xcd, 0xed, 0x72, 0xa7, 0xd4, 0x6b,
+	0x0c, 0x8e, 0xee, 0x43, 0xb6, 0xbc, 0x75, 0x70, 0x45, 0x88, 0xaf, 0xb3, 0x23, 0x17, 0x6a, 0x92,
+	0x9e, 0x4b, 0x1a, 0x5f, 0xd8, 0x95, 0xec, 0xa2, 0x37, 0x5f, 0x76, 0x11, 0xd6, 0x64, 0x38, 0x67,
+	0x45, 0xbb, 0xb0, 0x91, 0xfe, 0x99, 0x7b, 0xce, 0x02, 0x6a, 0x57, 0x3b, 0xa5, 0xde, 0x06, 0xae,
+	0xa7, 0x1b, 0x6f, 0x59, 0x40, 0xdb, 0x2f, 0xe1, 0xeb, 0xcf, 0xbe, 0x15, 0xed, 0xc0, 0x83, 0x29,
+	0x09, 0x12, 0x2d, 0x69, 0x13, 0x6b, 0xa3, 0xfd, 0x23, 0xfc, 0x7f, 0xe5, 0xcd, 0xb7, 0xbb, 0x74,
+	0xbf, 0x87, 0x6a, 0x2a, 0x31, 0x02, 0x58, 0xdf, 0x0b, 0x2e, 0xc9, 0x3c, 0xb6, 0xd6, 0xd2, 0x35,
+	0x26, 0xdc, 0x17, 0xa1, 0x55, 0x42, 0x9b, 0x50, 0x7f, 0x33, 0x4b, 0xb3, 0x8a, 0x04, 0x56, 0xb9,
+	0xfb, 0x7b, 0x0d, 0x5a, 0x98, 0x7a, 0x94, 0x4d, 0xa9, 0xd4, 0x5f, 0x8e, 0x7e, 0x02, 0xc8, 0xe2,
+	0x90, 0x84, 0x4f, 0x34, 0x77, 0x63, 0xd0, 0xb9, 0xae, 0x95, 0x4e, 0x37, 0x87, 0x53, 0xe5, 0x0c,
+	0x85, 0x54, 0x38, 0xc5, 0xe1, 0x8d, 0x28, 0x5f, 0xa2, 0x57, 0xb0, 0x1e, 0xb0, 0x58, 0x51, 0x6e,
+	0x7e, 0xf4, 0xf1, 0x0a, 0xe7, 0xa3, 0xe1, 0x89, 0x3c, 0x14, 0x21, 0x61, 0x1c, 0x1b, 0x07, 0xf4,
+	0x11, 0xfe, 0x47, 0x16, 0xf1, 0xba, 0xb1, 0x09, 0xd8, 0x7c, 0xd8, 0x77, 0xf7, 0xf8, 0x30, 0x8c,
+	0xc8, 0x72, 0xd6, 0x8e, 0x61, 0x2b, 0x56, 0x92, 0x92, 0xd0, 0x8d, 0xa9, 0x52, 0x8c, 0x4f, 0x62,
+	0xbb, 0xba, 0xcc, 0xbc, 0xa8, 0x3e, 0x27, 0xaf, 0x3e, 0x67, 0x94, 0x79, 0x69, 0x7d, 0x70, 0x4b,
+	0x73, 0x8c, 0x0c, 0x05, 0x7a, 0x0d, 0x5f, 0x49, 0xad, 0xa0, 0x2b, 0x24, 0x9b, 0x30, 0x4e, 0x02,
+	0xd7, 0xa7, 0xb1, 0x62, 0x3c, 0xbb, 0xdd, 0x7e, 0xd0, 0x29, 0xf5, 0xea, 0xb8, 0x6d, 0x30, 0x27,
+	0x06, 0x72, 0x78, 0x85, 0x40, 0x43, 0xd8, 0xf2, 0x33, 0x1d, 0x5c, 0x31, 0xa5, 0x52, 0x32, 0x9f,
+	0xda, 0xb5, 0x4e, 0xa5, 0xd7, 0x1a, 0x3c, 0x5d, 0x19, 0xf1, 0x2f, 0x5c, 0x5c, 0xf2, 0x61, 0x5a,
+	0xb3, 0x9e, 0x08, 0x62, 0xdc, 0xd2, 0xfe, 0x27, 0xc6, 0x1d, 0x7d, 0x84, 0x56, 0x06, 0x75, 0x23,
+	0x03, 0xb1, 0xeb, 0x59, 0xa5, 0xbe, 0x5c, 0x49, 0x58, 0x4c, 0x02, 0x67, 0x98, 0xee, 0xe7, 0xfc,
+	0xb8, 0x19, 0x5d, 0x37, 0xd1, 0x3e, 0xb4, 0x52, 0x75, 0x2f, 0xa9, 0xef, 0xc6, 0x22, 0x91, 0x1e,
+	0xb5, 0x37, 0x3a, 0x95, 0x5e, 0x63, 0xb0, 0x7b, 0x93, 0x5d, 0xb7, 0x18, 0xe7, 0xe0, 0xe8, 0x10,
+	0xe3, 0xa6, 0x71, 0x19, 0x65, 0x1e, 0xe8, 0x35, 0x34, 0x7d, 0xca, 0xd9, 0x15, 0x05, 0xdc, 0x4d,
+	0xb1, 0xa9, 0x3d, 0x0c, 0xc3, 0x29, 0x58, 0x24, 0x51, 0x17, 0xee, 0x39, 0x61, 0x41, 0x22, 0xa9,
+	0x7b, 0x46, 0xb8, 0xdd, 0xc8, 0xbe, 0x73, 0xb5, 0x6c, 0x7b, 0x89, 0xba, 0x78, 0xab, 0xf1, 0xfb,
+	0x84, 0xe3, 0x16, 0x29, 0xd8, 0xe8, 0x14, 0x1e, 0x16, 0x65, 0x73, 0x95, 0x4c, 0x62, 0x45, 0x7d,
+	0x7b, 0xf3, 0xee, 0xd7, 0xed, 0x14, 0x44, 0x1a, 0x6b, 0xc7, 0xee, 0x2b, 0x68, 0x16, 0xb4, 0x4c,
+	0xeb, 0xef, 0x90, 0xc5, 0xe4, 0x2c, 0xa0, 0xbe, 0xb5, 0x96, 0x5a, 0x27, 0x51, 0x9a, 0x04, 0x24,
+	0xd0, 0xb5, 0x89, 0xe9, 0x6f, 0x09, 0x93, 0xd4, 0xb7, 0xca, 0x3f, 0x57, 0xeb, 0xeb, 0x56, 0xad,
+	0x3b, 0x81, 0x56, 0xf1, 0xd5, 0xe8, 0x31, 0x6c, 0x86, 0x64, 0x96, 0xc7, 0x1d, 0x9b, 0xf2, 0x6f,
+	0x84, 0x64, 0x66, 0x40, 0x31, 0x7a, 0x08, 0xeb, 0x97, 0x8c, 0xfb, 0xe2, 0x32, 0x2b, 0xc1, 0x26,
+	0x36, 0x16, 0x6a, 0x43, 0xdd, 0x4f, 0xa4, 0xce, 0xcb, 0x4a, 0x76, 0xb2, 0xb0, 0xbb, 0x7f, 0x97,
+	0x60, 0xc7, 0x34, 0xff, 0x77, 0x84, 0xfb, 0xc1, 0xa2, 0x21, 0x58, 0x50, 0x51, 0x64, 0x92, 0x5d,
+	0xb3, 0x81, 0xd3, 0x25, 0x1a, 0xc1, 0xb6, 0x49, 0x67, 0x79, 0x55, 0x4a, 0xba, 0xd8, 0x9f, 0xdc,
+	0x52, 0xec, 0x7a, 0x30, 0x65, 0x9d, 0xdf, 0x3f, 0xd6, 0x73, 0x09, 0x5b, 0x39, 0xc1, 0xa2, 0x8e,
+	0x8e, 0xf3, 0x9c, 0x5d, 0x30, 0x56, 0xee, 0xc5, 0xa8, 0x93, 0x34, 0xa7, 0xeb, 0x5a, 0xd0, 0x3a,
+	0x49, 0xd4, 0xf5, 0x59, 0xf6, 0x57, 0x19, 0x36, 0x47, 0x94, 0xfb, 0x8b, 0xc0, 0x5e, 0x40, 0x65,
+	0xca, 0x88, 0x5d, 0xfa, 0xaf, 0x5d, 0x2a, 0x45, 0xdf, 0xd6, 0x44, 0xca, 0x5f, 0xde, 0x44, 0x4e,
+	0x57, 0x04, 0xff, 0xec, 0x0e, 0xd2, 0x2c, 0xb7, 0x0c, 0x67, 0x51, 0x00, 0xf4, 0x01, 0x50, 0x98,
+	0x04, 0x8a, 0x45, 0x01, 0x9d, 0x7d, 0xb6, 0xe1, 0x15, 0x2a, 0xe4, 0x38, 0x77, 0x61, 0x7c, 0x62,
+	0x78, 0xb7, 0x17, 0x34, 0x0b, 0x71, 0xff, 0x2c, 0x01, 0x5a, 0x46, 0x22, 0x1b, 0x6a, 0x94, 0x67,
+	0xa9, 0x9d, 0x89, 0x5a, 0xc7, 0xb9, 0x89, 0x3a, 0xcb, 0xa3, 0xbe, 0x59, 0x9c, 0xcf, 0x26, 0xab,
+	0x03, 0x76, 0x4e, 0x15, 0x0b, 0xa9, 0x5d, 0x59, 0x64, 0xf5, 0x7b, 0xb3, 0x95, 0x4e, 0xd8, 0x14,
+	0x22, 0x69, 0x12, 0xeb, 0x09, 0xdb, 0xc4, 0xf5, 0x90, 0xcc, 0x70, 0x6a, 0xa3, 0x1f, 0x00, 0x7d,
+	0xa2, 0x34, 0x22, 0x41, 0xda, 0x88, 0x33, 0x7d, 0xa6, 0x24, 0xc8, 0x9a, 0x6f, 0x13, 0x6f, 0x2f,
+	0x4e, 0x8e, 0xcc, 0xc1, 0xb3, 0x6f, 0xa0, 0x55, 0xec, 0xa1, 0xa8, 0x0e, 0xd5, 0x77, 0xe3, 0xf1,
+	0xd0, 0x5a, 0x43, 0x35, 0xa8, 0x8c, 0xdf, 0x8f, 0xac, 0xd2, 0xfe, 0x01, 0xec, 0x7a, 0x22, 0x5c,
+	0xa5, 0xd5, 0xb0, 0xf4, 0xa1, 0x9e, 0xaf, 0xff, 0x28, 0x3f, 0xfa, 0x75, 0x80, 0xc9, 0xdc, 0x39,
+	0x48, 0x51, 0x7b, 0x51, 0xa4, 0x7f, 0x26, 0x24, 0xfc, 0x6c, 0x3d, 0x6b, 0x26, 0x2f, 0xfe, 0x0d,
+	0x00, 0x00, 0xff, 0xff, 0x68, 0x18, 0xcb, 0x3a, 0x78, 0x0a, 0x00, 0x00,
 }
@@ -53,6 +53,15 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 		return nil, newError("not an inbound proxy.")
 	}
 
+	filter, err := newSourceFilter(receiverConfig)
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := toIPNets(receiverConfig.ProxyProtocolTrusted)
+	if err != nil {
+		return nil, newError("invalid trusted PROXY protocol peers").Base(err)
+	}
+
 	h := &AlwaysOnInboundHandler{
 		proxy: p,
 		mux:   mux.NewServer(ctx),
@@ -76,11 +85,14 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 				proxy:           p,
 				stream:          receiverConfig.StreamSettings,
 				recvOrigDest:    receiverConfig.ReceiveOriginalDestination,
+				proxyProtocol:   receiverConfig.ProxyProtocol,
+				trustedProxies:  trustedProxies,
 				tag:             tag,
 				dispatcher:      h.mux,
 				sniffers:        receiverConfig.DomainOverride,
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				filter:          filter,
 			}
 			h.workers = append(h.workers, worker)
 		}
@@ -95,6 +107,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 				dispatcher:      h.mux,
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				filter:          filter,
 			}
 			h.workers = append(h.workers, worker)
 		}
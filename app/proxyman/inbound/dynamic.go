@@ -8,6 +8,7 @@ import (
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/app/proxyman/mux"
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/signal"
@@ -26,10 +27,25 @@ type DynamicInboundHandler struct {
 	lastRefresh    time.Time
 	mux            *mux.Server
 	task           *signal.PeriodicTask
+	filter         *sourceFilter
+	trustedProxies []*net.IPNet
+
+	// Fields below are used by External allocation strategy only.
+	externalAccess  sync.Mutex
+	externalWorkers map[net.Port][]worker
+	portFileModTime time.Time
 }
 
 func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*DynamicInboundHandler, error) {
 	v := core.MustFromContext(ctx)
+	filter, err := newSourceFilter(receiverConfig)
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := toIPNets(receiverConfig.ProxyProtocolTrusted)
+	if err != nil {
+		return nil, newError("invalid trusted PROXY protocol peers").Base(err)
+	}
 	h := &DynamicInboundHandler{
 		tag:            tag,
 		proxyConfig:    proxyConfig,
@@ -37,6 +53,19 @@ func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *p
 		portsInUse:     make(map[net.Port]bool),
 		mux:            mux.NewServer(ctx),
 		v:              v,
+		filter:         filter,
+		trustedProxies: trustedProxies,
+	}
+
+	if h.isExternal() {
+		h.externalWorkers = make(map[net.Port][]worker)
+		if len(receiverConfig.AllocationStrategy.PortFile) > 0 {
+			h.task = &signal.PeriodicTask{
+				Interval: portFileCheckInterval,
+				Execute:  h.reloadPortFile,
+			}
+		}
+		return h, nil
 	}
 
 	h.task = &signal.PeriodicTask{
@@ -47,6 +76,10 @@ func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *p
 	return h, nil
 }
 
+func (h *DynamicInboundHandler) isExternal() bool {
+	return h.receiverConfig.AllocationStrategy.GetType() == proxyman.AllocationStrategy_External
+}
+
 func (h *DynamicInboundHandler) allocatePort() net.Port {
 	from := int(h.receiverConfig.PortRange.From)
 	delta := int(h.receiverConfig.PortRange.To) - from + 1
@@ -79,66 +112,85 @@ func (h *DynamicInboundHandler) closeWorkers(workers []worker) {
 	h.portMutex.Unlock()
 }
 
-func (h *DynamicInboundHandler) refresh() error {
-	h.lastRefresh = time.Now()
+// createWorkers starts workers on the given port. If a worker fails to start, the workers started before it are returned
+// along with the error.
+func (h *DynamicInboundHandler) createWorkers(address net.Address, port net.Port, uplinkCounter core.StatCounter, downlinkCounter core.StatCounter) ([]worker, error) {
+	var workers []worker
 
-	timeout := time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()) * 2
-	concurrency := h.receiverConfig.AllocationStrategy.GetConcurrencyValue()
-	workers := make([]worker, 0, concurrency)
+	rawProxy, err := h.v.CreateObject(h.proxyConfig)
+	if err != nil {
+		return nil, newError("failed to create proxy instance").Base(err)
+	}
+	p := rawProxy.(proxy.Inbound)
+	nl := p.Network()
+	if nl.HasNetwork(net.Network_TCP) {
+		worker := &tcpWorker{
+			tag:             h.tag,
+			address:         address,
+			port:            port,
+			proxy:           p,
+			stream:          h.receiverConfig.StreamSettings,
+			recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
+			proxyProtocol:   h.receiverConfig.ProxyProtocol,
+			trustedProxies:  h.trustedProxies,
+			dispatcher:      h.mux,
+			sniffers:        h.receiverConfig.DomainOverride,
+			uplinkCounter:   uplinkCounter,
+			downlinkCounter: downlinkCounter,
+			filter:          h.filter,
+		}
+		if err := worker.Start(); err != nil {
+			return workers, newError("failed to create TCP worker").Base(err)
+		}
+		workers = append(workers, worker)
+	}
+
+	if nl.HasNetwork(net.Network_UDP) {
+		worker := &udpWorker{
+			tag:             h.tag,
+			proxy:           p,
+			address:         address,
+			port:            port,
+			recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
+			dispatcher:      h.mux,
+			uplinkCounter:   uplinkCounter,
+			downlinkCounter: downlinkCounter,
+			filter:          h.filter,
+		}
+		if err := worker.Start(); err != nil {
+			return workers, newError("failed to create UDP worker").Base(err)
+		}
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
 
+func (h *DynamicInboundHandler) listenAddress() net.Address {
 	address := h.receiverConfig.Listen.AsAddress()
 	if address == nil {
 		address = net.AnyIP
 	}
+	return address
+}
+
+func (h *DynamicInboundHandler) refresh() error {
+	h.lastRefresh = time.Now()
+
+	timeout := time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()) * 2
+	concurrency := h.receiverConfig.AllocationStrategy.GetConcurrencyValue()
+	workers := make([]worker, 0, concurrency)
 
+	address := h.listenAddress()
 	uplinkCounter, downlinkCounter := getStatCounter(h.v, h.tag)
 
 	for i := uint32(0); i < concurrency; i++ {
 		port := h.allocatePort()
-		rawProxy, err := h.v.CreateObject(h.proxyConfig)
+		ws, err := h.createWorkers(address, port, uplinkCounter, downlinkCounter)
 		if err != nil {
-			newError("failed to create proxy instance").Base(err).AtWarning().WriteToLog()
-			continue
-		}
-		p := rawProxy.(proxy.Inbound)
-		nl := p.Network()
-		if nl.HasNetwork(net.Network_TCP) {
-			worker := &tcpWorker{
-				tag:             h.tag,
-				address:         address,
-				port:            port,
-				proxy:           p,
-				stream:          h.receiverConfig.StreamSettings,
-				recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
-				dispatcher:      h.mux,
-				sniffers:        h.receiverConfig.DomainOverride,
-				uplinkCounter:   uplinkCounter,
-				downlinkCounter: downlinkCounter,
-			}
-			if err := worker.Start(); err != nil {
-				newError("failed to create TCP worker").Base(err).AtWarning().WriteToLog()
-				continue
-			}
-			workers = append(workers, worker)
-		}
-
-		if nl.HasNetwork(net.Network_UDP) {
-			worker := &udpWorker{
-				tag:             h.tag,
-				proxy:           p,
-				address:         address,
-				port:            port,
-				recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
-				dispatcher:      h.mux,
-				uplinkCounter:   uplinkCounter,
-				downlinkCounter: downlinkCounter,
-			}
-			if err := worker.Start(); err != nil {
-				newError("failed to create UDP worker").Base(err).AtWarning().WriteToLog()
-				continue
-			}
-			workers = append(workers, worker)
+			newError("failed to create workers on port ", port).Base(err).AtWarning().WriteToLog()
 		}
+		workers = append(workers, ws...)
 	}
 
 	h.workerMutex.Lock()
@@ -153,10 +205,19 @@ func (h *DynamicInboundHandler) refresh() error {
 }
 
 func (h *DynamicInboundHandler) Start() error {
+	if h.task == nil {
+		return nil
+	}
 	return h.task.Start()
 }
 
 func (h *DynamicInboundHandler) Close() error {
+	if h.isExternal() {
+		if h.task != nil {
+			common.Close(h.task)
+		}
+		return h.SetPorts(context.Background(), nil)
+	}
 	return h.task.Close()
 }
 
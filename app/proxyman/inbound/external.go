@@ -0,0 +1,146 @@
+package inbound
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"v2ray.com/core/common/net"
+)
+
+// portFileCheckInterval is the interval between checks of port file changes.
+const portFileCheckInterval = time.Second * 10
+
+// SetPorts implements command.ExternalPortHandler. Workers are started on new ports, and closed on removed ports.
+// Ports on which workers fail to start are left unassigned, and returned in the error.
+func (h *DynamicInboundHandler) SetPorts(ctx context.Context, ports []net.Port) error {
+	if !h.isExternal() {
+		return newError("ports of inbound ", h.tag, " are not assigned externally")
+	}
+	if pr := h.receiverConfig.PortRange; pr != nil {
+		for _, port := range ports {
+			if !pr.Contains(port) {
+				return newError("port ", port, " is out of range ", pr.FromPort(), "-", pr.ToPort())
+			}
+		}
+	}
+
+	h.externalAccess.Lock()
+	defer h.externalAccess.Unlock()
+
+	assigned := make(map[net.Port]bool, len(ports))
+	for _, port := range ports {
+		assigned[port] = true
+	}
+
+	var removed []worker
+	for port, workers := range h.externalWorkers {
+		if !assigned[port] {
+			removed = append(removed, workers...)
+			delete(h.externalWorkers, port)
+		}
+	}
+
+	address := h.listenAddress()
+	uplinkCounter, downlinkCounter := getStatCounter(h.v, h.tag)
+	var failed []net.Port
+	for port := range assigned {
+		if _, found := h.externalWorkers[port]; found {
+			continue
+		}
+		workers, err := h.createWorkers(address, port, uplinkCounter, downlinkCounter)
+		if err != nil {
+			newError("failed to listen on externally assigned port ", port).Base(err).AtWarning().WriteToLog()
+			h.closeWorkers(workers)
+			failed = append(failed, port)
+			continue
+		}
+		newError("listening on externally assigned port ", port).WriteToLog()
+		h.externalWorkers[port] = workers
+	}
+
+	var active []worker
+	for _, workers := range h.externalWorkers {
+		active = append(active, workers...)
+	}
+
+	h.workerMutex.Lock()
+	h.worker = active
+	h.lastRefresh = time.Now()
+	h.workerMutex.Unlock()
+
+	for _, w := range removed {
+		newError("closing worker on port ", w.Port()).WriteToLog()
+		w.Close()
+	}
+
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool { return failed[i] < failed[j] })
+		return newError("failed to listen on ports: ", failed)
+	}
+	return nil
+}
+
+// parsePortList parses a list of ports, one port or port range per line. Empty lines and lines starting with '#' are ignored.
+func parsePortList(content string) ([]net.Port, error) {
+	var ports []net.Port
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		from, to := line, line
+		if idx := strings.Index(line, "-"); idx >= 0 {
+			from, to = strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		}
+		fromPort, err := net.PortFromString(from)
+		if err != nil {
+			return nil, newError("invalid port: ", line).Base(err)
+		}
+		toPort, err := net.PortFromString(to)
+		if err != nil {
+			return nil, newError("invalid port: ", line).Base(err)
+		}
+		if fromPort > toPort {
+			return nil, newError("invalid port range: ", line)
+		}
+		for port := uint32(fromPort); port <= uint32(toPort); port++ {
+			ports = append(ports, net.Port(port))
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports, nil
+}
+
+// reloadPortFile applies the ports in the port file if it is changed since last time.
+func (h *DynamicInboundHandler) reloadPortFile() error {
+	filename := h.receiverConfig.AllocationStrategy.PortFile
+	info, err := os.Stat(filename)
+	if err != nil {
+		newError("failed to read port file: ", filename).Base(err).AtWarning().WriteToLog()
+		return nil
+	}
+	if info.ModTime().Equal(h.portFileModTime) {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		newError("failed to read port file: ", filename).Base(err).AtWarning().WriteToLog()
+		return nil
+	}
+	ports, err := parsePortList(string(content))
+	if err != nil {
+		newError("invalid port file: ", filename).Base(err).AtWarning().WriteToLog()
+		return nil
+	}
+	if err := h.SetPorts(context.Background(), ports); err != nil {
+		newError("failed to apply port file: ", filename).Base(err).AtWarning().WriteToLog()
+		return nil
+	}
+	h.portFileModTime = info.ModTime()
+	return nil
+}
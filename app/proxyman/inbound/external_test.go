@@ -0,0 +1,131 @@
+package inbound_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/servers/tcp"
+	. "v2ray.com/ext/assert"
+)
+
+// pickPortPair returns two consecutive free ports.
+func pickPortPair() net.Port {
+	for {
+		port := tcp.PickPort()
+		listener, err := net.Listen("tcp4", "127.0.0.1:"+strconv.Itoa(int(port)+1))
+		if err == nil {
+			listener.Close()
+			return port
+		}
+	}
+}
+
+func isListening(port net.Port) bool {
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(port),
+	})
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestExternalPorts(t *testing.T) {
+	assert := With(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-ports")
+	assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	single := tcp.PickPort()
+	pair := pickPortPair()
+	portFile := filepath.Join(dir, "ports")
+	content := "# ports assigned to this node\n" + single.String() + "\n\n " + pair.String() + " - " + (pair + 1).String() + "\n"
+	assert(ioutil.WriteFile(portFile, []byte(content), 0644), IsNil)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				Tag: "external",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					Listen: net.NewIPOrDomain(net.LocalHostIP),
+					AllocationStrategy: &proxyman.AllocationStrategy{
+						Type:     proxyman.AllocationStrategy_External,
+						PortFile: portFile,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address: net.NewIPOrDomain(net.LocalHostIP),
+					Port:    uint32(single),
+					NetworkList: &net.NetworkList{
+						Network: []net.Network{net.Network_TCP},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	// Ports in the port file are applied on start.
+	assert(isListening(single), IsTrue)
+	assert(isListening(pair), IsTrue)
+	assert(isListening(pair+1), IsTrue)
+
+	rawHandler, err := v.InboundHandlerManager().GetHandler(context.Background(), "external")
+	assert(err, IsNil)
+	handler := rawHandler.(*DynamicInboundHandler)
+
+	assert(handler.SetPorts(context.Background(), []net.Port{pair + 1}), IsNil)
+	assert(isListening(single), IsFalse)
+	assert(isListening(pair), IsFalse)
+	assert(isListening(pair+1), IsTrue)
+
+	assert(handler.SetPorts(context.Background(), []net.Port{single, pair + 1}), IsNil)
+	assert(isListening(single), IsTrue)
+	assert(isListening(pair+1), IsTrue)
+
+	assert(handler.SetPorts(context.Background(), nil), IsNil)
+	assert(isListening(single), IsFalse)
+	assert(isListening(pair+1), IsFalse)
+
+	// A port in use by others is reported, and left unassigned.
+	occupied, err := net.Listen("tcp4", "127.0.0.1:"+pair.String())
+	assert(err, IsNil)
+	err = handler.SetPorts(context.Background(), []net.Port{single, pair})
+	assert(err, IsNotNil)
+	assert(err.Error(), HasSubstring, pair.String())
+	assert(isListening(single), IsTrue)
+	occupied.Close()
+	assert(isListening(pair), IsFalse)
+
+	assert(handler.SetPorts(context.Background(), []net.Port{single, pair}), IsNil)
+	assert(isListening(single), IsTrue)
+	assert(isListening(pair), IsTrue)
+}
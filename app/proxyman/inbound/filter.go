@@ -0,0 +1,170 @@
+package inbound
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+)
+
+// sourceFilter decides whether connections from a source IP are accepted.
+type sourceFilter struct {
+	allowed []*net.IPNet
+	denied  []*net.IPNet
+	banList *banList
+}
+
+func toIPNets(cidrs []*router.CIDR) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		bits := len(cidr.Ip) * 8
+		if bits != net.IPv4len*8 && bits != net.IPv6len*8 {
+			return nil, newError("invalid IP length: ", len(cidr.Ip))
+		}
+		if int(cidr.Prefix) > bits {
+			return nil, newError("invalid prefix ", cidr.Prefix, " for IP ", net.IP(cidr.Ip))
+		}
+		nets = append(nets, &net.IPNet{
+			IP:   net.IP(cidr.Ip),
+			Mask: net.CIDRMask(int(cidr.Prefix), bits),
+		})
+	}
+	return nets, nil
+}
+
+// newSourceFilter creates a sourceFilter for the given config. It returns nil if no filtering is configured.
+func newSourceFilter(config *proxyman.ReceiverConfig) (*sourceFilter, error) {
+	allowed, err := toIPNets(config.AllowedSource)
+	if err != nil {
+		return nil, newError("invalid allowed source").Base(err)
+	}
+	denied, err := toIPNets(config.DeniedSource)
+	if err != nil {
+		return nil, newError("invalid denied source").Base(err)
+	}
+
+	var bl *banList
+	if ban := config.AuthFailureBan; ban != nil && ban.MaxFailures > 0 {
+		bl = newBanList(ban)
+	}
+
+	if len(allowed) == 0 && len(denied) == 0 && bl == nil {
+		return nil, nil
+	}
+	return &sourceFilter{
+		allowed: allowed,
+		denied:  denied,
+		banList: bl,
+	}, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow returns true if connections from the given source are accepted.
+func (f *sourceFilter) Allow(source net.Address) bool {
+	if f == nil || !source.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) {
+		return true
+	}
+	ip := source.IP()
+	if len(f.allowed) > 0 && !containsIP(f.allowed, ip) {
+		return false
+	}
+	if containsIP(f.denied, ip) {
+		return false
+	}
+	return f.banList == nil || !f.banList.Banned(source)
+}
+
+// FailureHandler returns a function that records an authentication failure of the given source,
+// or nil if auth failures are not tracked.
+func (f *sourceFilter) FailureHandler(source net.Address) func() {
+	if f == nil || f.banList == nil || !source.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) {
+		return nil
+	}
+	return func() {
+		f.banList.Fail(source)
+	}
+}
+
+type banRecord struct {
+	failures uint32
+	since    time.Time
+	until    time.Time
+}
+
+// banList bans source IPs for a while after they fail to authenticate too many times.
+type banList struct {
+	sync.Mutex
+	records     map[string]*banRecord
+	maxFailures uint32
+	window      time.Duration
+	duration    time.Duration
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+func newBanList(config *proxyman.AuthFailureBan) *banList {
+	l := &banList{
+		records:     make(map[string]*banRecord),
+		maxFailures: config.MaxFailures,
+		window:      time.Duration(config.Window) * time.Second,
+		duration:    time.Duration(config.Duration) * time.Second,
+		now:         time.Now,
+	}
+	if l.window == 0 {
+		l.window = time.Minute * 10
+	}
+	if l.duration == 0 {
+		l.duration = time.Hour
+	}
+	return l
+}
+
+// Banned returns true if the given source is banned.
+func (l *banList) Banned(source net.Address) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	r, found := l.records[source.String()]
+	return found && l.now().Before(r.until)
+}
+
+// Fail records an authentication failure of the given source.
+func (l *banList) Fail(source net.Address) {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	key := source.String()
+	r, found := l.records[key]
+	if !found || (now.Sub(r.since) > l.window && now.After(r.until)) {
+		if !found && len(l.records) >= 4096 {
+			l.removeExpired(now)
+		}
+		r = &banRecord{since: now}
+		l.records[key] = r
+	}
+
+	r.failures++
+	if r.failures >= l.maxFailures && now.After(r.until) {
+		r.until = now.Add(l.duration)
+		newError("banning ", source, " for ", l.duration, " after ", r.failures, " authentication failures").AtWarning().WriteToLog()
+	}
+}
+
+func (l *banList) removeExpired(now time.Time) {
+	for key, r := range l.records {
+		if now.Sub(r.since) > l.window && now.After(r.until) {
+			delete(l.records, key)
+		}
+	}
+}
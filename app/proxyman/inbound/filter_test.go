@@ -0,0 +1,139 @@
+package inbound
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/ext/assert"
+)
+
+func TestSourceFilterAllow(t *testing.T) {
+	assert := With(t)
+
+	filter, err := newSourceFilter(&proxyman.ReceiverConfig{
+		AllowedSource: []*router.CIDR{
+			{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+		},
+		DeniedSource: []*router.CIDR{
+			{Ip: []byte{10, 1, 0, 0}, Prefix: 16},
+		},
+	})
+	assert(err, IsNil)
+
+	assert(filter.Allow(net.ParseAddress("10.0.0.1")), IsTrue)
+	assert(filter.Allow(net.ParseAddress("10.1.0.1")), IsFalse)
+	assert(filter.Allow(net.ParseAddress("192.168.0.1")), IsFalse)
+	assert(filter.Allow(net.ParseAddress("::1")), IsFalse)
+	assert(filter.Allow(net.DomainAddress("v2ray.com")), IsTrue)
+
+	filter, err = newSourceFilter(&proxyman.ReceiverConfig{})
+	assert(err, IsNil)
+	assert(filter == nil, IsTrue)
+	assert(filter.Allow(net.ParseAddress("192.168.0.1")), IsTrue)
+	assert(filter.FailureHandler(net.ParseAddress("192.168.0.1")) == nil, IsTrue)
+
+	_, err = newSourceFilter(&proxyman.ReceiverConfig{
+		DeniedSource: []*router.CIDR{
+			{Ip: []byte{10, 0, 0, 0}, Prefix: 33},
+		},
+	})
+	assert(err, IsNotNil)
+}
+
+func TestSourceFilterBan(t *testing.T) {
+	assert := With(t)
+
+	filter, err := newSourceFilter(&proxyman.ReceiverConfig{
+		AuthFailureBan: &proxyman.AuthFailureBan{
+			MaxFailures: 2,
+		},
+	})
+	assert(err, IsNil)
+
+	source := net.ParseAddress("192.168.0.1")
+	assert(filter.Allow(source), IsTrue)
+	filter.FailureHandler(source)()
+	assert(filter.Allow(source), IsTrue)
+	filter.FailureHandler(source)()
+	assert(filter.Allow(source), IsFalse)
+	assert(filter.Allow(net.ParseAddress("192.168.0.2")), IsTrue)
+}
+
+func TestBanListWindowAndDuration(t *testing.T) {
+	assert := With(t)
+
+	l := newBanList(&proxyman.AuthFailureBan{
+		MaxFailures: 2,
+	})
+	assert(l.window, Equals, time.Minute*10)
+	assert(l.duration, Equals, time.Hour)
+
+	now := time.Now()
+	l.now = func() time.Time {
+		return now
+	}
+	source := net.ParseAddress("192.168.0.1")
+
+	// Failures out of the window are not counted together.
+	l.Fail(source)
+	now = now.Add(time.Minute * 11)
+	l.Fail(source)
+	assert(l.Banned(source), IsFalse)
+
+	// Failures within the window ban the source for the duration.
+	now = now.Add(time.Minute * 9)
+	l.Fail(source)
+	assert(l.Banned(source), IsTrue)
+	now = now.Add(time.Minute * 59)
+	assert(l.Banned(source), IsTrue)
+
+	// The ban is not extended by failures during the ban, and a new window starts after it.
+	l.Fail(source)
+	now = now.Add(time.Minute * 2)
+	assert(l.Banned(source), IsFalse)
+	l.Fail(source)
+	assert(l.Banned(source), IsFalse)
+	l.Fail(source)
+	assert(l.Banned(source), IsTrue)
+}
+
+func TestBanListExpire(t *testing.T) {
+	assert := With(t)
+
+	l := newBanList(&proxyman.AuthFailureBan{
+		MaxFailures: 1,
+	})
+	now := time.Now()
+	addRecords := func(prefix string, r banRecord) {
+		for i := 0; i < 2048; i++ {
+			record := r
+			l.records[prefix+strconv.Itoa(i/256)+"."+strconv.Itoa(i%256)] = &record
+		}
+	}
+
+	// Records out of the window and not banned are expired. Others are kept.
+	addRecords("10.0.", banRecord{failures: 1, since: now.Add(-time.Hour), until: now.Add(-time.Minute)})
+	addRecords("10.1.", banRecord{failures: 1, since: now.Add(-time.Hour), until: now.Add(time.Minute)})
+	addRecords("10.2.", banRecord{failures: 1, since: now.Add(-time.Minute)})
+	assert(len(l.records), Equals, 6144)
+	assert(l.Banned(net.ParseAddress("10.0.0.1")), IsFalse)
+	assert(l.Banned(net.ParseAddress("10.1.0.1")), IsTrue)
+
+	// Expired records are only removed when a new source is recorded, and there are 4096 records or more.
+	l.Fail(net.ParseAddress("10.2.0.1"))
+	assert(len(l.records), Equals, 6144)
+	l.Fail(net.ParseAddress("192.168.0.1"))
+	assert(len(l.records), Equals, 4097)
+	assert(l.Banned(net.ParseAddress("192.168.0.1")), IsTrue)
+	assert(l.Banned(net.ParseAddress("10.1.0.1")), IsTrue)
+	assert(l.records["10.2.0.1"].failures, Equals, uint32(2))
+
+	l.records = make(map[string]*banRecord)
+	addRecords("10.0.", banRecord{failures: 1, since: now.Add(-time.Hour), until: now.Add(-time.Minute)})
+	l.Fail(net.ParseAddress("192.168.0.2"))
+	assert(len(l.records), Equals, 2049)
+}
@@ -142,7 +142,7 @@ func NewHandler(ctx context.Context, config *core.InboundHandlerConfig) (core.In
 		return NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
 	}
 
-	if allocStrategy.Type == proxyman.AllocationStrategy_Random {
+	if allocStrategy.Type == proxyman.AllocationStrategy_Random || allocStrategy.Type == proxyman.AllocationStrategy_External {
 		return NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
 	}
 	return nil, newError("unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type).AtError()
@@ -33,6 +33,9 @@ type tcpWorker struct {
 	proxy           proxy.Inbound
 	stream          *internet.StreamConfig
 	recvOrigDest    bool
+	proxyProtocol   proxyman.ReceiverConfig_ProxyProtocol
+	trustedProxies  []*net.IPNet
+	filter          *sourceFilter
 	tag             string
 	dispatcher      core.Dispatcher
 	sniffers        []proxyman.KnownProtocols
@@ -43,6 +46,15 @@ type tcpWorker struct {
 }
 
 func (w *tcpWorker) callback(conn internet.Connection) {
+	source := net.DestinationFromAddr(conn.RemoteAddr())
+	if !w.filter.Allow(source.Address) {
+		newError("rejecting connection from ", source).AtDebug().WriteToLog()
+		if err := conn.Close(); err != nil {
+			newError("failed to close connection").Base(err).WriteToLog()
+		}
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	sid := session.NewID()
 	ctx = session.ContextWithID(ctx, sid)
@@ -60,7 +72,10 @@ func (w *tcpWorker) callback(conn internet.Connection) {
 		ctx = proxy.ContextWithInboundTag(ctx, w.tag)
 	}
 	ctx = proxy.ContextWithInboundEntryPoint(ctx, net.TCPDestination(w.address, w.port))
-	ctx = proxy.ContextWithSource(ctx, net.DestinationFromAddr(conn.RemoteAddr()))
+	ctx = proxy.ContextWithSource(ctx, source)
+	if handler := w.filter.FailureHandler(source.Address); handler != nil {
+		ctx = proxy.ContextWithAuthFailureHandler(ctx, handler)
+	}
 	if len(w.sniffers) > 0 {
 		ctx = proxyman.ContextWithProtocolSniffers(ctx, w.sniffers)
 	}
@@ -86,6 +101,9 @@ func (w *tcpWorker) Proxy() proxy.Inbound {
 
 func (w *tcpWorker) Start() error {
 	ctx := internet.ContextWithStreamSettings(context.Background(), w.stream)
+	if w.proxyProtocol != proxyman.ReceiverConfig_Disabled {
+		ctx = internet.ContextWithProxyProtocol(ctx, w.proxyProtocol == proxyman.ReceiverConfig_Required, w.trustedProxies)
+	}
 	hub, err := internet.ListenTCP(ctx, w.address, w.port, func(conn internet.Connection) {
 		go w.callback(conn)
 	})
@@ -193,6 +211,7 @@ type udpWorker struct {
 	dispatcher      core.Dispatcher
 	uplinkCounter   core.StatCounter
 	downlinkCounter core.StatCounter
+	filter          *sourceFilter
 
 	done       *signal.Done
 	activeConn map[connID]*udpConn
@@ -230,6 +249,11 @@ func (w *udpWorker) getConnection(id connID) (*udpConn, bool) {
 }
 
 func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest net.Destination) {
+	if !w.filter.Allow(source.Address) {
+		b.Release()
+		return
+	}
+
 	id := connID{
 		src: source,
 	}
@@ -258,6 +282,9 @@ func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest
 				ctx = proxy.ContextWithInboundTag(ctx, w.tag)
 			}
 			ctx = proxy.ContextWithSource(ctx, source)
+			if handler := w.filter.FailureHandler(source.Address); handler != nil {
+				ctx = proxy.ContextWithAuthFailureHandler(ctx, handler)
+			}
 			ctx = proxy.ContextWithInboundEntryPoint(ctx, net.UDPDestination(w.address, w.port))
 			if err := w.proxy.Process(ctx, net.Network_UDP, conn, w.dispatcher); err != nil {
 				newError("connection ends").Base(err).WriteToLog()
@@ -0,0 +1,131 @@
+package mux
+
+import (
+	"io"
+	"sync"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/transport/pipe"
+)
+
+// defaultWindow is the number of bytes that the peer may send in a session before it receives more credit.
+const defaultWindow uint32 = 512 * 1024
+
+// sendWindow tracks the credit granted by the peer for sending data in a session.
+// It doesn't limit anything until the first grant, so that peers without flow control keep working.
+type sendWindow struct {
+	access   sync.Mutex
+	enabled  bool
+	granted  uint64
+	sent     uint64
+	notifier *signal.Notifier
+	done     *signal.Done
+}
+
+func newSendWindow() *sendWindow {
+	return &sendWindow{
+		notifier: signal.NewNotifier(),
+		done:     signal.NewDone(),
+	}
+}
+
+// Grant adds credit for sending data, and enables flow control if it is not yet.
+func (w *sendWindow) Grant(credit uint32) {
+	w.access.Lock()
+	w.enabled = true
+	w.granted += uint64(credit)
+	w.access.Unlock()
+
+	w.notifier.Signal()
+}
+
+// consume blocks until there is enough credit for sending n bytes, or the window is closed.
+func (w *sendWindow) consume(n uint32) error {
+	for {
+		w.access.Lock()
+		if !w.enabled || w.sent+uint64(n) <= w.granted {
+			w.sent += uint64(n)
+			w.access.Unlock()
+			return nil
+		}
+		w.access.Unlock()
+
+		select {
+		case <-w.notifier.Wait():
+		case <-w.done.Wait():
+			return io.ErrClosedPipe
+		}
+	}
+}
+
+// Close unblocks all pending sends.
+func (w *sendWindow) Close() error {
+	return w.done.Close()
+}
+
+func writeCredit(writer buf.Writer, id uint16, credit uint32) error {
+	meta := FrameMetadata{
+		SessionID:     id,
+		SessionStatus: SessionStatusKeep,
+		Option:        OptionCredit,
+		Credit:        credit,
+	}
+	frame := buf.New()
+	common.Must(meta.WriteTo(frame))
+	return writer.WriteMultiBuffer(buf.NewMultiBufferValue(frame))
+}
+
+// creditWriter writes data into the output of a session, and grants credit to the peer once enough data is written.
+type creditWriter struct {
+	id      uint16
+	output  buf.Writer
+	link    buf.Writer
+	pending uint32
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *creditWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	n := uint32(mb.Len())
+	if err := w.output.WriteMultiBuffer(mb); err != nil {
+		return err
+	}
+	w.pending += n
+	if w.pending < defaultWindow/2 {
+		return nil
+	}
+	credit := w.pending
+	w.pending = 0
+	return writeCredit(w.link, w.id, credit)
+}
+
+// newCreditOutput returns a buf.Writer that takes the place of the output of a session, and grants credit to the peer through the link as data is consumed by the output.
+// As long as the peer respects the window of the session, writes to the returned Writer never block, so a slow session doesn't stall other sessions in the same connection.
+// Peers without flow control are still limited by the buffer size of the pipe.
+func newCreditOutput(id uint16, output buf.Writer, link buf.Writer) buf.Writer {
+	reader, writer := pipe.New()
+	go func() {
+		w := &creditWriter{
+			id:     id,
+			output: output,
+			link:   link,
+		}
+		if err := buf.Copy(reader, w); err != nil {
+			pipe.CloseError(output)
+			pipe.CloseError(reader)
+			return
+		}
+		common.Close(output)
+	}()
+	return writer
+}
+
+func writeKeepAlive(writer buf.Writer) error {
+	meta := FrameMetadata{
+		SessionStatus: SessionStatusKeepAlive,
+	}
+	frame := buf.New()
+	common.Must(meta.WriteTo(frame))
+	return writer.WriteMultiBuffer(buf.NewMultiBufferValue(frame))
+}
@@ -20,6 +20,11 @@ const (
 const (
 	OptionData  bitmask.Byte = 0x01
 	OptionError bitmask.Byte = 0x02
+	// OptionCredit indicates that the frame carries a credit grant for flow control. Peers that don't support flow control ignore it.
+	OptionCredit bitmask.Byte = 0x04
+	// OptionPacketAddr indicates that the frame carries the address of its UDP packet.
+	// In SessionStatusNew, it also indicates that the session is full-cone, i.e., packets in the session have their own addresses.
+	OptionPacketAddr bitmask.Byte = 0x08
 )
 
 type TargetNetwork byte
@@ -47,6 +52,12 @@ Frame format
 2 bytes - port
 n bytes - address
 
+1 byte - network, only if OptionPacketAddr is set in SessionStatusKeep
+2 bytes - port
+n bytes - address
+
+4 bytes - credit, only if OptionCredit is set
+
 */
 
 type FrameMetadata struct {
@@ -54,6 +65,8 @@ type FrameMetadata struct {
 	SessionID     uint16
 	Option        bitmask.Byte
 	SessionStatus SessionStatus
+	// Credit is the number of bytes that the peer is additionally allowed to send in this session. Only valid with OptionCredit.
+	Credit uint32
 }
 
 func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
@@ -67,7 +80,7 @@ func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
 
 	b.AppendBytes(byte(f.SessionStatus), byte(f.Option))
 
-	if f.SessionStatus == SessionStatusNew {
+	if f.SessionStatus == SessionStatusNew || (f.SessionStatus == SessionStatusKeep && f.Option.Has(OptionPacketAddr)) {
 		switch f.Target.Network {
 		case net.Network_TCP:
 			b.AppendBytes(byte(TargetNetworkTCP))
@@ -80,6 +93,12 @@ func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
 		}
 	}
 
+	if f.Option.Has(OptionCredit) {
+		if err := b.AppendSupplier(serial.WriteUint32(f.Credit)); err != nil {
+			return err
+		}
+	}
+
 	len1 := b.Len()
 	serial.Uint16ToBytes(uint16(len1-len0), lenBytes)
 	return nil
@@ -96,7 +115,7 @@ func ReadFrameFrom(b *buf.Buffer) (*FrameMetadata, error) {
 		Option:        bitmask.Byte(b.Byte(3)),
 	}
 
-	if f.SessionStatus == SessionStatusNew {
+	if f.SessionStatus == SessionStatusNew || (f.SessionStatus == SessionStatusKeep && f.Option.Has(OptionPacketAddr)) {
 		network := TargetNetwork(b.Byte(4))
 		b.Advance(5)
 
@@ -113,6 +132,15 @@ func ReadFrameFrom(b *buf.Buffer) (*FrameMetadata, error) {
 		default:
 			return nil, newError("unknown network type: ", network)
 		}
+	} else {
+		b.Advance(4)
+	}
+
+	if f.Option.Has(OptionCredit) {
+		if b.Len() < 4 {
+			return nil, newError("insufficient buffer for credit: ", b.Len())
+		}
+		f.Credit = serial.BytesToUint32(b.BytesTo(4))
 	}
 
 	return f, nil
@@ -6,6 +6,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"v2ray.com/core"
@@ -18,6 +19,8 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
 	"v2ray.com/core/transport/pipe"
 )
 
@@ -45,16 +48,32 @@ func (m *ClientManager) Dispatch(ctx context.Context, link *core.Link) error {
 	m.access.Lock()
 	defer m.access.Unlock()
 
-	for _, client := range m.clients {
-		if client.Dispatch(ctx, link) {
-			return nil
+	// Connections of a Client are dialed through the SystemDialer in the context, e.g. the previous hops of a chain.
+	// Clients are shared only by links that go through the same proxies.
+	systemDialer := internet.SystemDialerFromContext(ctx)
+	dialerTag, shared := "", true
+	if systemDialer != nil {
+		if d, ok := systemDialer.(internet.ProxySystemDialer); ok {
+			dialerTag = d.Tag()
+		} else {
+			shared = false
+		}
+	}
+
+	if shared {
+		for _, client := range m.clients {
+			if client.dialerTag == dialerTag && client.shared && client.Dispatch(ctx, link) {
+				return nil
+			}
 		}
 	}
 
-	client, err := NewClient(m.proxy, m.dialer, m)
+	client, err := NewClient(m.proxy, m.dialer, m, systemDialer)
 	if err != nil {
 		return newError("failed to create client").Base(err)
 	}
+	client.dialerTag = dialerTag
+	client.shared = shared
 	m.clients = append(m.clients, client)
 	client.Dispatch(ctx, link)
 	return nil
@@ -78,16 +97,32 @@ type Client struct {
 	sessionManager *SessionManager
 	link           core.Link
 	done           *signal.Done
+	retired        *signal.Done
 	manager        *ClientManager
 	concurrency    uint32
+	maxReuse       uint16
+	expire         time.Time
+	keepalive      time.Duration
+	dialerTag      string
+	shared         bool
+
+	// lastRead is the UnixNano time of the last frame received from the peer.
+	lastRead int64
+	// peerAnswers is set to 1 once the peer replies to a keepalive frame.
+	peerAnswers uint32
+	// pingTime is only accessed by the monitor goroutine.
+	pingTime time.Time
 }
 
 var muxCoolAddress = net.DomainAddress("v1.mux.cool")
 var muxCoolPort = net.Port(9527)
 
-// NewClient creates a new mux.Client.
-func NewClient(p proxy.Outbound, dialer proxy.Dialer, m *ClientManager) (*Client, error) {
+// NewClient creates a new mux.Client. Its connection is dialed through the given SystemDialer, if not nil.
+func NewClient(p proxy.Outbound, dialer proxy.Dialer, m *ClientManager, systemDialer internet.SystemDialer) (*Client, error) {
 	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(muxCoolAddress, muxCoolPort))
+	if systemDialer != nil {
+		ctx = internet.ContextWithSystemDialer(ctx, systemDialer)
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	uplinkReader, upLinkWriter := pipe.New()
 	downlinkReader, downlinkWriter := pipe.New()
@@ -99,8 +134,18 @@ func NewClient(p proxy.Outbound, dialer proxy.Dialer, m *ClientManager) (*Client
 			Writer: upLinkWriter,
 		},
 		done:        signal.NewDone(),
+		retired:     signal.NewDone(),
 		manager:     m,
 		concurrency: m.config.Concurrency,
+		maxReuse:    maxTotal,
+		keepalive:   time.Duration(m.config.KeepaliveInterval) * time.Second,
+		lastRead:    time.Now().UnixNano(),
+	}
+	if r := m.config.MaxReuse; r > 0 && r < 65535 {
+		c.maxReuse = uint16(r)
+	}
+	if l := m.config.MaxLifetime; l > 0 {
+		c.expire = time.Now().Add(time.Duration(l) * time.Second)
 	}
 
 	go func() {
@@ -121,10 +166,60 @@ func (m *Client) Closed() bool {
 	return m.done.Done()
 }
 
+// retire stops the Client from accepting new sessions. Existing sessions are kept until they finish.
+func (m *Client) retire() {
+	if m.retired.Done() {
+		return
+	}
+	newError("retiring mux connection after ", m.sessionManager.Count(), " sessions").AtDebug().WriteToLog()
+	common.Must(m.retired.Close())
+}
+
+func (m *Client) shouldRetire() bool {
+	if m.sessionManager.Count() >= int(m.maxReuse) {
+		return true
+	}
+	return !m.expire.IsZero() && time.Now().After(m.expire)
+}
+
+// checkKeepAlive sends a keepalive frame when the connection has been idle for too long. It returns false if
+// the peer failed to answer a previous keepalive frame, which means the underlying connection is dead.
+func (m *Client) checkKeepAlive() bool {
+	now := time.Now()
+	lastRead := time.Unix(0, atomic.LoadInt64(&m.lastRead))
+	if now.Sub(lastRead) < m.keepalive {
+		return true
+	}
+
+	if !m.pingTime.IsZero() && lastRead.Before(m.pingTime) {
+		// The previous keepalive frame is not answered yet.
+		if now.Sub(m.pingTime) < m.keepalive {
+			return true
+		}
+		if atomic.LoadUint32(&m.peerAnswers) == 1 {
+			return false
+		}
+	}
+
+	m.pingTime = now
+	if err := writeKeepAlive(m.link.Writer); err != nil {
+		newError("failed to send keepalive").Base(err).WriteToLog()
+	}
+	return true
+}
+
+func (m *Client) monitorInterval() time.Duration {
+	interval := time.Second * 16
+	if m.keepalive > 0 && m.keepalive < interval {
+		interval = m.keepalive
+	}
+	return interval
+}
+
 func (m *Client) monitor() {
 	defer m.manager.onClientFinish()
 
-	timer := time.NewTicker(time.Second * 16)
+	timer := time.NewTicker(m.monitorInterval())
 	defer timer.Stop()
 
 	for {
@@ -135,6 +230,14 @@ func (m *Client) monitor() {
 			pipe.CloseError(m.link.Reader)
 			return
 		case <-timer.C:
+			if m.shouldRetire() {
+				m.retire()
+			}
+			if m.keepalive > 0 && !m.checkKeepAlive() {
+				newError("mux connection is not responding to keepalive").AtWarning().WriteToLog()
+				common.Must(m.done.Close())
+				continue
+			}
 			size := m.sessionManager.Size()
 			if size == 0 && m.sessionManager.CloseIfNoSession() {
 				common.Must(m.done.Close())
@@ -165,6 +268,9 @@ func fetchInput(ctx context.Context, s *Session, output buf.Writer) {
 	}
 	s.transferType = transferType
 	writer := NewWriter(s.ID, dest, output, transferType)
+	writer.window = s.window
+	writer.grant = defaultWindow
+	writer.fullCone = s.fullCone
 	defer s.Close()
 	defer writer.Close()
 
@@ -186,11 +292,16 @@ func fetchInput(ctx context.Context, s *Session, output buf.Writer) {
 
 func (m *Client) Dispatch(ctx context.Context, link *core.Link) bool {
 	sm := m.sessionManager
-	if sm.Size() >= int(m.concurrency) || sm.Count() >= maxTotal {
+	if sm.Size() >= int(m.concurrency) || sm.Count() >= int(m.maxReuse) {
+		return false
+	}
+
+	if m.done.Done() || m.retired.Done() {
 		return false
 	}
 
-	if m.done.Done() {
+	if !m.expire.IsZero() && time.Now().After(m.expire) {
+		m.retire()
 		return false
 	}
 
@@ -199,7 +310,12 @@ func (m *Client) Dispatch(ctx context.Context, link *core.Link) bool {
 		return false
 	}
 	s.input = link.Reader
-	s.output = link.Writer
+	s.output = newCreditOutput(s.ID, link.Writer, m.link.Writer)
+	s.window = newSendWindow()
+	if dest, _ := proxy.TargetFromContext(ctx); dest.Network == net.Network_UDP && udp.IsFullCone(ctx) {
+		s.fullCone = true
+		s.target = dest
+	}
 	go fetchInput(ctx, s, m.link.Writer)
 	return true
 }
@@ -223,13 +339,18 @@ func (m *Client) handleStatusNew(meta *FrameMetadata, reader *buf.BufferedReader
 }
 
 func (m *Client) handleStatusKeep(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	s, found := m.sessionManager.Get(meta.SessionID)
+	if found {
+		s.grant(meta)
+	}
+
 	if !meta.Option.Has(OptionData) {
 		return nil
 	}
 
-	if s, found := m.sessionManager.Get(meta.SessionID); found {
+	if found {
 		rr := s.NewReader(reader)
-		if err := buf.Copy(rr, s.output); err != nil {
+		if err := buf.Copy(rr, s.dataWriter(meta)); err != nil {
 			drain(rr)
 			pipe.CloseError(s.input)
 			return s.Close()
@@ -268,9 +389,11 @@ func (m *Client) fetchOutput() {
 			}
 			break
 		}
+		atomic.StoreInt64(&m.lastRead, time.Now().UnixNano())
 
 		switch meta.SessionStatus {
 		case SessionStatusKeepAlive:
+			atomic.StoreUint32(&m.peerAnswers, 1)
 			err = m.handleStatueKeepAlive(meta, reader)
 		case SessionStatusEnd:
 			err = m.handleStatusEnd(meta, reader)
@@ -310,15 +433,10 @@ func (s *Server) Dispatch(ctx context.Context, dest net.Destination) (*core.Link
 	uplinkReader, uplinkWriter := pipe.New()
 	downlinkReader, downlinkWriter := pipe.New()
 
-	worker := &ServerWorker{
-		dispatcher: s.dispatcher,
-		link: &core.Link{
-			Reader: uplinkReader,
-			Writer: downlinkWriter,
-		},
-		sessionManager: NewSessionManager(),
-	}
-	go worker.run(ctx)
+	NewServerWorker(ctx, s.dispatcher, &core.Link{
+		Reader: uplinkReader,
+		Writer: downlinkWriter,
+	})
 	return &core.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
 }
 
@@ -336,8 +454,26 @@ type ServerWorker struct {
 	sessionManager *SessionManager
 }
 
+// NewServerWorker creates a ServerWorker that serves the Mux connection on the given link, until the link or the context is closed.
+func NewServerWorker(ctx context.Context, d core.Dispatcher, link *core.Link) *ServerWorker {
+	worker := &ServerWorker{
+		dispatcher:     d,
+		link:           link,
+		sessionManager: NewSessionManager(),
+	}
+	go worker.run(ctx)
+	return worker
+}
+
 func handle(ctx context.Context, s *Session, output buf.Writer) {
 	writer := NewResponseWriter(s.ID, output, s.transferType)
+	writer.fullCone = s.fullCone
+	if s.window != nil {
+		writer.window = s.window
+		if err := writeCredit(output, s.ID, defaultWindow); err != nil {
+			newError("failed to grant credit for session ", s.ID).Base(err).WithContext(ctx).WriteToLog()
+		}
+	}
 	if err := buf.Copy(s.input, writer); err != nil {
 		newError("session ", s.ID, " ends.").Base(err).WithContext(ctx).WriteToLog()
 		writer.hasError = true
@@ -349,9 +485,12 @@ func handle(ctx context.Context, s *Session, output buf.Writer) {
 
 func (w *ServerWorker) handleStatusKeepAlive(meta *FrameMetadata, reader *buf.BufferedReader) error {
 	if meta.Option.Has(OptionData) {
-		return drain(NewStreamReader(reader))
+		if err := drain(NewStreamReader(reader)); err != nil {
+			return err
+		}
 	}
-	return nil
+	// Answer the keepalive so that the client knows the connection is still alive.
+	return writeKeepAlive(w.link.Writer)
 }
 
 func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata, reader *buf.BufferedReader) error {
@@ -367,6 +506,10 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 		}
 		log.Record(msg)
 	}
+	fullCone := meta.Target.Network == net.Network_UDP && meta.Option.Has(OptionPacketAddr)
+	if fullCone {
+		ctx = udp.ContextWithFullCone(ctx)
+	}
 	link, err := w.dispatcher.Dispatch(ctx, meta.Target)
 	if err != nil {
 		if meta.Option.Has(OptionData) {
@@ -380,10 +523,18 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 		parent:       w.sessionManager,
 		ID:           meta.SessionID,
 		transferType: protocol.TransferTypeStream,
+		fullCone:     fullCone,
+		target:       meta.Target,
 	}
 	if meta.Target.Network == net.Network_UDP {
 		s.transferType = protocol.TransferTypePacket
 	}
+	if meta.Option.Has(OptionCredit) {
+		// The client supports flow control.
+		s.window = newSendWindow()
+		s.window.Grant(meta.Credit)
+		s.output = newCreditOutput(s.ID, link.Writer, w.link.Writer)
+	}
 	w.sessionManager.Add(s)
 	go handle(ctx, s, w.link.Writer)
 	if !meta.Option.Has(OptionData) {
@@ -391,7 +542,7 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 	}
 
 	rr := s.NewReader(reader)
-	if err := buf.Copy(rr, s.output); err != nil {
+	if err := buf.Copy(rr, s.dataWriter(meta)); err != nil {
 		drain(rr)
 		pipe.CloseError(s.input)
 		return s.Close()
@@ -400,12 +551,17 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 }
 
 func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	s, found := w.sessionManager.Get(meta.SessionID)
+	if found {
+		s.grant(meta)
+	}
+
 	if !meta.Option.Has(OptionData) {
 		return nil
 	}
-	if s, found := w.sessionManager.Get(meta.SessionID); found {
+	if found {
 		rr := s.NewReader(reader)
-		if err := buf.Copy(rr, s.output); err != nil {
+		if err := buf.Copy(rr, s.dataWriter(meta)); err != nil {
 			drain(rr)
 			pipe.CloseError(s.input)
 			return s.Close()
@@ -1,13 +1,23 @@
 package mux_test
 
 import (
+	"context"
+	"crypto/rand"
 	"io"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
 	. "v2ray.com/core/app/proxyman/mux"
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
 	"v2ray.com/core/transport/pipe"
 	. "v2ray.com/ext/assert"
 )
@@ -138,3 +148,349 @@ func TestReaderWriter(t *testing.T) {
 	assert(err, IsNotNil)
 	assert(meta, IsNil)
 }
+
+func TestFrameCredit(t *testing.T) {
+	assert := With(t)
+
+	frames := []FrameMetadata{
+		{
+			SessionID:     1,
+			SessionStatus: SessionStatusNew,
+			Option:        OptionCredit | OptionData,
+			Target:        net.TCPDestination(net.DomainAddress("v2ray.com"), 80),
+			Credit:        65536,
+		},
+		{
+			SessionID:     2,
+			SessionStatus: SessionStatusKeep,
+			Option:        OptionCredit,
+			Credit:        1024,
+		},
+		{
+			SessionID:     3,
+			SessionStatus: SessionStatusKeep,
+			Option:        OptionPacketAddr | OptionCredit | OptionData,
+			Target:        net.UDPDestination(net.LocalHostIPv6, 53),
+			Credit:        2048,
+		},
+	}
+
+	for _, f := range frames {
+		b := buf.New()
+		assert(f.WriteTo(b), IsNil)
+
+		meta, err := ReadMetadata(b)
+		assert(err, IsNil)
+		assert(meta.SessionID, Equals, f.SessionID)
+		assert(byte(meta.SessionStatus), Equals, byte(f.SessionStatus))
+		assert(byte(meta.Option), Equals, byte(f.Option))
+		assert(meta.Target, Equals, f.Target)
+		assert(meta.Credit, Equals, f.Credit)
+		b.Release()
+	}
+}
+
+type testDispatcher struct {
+	dispatch func(dest net.Destination) *core.Link
+}
+
+func (d *testDispatcher) Start() error { return nil }
+func (d *testDispatcher) Close() error { return nil }
+
+func (d *testDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*core.Link, error) {
+	return d.dispatch(dest), nil
+}
+
+// serverOutbound serves Mux connections with a ServerWorker, in place of a remote server.
+type serverOutbound struct {
+	dispatcher core.Dispatcher
+	done       chan struct{}
+}
+
+func (o *serverOutbound) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	NewServerWorker(ctx, o.dispatcher, link)
+	<-o.done
+	return nil
+}
+
+func TestSlowSessionNotBlockingOthers(t *testing.T) {
+	assert := With(t)
+
+	dispatcher := &testDispatcher{
+		dispatch: func(dest net.Destination) *core.Link {
+			uplinkReader, uplinkWriter := pipe.New()
+			downlinkReader, downlinkWriter := pipe.New()
+			go buf.Copy(uplinkReader, buf.Discard)
+			go func() {
+				if dest.Port == 80 {
+					// Much more data than the slow client is able to receive.
+					for i := 0; i < 16384; i++ {
+						b := buf.New()
+						common.Must(b.Reset(buf.ReadFullFrom(rand.Reader, buf.Size)))
+						if err := downlinkWriter.WriteMultiBuffer(buf.NewMultiBufferValue(b)); err != nil {
+							return
+						}
+					}
+					return
+				}
+				b := buf.New()
+				b.Write([]byte("hello"))
+				downlinkWriter.WriteMultiBuffer(buf.NewMultiBufferValue(b))
+			}()
+			return &core.Link{Reader: downlinkReader, Writer: uplinkWriter}
+		},
+	}
+
+	outbound := &serverOutbound{
+		dispatcher: dispatcher,
+		done:       make(chan struct{}),
+	}
+	defer close(outbound.done)
+
+	manager := NewClientManager(outbound, nil, &proxyman.MultiplexingConfig{
+		Enabled:     true,
+		Concurrency: 8,
+	})
+
+	dispatch := func(port net.Port, opts ...pipe.Option) *pipe.Reader {
+		ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.LocalHostIP, port))
+		uplinkReader, _ := pipe.New()
+		downlinkReader, downlinkWriter := pipe.New(opts...)
+		assert(manager.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter}), IsNil)
+		return downlinkReader
+	}
+
+	// Nobody reads from the slow session, and its buffer is small.
+	dispatch(80, pipe.WithSizeLimit(1024))
+	time.Sleep(time.Second)
+
+	reader := dispatch(443)
+	mb, err := reader.ReadMultiBufferWithTimeout(time.Second * 5)
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "hello")
+}
+
+func TestFullConeUDP(t *testing.T) {
+	assert := With(t)
+
+	dispatcher := &testDispatcher{
+		dispatch: func(dest net.Destination) *core.Link {
+			uplinkReader, uplinkWriter := pipe.New()
+			downlinkReader, downlinkWriter := pipe.New()
+			// Echo every packet back, as if it came from its destination.
+			go buf.Copy(uplinkReader, downlinkWriter)
+			return &core.Link{Reader: downlinkReader, Writer: uplinkWriter}
+		},
+	}
+
+	outbound := &serverOutbound{
+		dispatcher: dispatcher,
+		done:       make(chan struct{}),
+	}
+	defer close(outbound.done)
+
+	manager := NewClientManager(outbound, nil, &proxyman.MultiplexingConfig{
+		Enabled:     true,
+		Concurrency: 8,
+	})
+
+	dest1 := net.UDPDestination(net.LocalHostIP, 53)
+	dest2 := net.UDPDestination(net.DomainAddress("v2ray.com"), 443)
+
+	ctx := udp.ContextWithFullCone(proxy.ContextWithTarget(context.Background(), dest1))
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	assert(manager.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter}), IsNil)
+
+	for _, dest := range []net.Destination{dest1, dest2} {
+		b := buf.New()
+		b.Write([]byte(dest.String()))
+		assert(uplinkWriter.WriteMultiBuffer(buf.NewMultiBufferValue(udp.EncodePacket(b, dest))), IsNil)
+	}
+
+	responses := make(map[net.Destination]string)
+	for len(responses) < 2 {
+		mb, err := downlinkReader.ReadMultiBufferWithTimeout(time.Second * 5)
+		assert(err, IsNil)
+		for _, b := range mb {
+			source, err := udp.DecodePacket(b)
+			assert(err, IsNil)
+			responses[source] = b.String()
+			b.Release()
+		}
+	}
+	assert(responses[dest1], Equals, dest1.String())
+	assert(responses[dest2], Equals, dest2.String())
+}
+
+type countingOutbound struct {
+	serverOutbound
+	count int32
+}
+
+func (o *countingOutbound) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	atomic.AddInt32(&o.count, 1)
+	return o.serverOutbound.Process(ctx, link, dialer)
+}
+
+func TestMaxReuse(t *testing.T) {
+	assert := With(t)
+
+	dispatcher := &testDispatcher{
+		dispatch: func(dest net.Destination) *core.Link {
+			uplinkReader, uplinkWriter := pipe.New()
+			downlinkReader, _ := pipe.New()
+			go buf.Copy(uplinkReader, buf.Discard)
+			return &core.Link{Reader: downlinkReader, Writer: uplinkWriter}
+		},
+	}
+
+	outbound := &countingOutbound{
+		serverOutbound: serverOutbound{
+			dispatcher: dispatcher,
+			done:       make(chan struct{}),
+		},
+	}
+	defer close(outbound.done)
+
+	manager := NewClientManager(outbound, nil, &proxyman.MultiplexingConfig{
+		Enabled:     true,
+		Concurrency: 8,
+		MaxReuse:    2,
+	})
+
+	for i := 0; i < 5; i++ {
+		ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.LocalHostIP, net.Port(80+i)))
+		uplinkReader, _ := pipe.New()
+		_, downlinkWriter := pipe.New()
+		assert(manager.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter}), IsNil)
+	}
+
+	time.Sleep(time.Millisecond * 500)
+	assert(atomic.LoadInt32(&outbound.count), Equals, int32(3))
+}
+
+// silentOutbound answers the first keepalive frame and then stops responding.
+type silentOutbound struct {
+	keepalives int32
+}
+
+func (o *silentOutbound) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	reader := &buf.BufferedReader{Reader: link.Reader}
+	for {
+		meta, err := ReadMetadata(reader)
+		if err != nil {
+			return nil
+		}
+		if meta.Option.Has(OptionData) {
+			if _, err := readAll(NewStreamReader(reader)); err != nil {
+				return err
+			}
+		}
+		if meta.SessionStatus == SessionStatusKeepAlive && atomic.AddInt32(&o.keepalives, 1) == 1 {
+			frame := buf.New()
+			common.Must(meta.WriteTo(frame))
+			if err := link.Writer.WriteMultiBuffer(buf.NewMultiBufferValue(frame)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func TestKeepAliveDeadConnection(t *testing.T) {
+	assert := With(t)
+
+	outbound := &silentOutbound{}
+	manager := NewClientManager(outbound, nil, &proxyman.MultiplexingConfig{
+		Enabled:           true,
+		Concurrency:       8,
+		KeepaliveInterval: 1,
+	})
+
+	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.LocalHostIP, 80))
+	uplinkReader, _ := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	assert(manager.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter}), IsNil)
+
+	// The session is closed once the client notices the peer stopped answering keepalives.
+	_, err := downlinkReader.ReadMultiBufferWithTimeout(time.Second * 10)
+	assert(err, IsNotNil)
+	assert(err, Not(Equals), buf.ErrReadTimeout)
+	assert(atomic.LoadInt32(&outbound.keepalives) >= 2, IsTrue)
+}
+
+type taggedDialer struct {
+	internet.DefaultSystemDialer
+	tag string
+}
+
+func (d *taggedDialer) Tag() string {
+	return d.tag
+}
+
+type dialerOutbound struct {
+	tags chan string
+	done chan struct{}
+}
+
+func (o *dialerOutbound) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	tag := "none"
+	if d, ok := internet.SystemDialerFromContext(ctx).(internet.ProxySystemDialer); ok {
+		tag = d.Tag()
+	}
+	o.tags <- tag
+	<-o.done
+	return nil
+}
+
+func TestClientKeepsSystemDialer(t *testing.T) {
+	assert := With(t)
+
+	outbound := &dialerOutbound{
+		tags: make(chan string, 4),
+		done: make(chan struct{}),
+	}
+	defer close(outbound.done)
+
+	manager := NewClientManager(outbound, nil, &proxyman.MultiplexingConfig{
+		Enabled:     true,
+		Concurrency: 8,
+	})
+
+	dispatch := func(dialer internet.SystemDialer) {
+		ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.LocalHostIP, 80))
+		if dialer != nil {
+			ctx = internet.ContextWithSystemDialer(ctx, dialer)
+		}
+		uplinkReader, _ := pipe.New()
+		_, downlinkWriter := pipe.New()
+		assert(manager.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter}), IsNil)
+	}
+
+	nextTag := func() string {
+		select {
+		case tag := <-outbound.tags:
+			return tag
+		case <-time.After(time.Second * 5):
+			return "timeout"
+		}
+	}
+
+	dispatch(&taggedDialer{tag: "hop1"})
+	assert(nextTag(), Equals, "hop1")
+
+	// Links through the same proxies share the client.
+	dispatch(&taggedDialer{tag: "hop1"})
+
+	dispatch(nil)
+	assert(nextTag(), Equals, "none")
+
+	dispatch(&taggedDialer{tag: "hop2"})
+	assert(nextTag(), Equals, "hop2")
+
+	select {
+	case tag := <-outbound.tags:
+		t.Error("unexpected client for ", tag)
+	default:
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/transport/internet/udp"
 )
 
 type SessionManager struct {
@@ -118,6 +120,9 @@ func (m *SessionManager) Close() error {
 	m.closed = true
 
 	for _, s := range m.sessions {
+		if s.window != nil {
+			s.window.Close()
+		}
 		common.Close(s.input)
 		common.Close(s.output)
 	}
@@ -133,10 +138,17 @@ type Session struct {
 	parent       *SessionManager
 	ID           uint16
 	transferType protocol.TransferType
+	window       *sendWindow
+	// fullCone indicates that the input and output of this session are full-cone links. See udp.ContextWithFullCone.
+	fullCone bool
+	target   net.Destination
 }
 
 // Close closes all resources associated with this session.
 func (s *Session) Close() error {
+	if s.window != nil {
+		s.window.Close()
+	}
 	common.Close(s.output)
 	common.Close(s.input)
 	s.parent.Remove(s.ID)
@@ -150,3 +162,25 @@ func (s *Session) NewReader(reader *buf.BufferedReader) buf.Reader {
 	}
 	return NewPacketReader(reader)
 }
+
+// grant adds credit for sending data in this session, if the frame carries any.
+func (s *Session) grant(meta *FrameMetadata) {
+	if meta.Option.Has(OptionCredit) && s.window != nil {
+		s.window.Grant(meta.Credit)
+	}
+}
+
+// dataWriter returns a buf.Writer for the data in the given frame. In full-cone sessions, the data is written as a packet with the address in the frame.
+func (s *Session) dataWriter(meta *FrameMetadata) buf.Writer {
+	if !s.fullCone {
+		return s.output
+	}
+	addr := s.target
+	if meta.Option.Has(OptionPacketAddr) {
+		addr = meta.Target
+	}
+	return &udp.PacketWriter{
+		Writer: s.output,
+		Source: addr,
+	}
+}
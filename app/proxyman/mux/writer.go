@@ -6,6 +6,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
+	"v2ray.com/core/transport/internet/udp"
 )
 
 type Writer struct {
@@ -15,6 +16,12 @@ type Writer struct {
 	followup     bool
 	hasError     bool
 	transferType protocol.TransferType
+	// window limits data sent by this Writer. Nil if not limited.
+	window *sendWindow
+	// grant is the credit announced to the peer in the first frame, if non-zero.
+	grant uint32
+	// fullCone indicates that written buffers are full-cone packets, whose addresses are sent along with them.
+	fullCone bool
 }
 
 func NewWriter(id uint16, dest net.Destination, writer buf.Writer, transferType protocol.TransferType) *Writer {
@@ -47,6 +54,15 @@ func (w *Writer) getNextFrameMeta() FrameMetadata {
 	} else {
 		w.followup = true
 		meta.SessionStatus = SessionStatusNew
+		if w.fullCone {
+			meta.Option.Set(OptionPacketAddr)
+		}
+	}
+
+	if w.grant > 0 {
+		meta.Option.Set(OptionCredit)
+		meta.Credit = w.grant
+		w.grant = 0
 	}
 
 	return meta
@@ -62,7 +78,31 @@ func (w *Writer) writeMetaOnly() error {
 }
 
 func (w *Writer) writeData(mb buf.MultiBuffer) error {
+	return w.writeFrame(w.getNextFrameMeta(), mb)
+}
+
+// writePacket writes a full-cone packet along with its address.
+func (w *Writer) writePacket(b *buf.Buffer) error {
+	addr, err := udp.DecodePacket(b)
+	if err != nil {
+		b.Release()
+		return err
+	}
+
 	meta := w.getNextFrameMeta()
+	meta.Option.Set(OptionPacketAddr)
+	meta.Target = addr
+	return w.writeFrame(meta, buf.NewMultiBufferValue(b))
+}
+
+func (w *Writer) writeFrame(meta FrameMetadata, mb buf.MultiBuffer) error {
+	if w.window != nil {
+		if err := w.window.consume(uint32(mb.Len())); err != nil {
+			mb.Release()
+			return err
+		}
+	}
+
 	meta.Option.Set(OptionData)
 
 	frame := buf.New()
@@ -88,6 +128,13 @@ func (w *Writer) WriteMultiBuffer(mb buf.MultiBuffer) error {
 	}
 
 	for !mb.IsEmpty() {
+		if w.fullCone {
+			if err := w.writePacket(mb.SplitFirst()); err != nil {
+				return err
+			}
+			continue
+		}
+
 		var chunk buf.MultiBuffer
 		if w.transferType == protocol.TransferTypeStream {
 			chunk = mb.SliceBySize(8 * 1024)
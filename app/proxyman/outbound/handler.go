@@ -10,14 +10,41 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
 	"v2ray.com/core/transport/pipe"
 )
 
+// proxyDialer is an internet.SystemDialer that tunnels connections through another outbound handler.
+type proxyDialer struct {
+	tag     string
+	handler core.OutboundHandler
+}
+
+// Tag implements internet.ProxySystemDialer.
+func (d *proxyDialer) Tag() string {
+	return d.tag
+}
+
+// Dial implements internet.SystemDialer.
+func (d *proxyDialer) Dial(ctx context.Context, src net.Address, dest net.Destination) (net.Conn, error) {
+	// The upstream handler dials with its own settings. Keeping this dialer in the context would make it dial through itself.
+	ctx = internet.ContextWithoutDialerSettings(ctx)
+	ctx = udp.ContextWithoutFullCone(ctx)
+	ctx = proxy.ContextWithTarget(ctx, dest)
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+
+	go d.handler.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	return net.NewConnection(net.ConnectionInputMulti(uplinkWriter), net.ConnectionOutputMulti(downlinkReader)), nil
+}
+
 type Handler struct {
 	config          *core.OutboundHandlerConfig
 	senderSettings  *proxyman.SenderConfig
 	proxy           proxy.Outbound
 	outboundManager core.OutboundHandlerManager
+	router          core.Router
 	mux             *mux.ClientManager
 }
 
@@ -26,6 +53,7 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (core.O
 	h := &Handler{
 		config:          config,
 		outboundManager: v.OutboundHandlerManager(),
+		router:          v.Router(),
 	}
 
 	if config.SenderSettings != nil {
@@ -73,17 +101,78 @@ func (h *Handler) Tag() string {
 	return h.config.Tag
 }
 
+// dependencies returns tags of other outbound handlers that this handler sends traffic through.
+func (h *Handler) dependencies() []string {
+	var tags []string
+	if h.senderSettings != nil && h.senderSettings.ProxySettings.HasTag() {
+		tags = append(tags, h.senderSettings.ProxySettings.Tag)
+	}
+	if chain, ok := h.proxy.(proxy.Chain); ok {
+		tags = append(tags, chain.Hops()...)
+	}
+	return tags
+}
+
+// supportFullCone returns true if this handler sends full-cone UDP links as is, either by Mux or by the proxy itself.
+func (h *Handler) supportFullCone() bool {
+	if h.mux != nil {
+		return true
+	}
+	p, ok := h.proxy.(proxy.FullConeOutbound)
+	return ok && p.SupportFullCone()
+}
+
+// routes returns true if traffic to the given destination is routed to this handler, as the dispatcher would do.
+func (h *Handler) routes(ctx context.Context, dest net.Destination) bool {
+	if tag, err := h.router.PickRoute(proxy.ContextWithTarget(ctx, dest)); err == nil && h.outboundManager.GetHandler(tag) != nil {
+		return tag == h.Tag()
+	}
+	return h.outboundManager.GetDefaultHandler() == h
+}
+
 // Dispatch implements proxy.Outbound.Dispatch.
 func (h *Handler) Dispatch(ctx context.Context, link *core.Link) {
+	if udp.IsFullCone(ctx) {
+		target, _ := proxy.TargetFromContext(ctx)
+		switch {
+		case target.Network != net.Network_UDP:
+			// The flag is inherited from the dispatch of another handler, and this link is not full-cone.
+			ctx = udp.ContextWithoutFullCone(ctx)
+		case !h.supportFullCone():
+			// Fall back to a plain UDP link to the first destination.
+			link = &core.Link{
+				Reader: &udp.PayloadReader{Reader: link.Reader, Target: target},
+				Writer: &udp.PacketWriter{Writer: link.Writer, Source: target},
+			}
+			ctx = udp.ContextWithoutFullCone(ctx)
+		default:
+			// Only the first destination was routed by the dispatcher. Packets to other destinations must be routed
+			// to this handler as well.
+			link = &core.Link{
+				Reader: &udp.FilterReader{
+					Reader: link.Reader,
+					Allow: func(dest net.Destination) bool {
+						if dest == target || h.routes(ctx, dest) {
+							return true
+						}
+						newError("dropping full-cone packets to ", dest, ", which is not routed to [", h.Tag(), "]").WithContext(ctx).WriteToLog()
+						return false
+					},
+				},
+				Writer: link.Writer,
+			}
+		}
+	}
+
 	if h.mux != nil {
 		if err := h.mux.Dispatch(ctx, link); err != nil {
-			newError("failed to process mux outbound traffic").Base(err).WithContext(ctx).WriteToLog()
+			newError("failed to process mux outbound traffic of [", h.Tag(), "]").Base(err).WithContext(ctx).WriteToLog()
 			pipe.CloseError(link.Writer)
 		}
 	} else {
 		if err := h.proxy.Process(ctx, link, h); err != nil {
 			// Ensure outbound ray is properly closed.
-			newError("failed to process outbound traffic").Base(err).WithContext(ctx).WriteToLog()
+			newError("failed to process outbound traffic of [", h.Tag(), "]").Base(err).WithContext(ctx).WriteToLog()
 			pipe.CloseError(link.Writer)
 		} else {
 			common.Must(common.Close(link.Writer))
@@ -92,6 +181,48 @@ func (h *Handler) Dispatch(ctx context.Context, link *core.Link) {
 	}
 }
 
+// listenIP returns the local IP that this handler dials from, or nil if not specified.
+func (h *Handler) listenIP() (net.IP, error) {
+	if h.senderSettings == nil {
+		return nil, nil
+	}
+	if h.senderSettings.ProxySettings.HasTag() {
+		return nil, newError("unable to listen for [", h.Tag(), "], whose traffic goes through [", h.senderSettings.ProxySettings.Tag, "]")
+	}
+	if h.senderSettings.Via != nil {
+		if via := h.senderSettings.Via.AsAddress(); !via.Family().IsDomain() {
+			return via.IP(), nil
+		}
+	}
+	return nil, nil
+}
+
+// Listen implements proxy.Listener. If this handler has no send-through address, it listens on the address of the
+// inbound, so that peers connect to the same address as clients do.
+func (h *Handler) Listen(ctx context.Context) (net.Listener, error) {
+	ip, err := h.listenIP()
+	if err != nil {
+		return nil, err
+	}
+	if entry, ok := proxy.InboundEntryPointFromContext(ctx); ip == nil && ok && !entry.Address.Family().IsDomain() && !entry.Address.IP().IsUnspecified() {
+		ip = entry.Address.IP()
+	}
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: ip})
+	if err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
+
+// ListenUDP implements proxy.Listener.
+func (h *Handler) ListenUDP(ctx context.Context) (*net.UDPConn, error) {
+	ip, err := h.listenIP()
+	if err != nil {
+		return nil, err
+	}
+	return net.ListenUDP("udp", &net.UDPAddr{IP: ip})
+}
+
 // Dial implements proxy.Dialer.Dial().
 func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
 	if h.senderSettings != nil {
@@ -100,16 +231,11 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 			handler := h.outboundManager.GetHandler(tag)
 			if handler != nil {
 				newError("proxying to ", tag, " for dest ", dest).AtDebug().WithContext(ctx).WriteToLog()
-				ctx = proxy.ContextWithTarget(ctx, dest)
-
-				uplinkReader, uplinkWriter := pipe.New()
-				downlinkReader, downlinkWriter := pipe.New()
-
-				go handler.Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter})
-				return net.NewConnection(net.ConnectionInputMulti(uplinkWriter), net.ConnectionOutputMulti(downlinkReader)), nil
+				// Transport and security settings below still apply, on top of the tunnelled connection.
+				ctx = internet.ContextWithSystemDialer(ctx, &proxyDialer{tag: tag, handler: handler})
+			} else {
+				newError("failed to get outbound handler with tag: ", tag).AtWarning().WithContext(ctx).WriteToLog()
 			}
-
-			newError("failed to get outbound handler with tag: ", tag).AtWarning().WithContext(ctx).WriteToLog()
 		}
 
 		if h.senderSettings.Via != nil {
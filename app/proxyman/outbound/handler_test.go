@@ -1,10 +1,30 @@
 package outbound_test
 
 import (
+	"context"
+	"io"
 	"testing"
+	"time"
 
 	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
 	. "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/chain"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/servers/tcp"
+	udpserver "v2ray.com/core/testing/servers/udp"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
+	"v2ray.com/core/transport/pipe"
 	. "v2ray.com/ext/assert"
 )
 
@@ -14,3 +34,331 @@ func TestInterfaces(t *testing.T) {
 	assert((*Handler)(nil), Implements, (*core.OutboundHandler)(nil))
 	assert((*Manager)(nil), Implements, (*core.OutboundHandlerManager)(nil))
 }
+
+func TestChainLoop(t *testing.T) {
+	assert := With(t)
+
+	_, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ProxySettings: &internet.ProxyConfig{
+						Tag: "chain",
+					},
+				}),
+			},
+			{
+				Tag: "chain",
+				ProxySettings: serial.ToTypedMessage(&chain.Config{
+					Tag: []string{"blackhole", "direct"},
+				}),
+			},
+			{
+				Tag:           "blackhole",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	})
+	assert(err, IsNotNil)
+	assert(err.Error(), HasSubstring, "loop")
+}
+
+func TestAddHandlerLoop(t *testing.T) {
+	assert := With(t)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ProxySettings: &internet.ProxyConfig{
+						Tag: "chain",
+					},
+				}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	defer v.Close()
+
+	ohm := v.OutboundHandlerManager()
+	addHandler := func(config *core.OutboundHandlerConfig) error {
+		handler, err := v.CreateObject(config)
+		assert(err, IsNil)
+		return ohm.AddHandler(context.Background(), handler.(core.OutboundHandler))
+	}
+
+	// The handler is rejected before the instance starts, and it is not added.
+	loop := &core.OutboundHandlerConfig{
+		Tag: "chain",
+		ProxySettings: serial.ToTypedMessage(&chain.Config{
+			Tag: []string{"direct"},
+		}),
+	}
+	err = addHandler(loop)
+	assert(err, IsNotNil)
+	assert(err.Error(), HasSubstring, "loop")
+	assert(ohm.GetHandler("chain"), IsNil)
+
+	assert(addHandler(&core.OutboundHandlerConfig{
+		Tag:           "chain",
+		ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+	}), IsNil)
+	assert(v.Start(), IsNil)
+
+	// A handler replacing another one is rejected as well, and the previous one is kept.
+	err = addHandler(loop)
+	assert(err, IsNotNil)
+	_, isChain := ohm.GetHandler("chain").(*Handler).GetOutbound().(proxy.Chain)
+	assert(isChain, IsFalse)
+}
+
+func TestDialThroughProxyTag(t *testing.T) {
+	assert := With(t)
+
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte { return b },
+	}
+	dest, err := tcpServer.Start()
+	assert(err, IsNil)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "main",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ProxySettings: &internet.ProxyConfig{
+						Tag: "upstream",
+					},
+				}),
+			},
+			{
+				Tag:           "upstream",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dialer := v.OutboundHandlerManager().GetHandler("main").(proxy.Dialer)
+	conn, err := dialer.Dial(ctx, dest)
+	assert(err, IsNil)
+	defer conn.Close()
+
+	payload := []byte("test payload")
+	_, err = conn.Write(payload)
+	assert(err, IsNil)
+
+	response := make([]byte, len(payload))
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadFull(conn, response)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert(err, IsNil)
+		assert(response, Equals, payload)
+	case <-ctx.Done():
+		t.Fatal("timeout while dialing through upstream handler")
+	}
+}
+
+func TestDispatchIgnoresInheritedFullCone(t *testing.T) {
+	assert := With(t)
+
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte { return b },
+	}
+	dest, err := tcpServer.Start()
+	assert(err, IsNil)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag: "direct",
+				// Freedom doesn't support full-cone UDP with destination override.
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{
+					DestinationOverride: &freedom.DestinationOverride{
+						Server: &protocol.ServerEndpoint{
+							Address: net.NewIPOrDomain(dest.Address),
+							Port:    uint32(dest.Port),
+						},
+					},
+				}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	// A TCP link dispatched from within a full-cone UDP link, e.g. by a handler that dials through this one.
+	ctx := udp.ContextWithFullCone(context.Background())
+	ctx = proxy.ContextWithTarget(ctx, dest)
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	go v.OutboundHandlerManager().GetHandler("direct").Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter})
+
+	payload := buf.New()
+	payload.Write([]byte("test payload"))
+	assert(uplinkWriter.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+	mb, err := downlinkReader.ReadMultiBufferWithTimeout(time.Second * 5)
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "test payload")
+	uplinkWriter.Close()
+}
+
+func TestListen(t *testing.T) {
+	assert := With(t)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					Via: net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+			{
+				Tag:           "chained",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ProxySettings: &internet.ProxyConfig{
+						Tag: "direct",
+					},
+				}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	listener := v.OutboundHandlerManager().GetHandler("direct").(proxy.Listener)
+	tcpListener, err := listener.Listen(context.Background())
+	assert(err, IsNil)
+	assert(tcpListener.Addr().(*net.TCPAddr).IP.String(), Equals, "127.0.0.1")
+	tcpListener.Close()
+	udpConn, err := listener.ListenUDP(context.Background())
+	assert(err, IsNil)
+	assert(udpConn.LocalAddr().(*net.UDPAddr).IP.String(), Equals, "127.0.0.1")
+	udpConn.Close()
+
+	// Sockets can't be sent through other handlers.
+	listener = v.OutboundHandlerManager().GetHandler("chained").(proxy.Listener)
+	_, err = listener.Listen(context.Background())
+	assert(err, IsNotNil)
+	_, err = listener.ListenUDP(context.Background())
+	assert(err, IsNotNil)
+}
+
+func TestFullConeRouting(t *testing.T) {
+	assert := With(t)
+
+	echo := func(b []byte) []byte { return b }
+	udpServer1 := udpserver.Server{MsgProcessor: echo}
+	dest1, err := udpServer1.Start()
+	assert(err, IsNil)
+	defer udpServer1.Close()
+	udpServer2 := udpserver.Server{MsgProcessor: echo}
+	dest2, err := udpServer2.Start()
+	assert(err, IsNil)
+	defer udpServer2.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						Tag:       "blocked",
+						PortRange: net.SinglePortRange(dest2.Port),
+					},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag:           "blocked",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	})
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	defer v.Close()
+
+	ctx := udp.ContextWithFullCone(context.Background())
+	ctx = proxy.ContextWithTarget(ctx, dest1)
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	go v.OutboundHandlerManager().GetHandler("direct").Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	defer uplinkWriter.Close()
+
+	// Packets to destinations that are routed to other handlers are dropped.
+	var mb buf.MultiBuffer
+	for _, dest := range []net.Destination{dest2, dest1} {
+		b := buf.New()
+		b.Write([]byte(dest.String()))
+		mb.Append(udp.EncodePacket(b, dest))
+	}
+	assert(uplinkWriter.WriteMultiBuffer(mb), IsNil)
+
+	mb, err = downlinkReader.ReadMultiBufferWithTimeout(time.Second * 5)
+	assert(err, IsNil)
+	assert(len(mb), Equals, 1)
+	source, err := udp.DecodePacket(mb[0])
+	assert(err, IsNil)
+	assert(source, Equals, dest1)
+	assert(mb.String(), Equals, dest1.String())
+
+	_, err = downlinkReader.ReadMultiBufferWithTimeout(time.Second)
+	assert(err, Equals, buf.ErrReadTimeout)
+}
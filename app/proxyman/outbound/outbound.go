@@ -4,6 +4,7 @@ package outbound
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"v2ray.com/core"
@@ -32,11 +33,55 @@ func New(ctx context.Context, config *proxyman.OutboundConfig) (*Manager, error)
 	return m, nil
 }
 
+// checkCycle returns an error if tagged handlers send traffic through each other in a loop, either by proxy settings or by chains.
+func (m *Manager) checkCycle() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+
+	var visit func(tag string, path []string) error
+	visit = func(tag string, path []string) error {
+		path = append(path, tag)
+		switch state[tag] {
+		case visiting:
+			return newError("outbound handlers form a loop: ", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		handler, ok := m.taggedHandler[tag].(*Handler)
+		if !ok {
+			return nil
+		}
+		state[tag] = visiting
+		for _, dep := range handler.dependencies() {
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		state[tag] = visited
+		return nil
+	}
+
+	for tag := range m.taggedHandler {
+		if err := visit(tag, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Start implements core.Feature
 func (m *Manager) Start() error {
 	m.access.Lock()
 	defer m.access.Unlock()
 
+	if err := m.checkCycle(); err != nil {
+		return err
+	}
+
 	m.running = true
 
 	for _, h := range m.taggedHandler {
@@ -98,17 +143,27 @@ func (m *Manager) AddHandler(ctx context.Context, handler core.OutboundHandler)
 	m.access.Lock()
 	defer m.access.Unlock()
 
-	if m.defaultHandler == nil {
-		m.defaultHandler = handler
-	}
-
 	tag := handler.Tag()
 	if len(tag) > 0 {
+		// A loop is formed by the last handler added to it, which is rejected, whether or not the manager is running.
+		previous, found := m.taggedHandler[tag]
 		m.taggedHandler[tag] = handler
+		if err := m.checkCycle(); err != nil {
+			if found {
+				m.taggedHandler[tag] = previous
+			} else {
+				delete(m.taggedHandler, tag)
+			}
+			return err
+		}
 	} else {
 		m.untaggedHandlers = append(m.untaggedHandlers, handler)
 	}
 
+	if m.defaultHandler == nil {
+		m.defaultHandler = handler
+	}
+
 	if m.running {
 		return handler.Start()
 	}
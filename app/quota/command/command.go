@@ -0,0 +1,80 @@
+package command
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg command -path App,Quota,Command
+
+import (
+	"context"
+
+	grpc "google.golang.org/grpc"
+	"v2ray.com/core"
+	"v2ray.com/core/app/quota"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
+)
+
+type quotaServer struct {
+	manager *quota.Manager
+}
+
+// NewQuotaServer creates a QuotaServiceServer on top of the given quota Manager.
+func NewQuotaServer(manager *quota.Manager) QuotaServiceServer {
+	return &quotaServer{
+		manager: manager,
+	}
+}
+
+func (s *quotaServer) GetQuota(ctx context.Context, request *GetQuotaRequest) (*GetQuotaResponse, error) {
+	q, used, found := s.manager.GetQuota(&protocol.User{
+		Email: request.Email,
+		Level: request.Level,
+	})
+	if !found {
+		return nil, newError(request.Email, " not found.")
+	}
+	return &GetQuotaResponse{
+		Quota: &UserQuota{
+			Email:  request.Email,
+			Bytes:  q.Bytes,
+			Expire: q.Expire,
+			Used:   used,
+		},
+	}, nil
+}
+
+func (s *quotaServer) SetQuota(ctx context.Context, request *SetQuotaRequest) (*SetQuotaResponse, error) {
+	if len(request.Email) == 0 {
+		return nil, newError("empty email.")
+	}
+	s.manager.SetQuota(request.Email, quota.Quota{
+		Bytes:  request.Bytes,
+		Expire: request.Expire,
+	})
+	return &SetQuotaResponse{}, nil
+}
+
+func (s *quotaServer) ResetQuota(ctx context.Context, request *ResetQuotaRequest) (*ResetQuotaResponse, error) {
+	if !s.manager.ResetQuota(request.Email) {
+		return nil, newError(request.Email, " not found.")
+	}
+	return &ResetQuotaResponse{}, nil
+}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	manager, ok := s.v.GetFeature((*quota.Manager)(nil)).(*quota.Manager)
+	if !ok {
+		newError("quota manager is not configured").AtWarning().WriteToLog()
+		return
+	}
+	RegisterQuotaServiceServer(server, NewQuotaServer(manager))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
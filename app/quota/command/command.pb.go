@@ -0,0 +1,356 @@
+package command
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	"context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type UserQuota struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	// Total number of bytes that the user may transfer. Unlimited if 0.
+	Bytes uint64 `protobuf:"varint,2,opt,name=bytes" json:"bytes,omitempty"`
+	// Unix time in seconds after which the user is no longer allowed. Never expires if 0.
+	Expire int64 `protobuf:"varint,3,opt,name=expire" json:"expire,omitempty"`
+	// Number of bytes that the user has transferred.
+	Used uint64 `protobuf:"varint,4,opt,name=used" json:"used,omitempty"`
+}
+
+func (m *UserQuota) Reset()                    { *m = UserQuota{} }
+func (m *UserQuota) String() string            { return proto.CompactTextString(m) }
+func (*UserQuota) ProtoMessage()               {}
+func (*UserQuota) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *UserQuota) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UserQuota) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *UserQuota) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+func (m *UserQuota) GetUsed() uint64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+type GetQuotaRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	// Level of the user. The default quota of the level is returned if the user has no quota of its own.
+	Level uint32 `protobuf:"varint,2,opt,name=level" json:"level,omitempty"`
+}
+
+func (m *GetQuotaRequest) Reset()                    { *m = GetQuotaRequest{} }
+func (m *GetQuotaRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetQuotaRequest) ProtoMessage()               {}
+func (*GetQuotaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *GetQuotaRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *GetQuotaRequest) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+type GetQuotaResponse struct {
+	Quota *UserQuota `protobuf:"bytes,1,opt,name=quota" json:"quota,omitempty"`
+}
+
+func (m *GetQuotaResponse) Reset()                    { *m = GetQuotaResponse{} }
+func (m *GetQuotaResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetQuotaResponse) ProtoMessage()               {}
+func (*GetQuotaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *GetQuotaResponse) GetQuota() *UserQuota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+type SetQuotaRequest struct {
+	Email  string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Bytes  uint64 `protobuf:"varint,2,opt,name=bytes" json:"bytes,omitempty"`
+	Expire int64  `protobuf:"varint,3,opt,name=expire" json:"expire,omitempty"`
+}
+
+func (m *SetQuotaRequest) Reset()                    { *m = SetQuotaRequest{} }
+func (m *SetQuotaRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetQuotaRequest) ProtoMessage()               {}
+func (*SetQuotaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *SetQuotaRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *SetQuotaRequest) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *SetQuotaRequest) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+type SetQuotaResponse struct {
+}
+
+func (m *SetQuotaResponse) Reset()                    { *m = SetQuotaResponse{} }
+func (m *SetQuotaResponse) String() string            { return proto.CompactTextString(m) }
+func (*SetQuotaResponse) ProtoMessage()               {}
+func (*SetQuotaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type ResetQuotaRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *ResetQuotaRequest) Reset()                    { *m = ResetQuotaRequest{} }
+func (m *ResetQuotaRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetQuotaRequest) ProtoMessage()               {}
+func (*ResetQuotaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ResetQuotaRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type ResetQuotaResponse struct {
+}
+
+func (m *ResetQuotaResponse) Reset()                    { *m = ResetQuotaResponse{} }
+func (m *ResetQuotaResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResetQuotaResponse) ProtoMessage()               {}
+func (*ResetQuotaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type Config struct {
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func init() {
+	proto.RegisterType((*UserQuota)(nil), "v2ray.core.app.quota.command.UserQuota")
+	proto.RegisterType((*GetQuotaRequest)(nil), "v2ray.core.app.quota.command.GetQuotaRequest")
+	proto.RegisterType((*GetQuotaResponse)(nil), "v2ray.core.app.quota.command.GetQuotaResponse")
+	proto.RegisterType((*SetQuotaRequest)(nil), "v2ray.core.app.quota.command.SetQuotaRequest")
+	proto.RegisterType((*SetQuotaResponse)(nil), "v2ray.core.app.quota.command.SetQuotaResponse")
+	proto.RegisterType((*ResetQuotaRequest)(nil), "v2ray.core.app.quota.command.ResetQuotaRequest")
+	proto.RegisterType((*ResetQuotaResponse)(nil), "v2ray.core.app.quota.command.ResetQuotaResponse")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.quota.command.Config")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for QuotaService service
+
+type QuotaServiceClient interface {
+	GetQuota(ctx context.Context, in *GetQuotaRequest, opts ...grpc.CallOption) (*GetQuotaResponse, error)
+	SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error)
+	ResetQuota(ctx context.Context, in *ResetQuotaRequest, opts ...grpc.CallOption) (*ResetQuotaResponse, error)
+}
+
+type quotaServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewQuotaServiceClient(cc *grpc.ClientConn) QuotaServiceClient {
+	return &quotaServiceClient{cc}
+}
+
+func (c *quotaServiceClient) GetQuota(ctx context.Context, in *GetQuotaRequest, opts ...grpc.CallOption) (*GetQuotaResponse, error) {
+	out := new(GetQuotaResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.quota.command.QuotaService/GetQuota", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quotaServiceClient) SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*SetQuotaResponse, error) {
+	out := new(SetQuotaResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.quota.command.QuotaService/SetQuota", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quotaServiceClient) ResetQuota(ctx context.Context, in *ResetQuotaRequest, opts ...grpc.CallOption) (*ResetQuotaResponse, error) {
+	out := new(ResetQuotaResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.quota.command.QuotaService/ResetQuota", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for QuotaService service
+
+type QuotaServiceServer interface {
+	GetQuota(context.Context, *GetQuotaRequest) (*GetQuotaResponse, error)
+	SetQuota(context.Context, *SetQuotaRequest) (*SetQuotaResponse, error)
+	ResetQuota(context.Context, *ResetQuotaRequest) (*ResetQuotaResponse, error)
+}
+
+func RegisterQuotaServiceServer(s *grpc.Server, srv QuotaServiceServer) {
+	s.RegisterService(&_QuotaService_serviceDesc, srv)
+}
+
+func _QuotaService_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuotaServiceServer).GetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.quota.command.QuotaService/GetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuotaServiceServer).GetQuota(ctx, req.(*GetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuotaService_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuotaServiceServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.quota.command.QuotaService/SetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuotaServiceServer).SetQuota(ctx, req.(*SetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuotaService_ResetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuotaServiceServer).ResetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.quota.command.QuotaService/ResetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuotaServiceServer).ResetQuota(ctx, req.(*ResetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _QuotaService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.quota.command.QuotaService",
+	HandlerType: (*QuotaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetQuota",
+			Handler:    _QuotaService_GetQuota_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _QuotaService_SetQuota_Handler,
+		},
+		{
+			MethodName: "ResetQuota",
+			Handler:    _QuotaService_ResetQuota_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2ray.com/core/app/quota/command/command.proto",
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/quota/command/command.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 360 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xbf, 0x4e, 0xeb, 0x30,
+	0x14, 0xc6, 0x6f, 0xfa, 0xef, 0xb6, 0xe7, 0x5e, 0xd4, 0x62, 0x55, 0x28, 0xaa, 0x3a, 0x44, 0x59,
+	0x28, 0x03, 0x0e, 0x0a, 0x73, 0x07, 0xe8, 0xc0, 0xc2, 0x40, 0x1d, 0x95, 0x81, 0xcd, 0x4d, 0x0f,
+	0x28, 0xa2, 0xa9, 0x5d, 0x3b, 0xad, 0xe8, 0x2b, 0xf1, 0x4a, 0xbc, 0x0c, 0x8a, 0x93, 0x50, 0xd4,
+	0x8a, 0x28, 0x4c, 0xf1, 0xb1, 0xbf, 0xcf, 0xbf, 0x73, 0xbe, 0xc8, 0x40, 0xb7, 0xbe, 0xe2, 0x3b,
+	0x1a, 0x8a, 0xd8, 0x0b, 0x85, 0x42, 0x8f, 0x4b, 0xe9, 0xad, 0x37, 0x22, 0xe1, 0x5e, 0x28, 0xe2,
+	0x98, 0xaf, 0x16, 0xc5, 0x97, 0x4a, 0x25, 0x12, 0x41, 0x86, 0x85, 0x5e, 0x21, 0xe5, 0x52, 0x52,
+	0xa3, 0xa5, 0xb9, 0xc6, 0x0d, 0xa1, 0x33, 0xd3, 0xa8, 0xa6, 0xe9, 0x26, 0xe9, 0x43, 0x13, 0x63,
+	0x1e, 0x2d, 0x6d, 0xcb, 0xb1, 0x46, 0x1d, 0x96, 0x15, 0xe9, 0xee, 0x7c, 0x97, 0xa0, 0xb6, 0x6b,
+	0x8e, 0x35, 0x6a, 0xb0, 0xac, 0x20, 0x67, 0xd0, 0xc2, 0x37, 0x19, 0x29, 0xb4, 0xeb, 0x8e, 0x35,
+	0xaa, 0xb3, 0xbc, 0x22, 0x04, 0x1a, 0x1b, 0x8d, 0x0b, 0xbb, 0x61, 0xc4, 0x66, 0xed, 0x8e, 0xa1,
+	0x7b, 0x87, 0x89, 0x61, 0x30, 0x5c, 0x6f, 0x50, 0x27, 0x3f, 0xa3, 0x96, 0xb8, 0xc5, 0xa5, 0x41,
+	0x9d, 0xb0, 0xac, 0x70, 0xa7, 0xd0, 0xdb, 0xdb, 0xb5, 0x14, 0x2b, 0x8d, 0x64, 0x0c, 0x4d, 0x33,
+	0x88, 0xf1, 0xff, 0xf3, 0xcf, 0x69, 0xd9, 0x94, 0xf4, 0x6b, 0x44, 0x96, 0xb9, 0xdc, 0x19, 0x74,
+	0x83, 0xaa, 0x1d, 0x55, 0x1f, 0xde, 0x25, 0xd0, 0x0b, 0x0e, 0x3a, 0x75, 0x2f, 0xe0, 0x94, 0xa1,
+	0xae, 0x02, 0x73, 0xfb, 0x40, 0xbe, 0x4b, 0xf3, 0x0b, 0xda, 0xd0, 0x9a, 0x88, 0xd5, 0x73, 0xf4,
+	0xe2, 0x7f, 0xd4, 0xe0, 0xbf, 0x39, 0x0b, 0x50, 0x6d, 0xa3, 0x10, 0xc9, 0x2b, 0xb4, 0x8b, 0x64,
+	0xc8, 0x65, 0x79, 0x04, 0x07, 0x3f, 0x60, 0x40, 0xab, 0xca, 0xf3, 0x2e, 0xfe, 0xa4, 0xb0, 0xa0,
+	0x22, 0x2c, 0xf8, 0x1d, 0x2c, 0x38, 0x86, 0xad, 0x01, 0xf6, 0x51, 0x10, 0xaf, 0xdc, 0x7f, 0x94,
+	0xef, 0xe0, 0xaa, 0xba, 0xa1, 0x40, 0xde, 0xde, 0x83, 0x13, 0x8a, 0xb8, 0xd4, 0xf8, 0x60, 0x3d,
+	0xfd, 0xcd, 0x97, 0xef, 0xb5, 0xe1, 0xa3, 0xcf, 0xf8, 0x8e, 0x4e, 0x52, 0xe5, 0x8d, 0x94, 0xd4,
+	0xdc, 0x47, 0x27, 0xd9, 0xf1, 0xbc, 0x65, 0x5e, 0xdf, 0xf5, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff,
+	0x74, 0x3b, 0x65, 0xa8, 0xaf, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,98 @@
+package quota
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Quota limits the traffic of a user.
+type Quota struct {
+	// Total number of bytes, in both directions, that the user may transfer. Unlimited if 0.
+	Bytes uint64 `protobuf:"varint,1,opt,name=bytes" json:"bytes,omitempty"`
+	// Unix time in seconds after which the user is no longer allowed. Never expires if 0.
+	Expire int64 `protobuf:"varint,2,opt,name=expire" json:"expire,omitempty"`
+}
+
+func (m *Quota) Reset()                    { *m = Quota{} }
+func (m *Quota) String() string            { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()               {}
+func (*Quota) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Quota) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *Quota) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+type Config struct {
+	// Quotas of users, keyed by email.
+	User map[string]*Quota `protobuf:"bytes,1,rep,name=user" json:"user,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Default quota of each user in a user level, keyed by level. Each user gets a quota of its own.
+	Level map[uint32]*Quota `protobuf:"bytes,2,rep,name=level" json:"level,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Config) GetUser() map[string]*Quota {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *Config) GetLevel() map[uint32]*Quota {
+	if m != nil {
+		return m.Level
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Quota)(nil), "v2ray.core.app.quota.Quota")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.quota.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/quota/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 277 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x91, 0x4d, 0x4b, 0xc4, 0x30,
+	0x10, 0x86, 0x49, 0x77, 0x5b, 0xd8, 0x59, 0x04, 0x09, 0x45, 0xc2, 0x7a, 0x29, 0x0b, 0x6a, 0x4f,
+	0x29, 0x56, 0x04, 0x29, 0x78, 0xd0, 0xc5, 0x9b, 0x07, 0x0d, 0xae, 0x07, 0x6f, 0xd9, 0x32, 0xca,
+	0x62, 0x77, 0x13, 0xd3, 0x0f, 0xcc, 0x5f, 0xf2, 0xe4, 0x4f, 0x94, 0x26, 0x7e, 0x1d, 0x8a, 0x17,
+	0x6f, 0xef, 0xd0, 0xe7, 0x7d, 0x66, 0x4a, 0xe0, 0xa0, 0xcb, 0x8d, 0xb4, 0xbc, 0x54, 0x9b, 0xac,
+	0x54, 0x06, 0x33, 0xa9, 0x75, 0xf6, 0xd2, 0xaa, 0x46, 0x66, 0xa5, 0xda, 0x3e, 0xae, 0x9f, 0xb8,
+	0x36, 0xaa, 0x51, 0x34, 0xfe, 0xc2, 0x0c, 0x72, 0xa9, 0x35, 0x77, 0xc8, 0xfc, 0x14, 0xc2, 0xdb,
+	0x3e, 0xd0, 0x18, 0xc2, 0x95, 0x6d, 0xb0, 0x66, 0x24, 0x21, 0xe9, 0x58, 0xf8, 0x81, 0xee, 0x41,
+	0x84, 0xaf, 0x7a, 0x6d, 0x90, 0x05, 0x09, 0x49, 0x47, 0xe2, 0x73, 0x9a, 0xbf, 0x07, 0x10, 0x2d,
+	0x9c, 0x9d, 0x16, 0x30, 0x6e, 0x6b, 0x34, 0x8c, 0x24, 0xa3, 0x74, 0x9a, 0x1f, 0xf2, 0xa1, 0x35,
+	0xdc, 0xb3, 0x7c, 0x59, 0xa3, 0xb9, 0xda, 0x36, 0xc6, 0x0a, 0xd7, 0xa1, 0xe7, 0x10, 0x56, 0xd8,
+	0x61, 0xc5, 0x02, 0x57, 0x3e, 0xfa, 0xb3, 0x7c, 0xdd, 0x93, 0xbe, 0xed, 0x5b, 0xb3, 0x3b, 0x98,
+	0x7c, 0x1b, 0xe9, 0x2e, 0x8c, 0x9e, 0xd1, 0xba, 0xf3, 0x27, 0xa2, 0x8f, 0xf4, 0x18, 0xc2, 0x4e,
+	0x56, 0xad, 0xbf, 0x7d, 0x9a, 0xef, 0x0f, 0xdb, 0xdd, 0xef, 0x0b, 0x4f, 0x16, 0xc1, 0x19, 0x99,
+	0x2d, 0x01, 0x7e, 0x56, 0xfd, 0xd6, 0xee, 0xfc, 0x47, 0x7b, 0x59, 0x00, 0x2b, 0xd5, 0x66, 0x10,
+	0xbe, 0x21, 0x0f, 0xa1, 0x0b, 0x6f, 0x41, 0x7c, 0x9f, 0x0b, 0x69, 0xf9, 0xa2, 0xff, 0x7e, 0xa1,
+	0xb5, 0xd7, 0xac, 0x22, 0xf7, 0x84, 0x27, 0x1f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x76, 0xc3, 0x64,
+	0x84, 0xeb, 0x01, 0x00, 0x00,
+}
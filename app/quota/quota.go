@@ -0,0 +1,304 @@
+package quota
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg quota -path App,Quota
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/signal"
+)
+
+var (
+	errExceeded = newError("quota exceeded")
+	errExpired  = newError("quota expired")
+)
+
+type userState struct {
+	// bytes and expire are the quota of the user. They are accessed atomically.
+	bytes  uint64
+	expire int64
+	used   core.StatCounter
+
+	access      sync.Mutex
+	connections map[*Connection]struct{}
+}
+
+func (s *userState) setQuota(quota Quota) {
+	atomic.StoreUint64(&s.bytes, quota.Bytes)
+	atomic.StoreInt64(&s.expire, quota.Expire)
+}
+
+func (s *userState) quota() Quota {
+	return Quota{
+		Bytes:  atomic.LoadUint64(&s.bytes),
+		Expire: atomic.LoadInt64(&s.expire),
+	}
+}
+
+func (s *userState) check(now time.Time) error {
+	if expire := atomic.LoadInt64(&s.expire); expire > 0 && now.Unix() >= expire {
+		return errExpired
+	}
+	if bytes := atomic.LoadUint64(&s.bytes); bytes > 0 && uint64(s.used.Value()) >= bytes {
+		return errExceeded
+	}
+	return nil
+}
+
+// interrupt closes all active connections of the user, and returns the number of them.
+func (s *userState) interrupt() int {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	for c := range s.connections {
+		c.interrupt()
+	}
+	return len(s.connections)
+}
+
+// Manager is a V2Ray feature that enforces traffic quotas of users.
+// Once a user runs out of quota, new connections of the user are refused and existing ones are closed.
+// Used bytes of each user are kept in a reserved counter of the StatManager, if any, so that they are persisted along with other stats,
+// but can't be reset by clients of StatsService.
+type Manager struct {
+	access sync.Mutex
+	stats  core.StatManager
+	levels map[uint32]Quota
+	quotas map[string]Quota
+	users  map[string]*userState
+	task   *signal.PeriodicTask
+}
+
+// New creates a new Manager based on the given config.
+func New(ctx context.Context, config *Config) (*Manager, error) {
+	m := &Manager{
+		levels: make(map[uint32]Quota),
+		quotas: make(map[string]Quota),
+		users:  make(map[string]*userState),
+	}
+	for level, q := range config.Level {
+		if q != nil {
+			m.levels[level] = *q
+		}
+	}
+	for email, q := range config.User {
+		if q != nil && len(email) > 0 {
+			m.quotas[email] = *q
+		}
+	}
+	m.task = &signal.PeriodicTask{
+		Interval: time.Second * 10,
+		Execute:  m.checkExpired,
+	}
+
+	v := core.FromContext(ctx)
+	if v != nil {
+		m.stats = v.Stats()
+		if err := v.RegisterFeature((*Manager)(nil), m); err != nil {
+			return nil, newError("unable to register QuotaManager").Base(err)
+		}
+	}
+	return m, nil
+}
+
+func usageCounterName(email string) string {
+	return stats.ReservedPrefix + "quota>>>" + email + ">>>used"
+}
+
+// usageCounter returns the counter of used bytes of the given user.
+// The counter is taken from the StatManager if possible. Otherwise used bytes are only kept in memory.
+func (m *Manager) usageCounter(email string) core.StatCounter {
+	if m.stats != nil {
+		if c, err := core.GetOrRegisterStatCounter(m.stats, usageCounterName(email)); err == nil {
+			return c
+		}
+	}
+	return new(stats.Counter)
+}
+
+// persistedUsage returns the counter of used bytes of the given user, if the user has used bytes from a previous run.
+func (m *Manager) persistedUsage(email string) core.StatCounter {
+	if m.stats == nil {
+		return nil
+	}
+	return m.stats.GetCounter(usageCounterName(email))
+}
+
+// Type implements common.HasType.
+func (*Manager) Type() interface{} {
+	return (*Manager)(nil)
+}
+
+// Start implements common.Runnable.
+func (m *Manager) Start() error {
+	return m.task.Start()
+}
+
+// Close implements common.Closable.
+func (m *Manager) Close() error {
+	return m.task.Close()
+}
+
+func (m *Manager) checkExpired() error {
+	now := time.Now()
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	for email, s := range m.users {
+		if s.check(now) != nil && s.interrupt() > 0 {
+			newError("closed connections of user ", email, ": quota expired").AtInfo().WriteToLog()
+		}
+	}
+	return nil
+}
+
+// resolveQuota returns the quota of the given user. Users without a quota of their own get the default quota of their level.
+// Must be called with access held.
+func (m *Manager) resolveQuota(user *protocol.User) (Quota, bool) {
+	if quota, found := m.quotas[user.Email]; found {
+		return quota, true
+	}
+	quota, found := m.levels[user.Level]
+	return quota, found
+}
+
+// Open registers a new connection of the given user. interrupt is called when the connection has to be closed because the user runs out of quota.
+// It returns nil if the user has no quota, or an error if the user has already run out of quota.
+func (m *Manager) Open(user *protocol.User, interrupt func()) (*Connection, error) {
+	if user == nil || len(user.Email) == 0 {
+		return nil, nil
+	}
+
+	m.access.Lock()
+	s, found := m.users[user.Email]
+	if !found {
+		quota, hasQuota := m.resolveQuota(user)
+		if !hasQuota {
+			m.access.Unlock()
+			return nil, nil
+		}
+		s = &userState{
+			used:        m.usageCounter(user.Email),
+			connections: make(map[*Connection]struct{}),
+		}
+		s.setQuota(quota)
+		m.users[user.Email] = s
+	}
+	m.access.Unlock()
+
+	if err := s.check(time.Now()); err != nil {
+		return nil, newError("user ", user.Email, " is refused").Base(err)
+	}
+
+	c := &Connection{
+		state:     s,
+		interrupt: interrupt,
+	}
+	s.access.Lock()
+	s.connections[c] = struct{}{}
+	s.access.Unlock()
+	return c, nil
+}
+
+// GetQuota returns the quota and used bytes of the given user. The level of the user is used if the user has no quota of its own.
+// Users that have neither a quota of their own nor any used bytes, in this run or a previous one, are not found.
+func (m *Manager) GetQuota(user *protocol.User) (Quota, uint64, bool) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if s, found := m.users[user.Email]; found {
+		return s.quota(), uint64(s.used.Value()), true
+	}
+
+	used := m.persistedUsage(user.Email)
+	if _, found := m.quotas[user.Email]; !found && used == nil {
+		return Quota{}, 0, false
+	}
+	quota, found := m.resolveQuota(user)
+	if !found {
+		return Quota{}, 0, false
+	}
+	if used == nil {
+		return quota, 0, true
+	}
+	return quota, uint64(used.Value()), true
+}
+
+// SetQuota overrides the quota of the given user. Used bytes of the user are kept.
+// If the user runs out of the new quota, existing connections of the user are closed.
+func (m *Manager) SetQuota(email string, quota Quota) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	m.quotas[email] = quota
+	s, found := m.users[email]
+	if !found {
+		return
+	}
+	s.setQuota(quota)
+	if s.check(time.Now()) != nil {
+		s.interrupt()
+	}
+}
+
+// ResetQuota clears the used bytes of the given user. It returns false if the user has neither a quota nor used bytes.
+func (m *Manager) ResetQuota(email string) bool {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if s, found := m.users[email]; found {
+		s.used.Set(0)
+		return true
+	}
+	if c := m.persistedUsage(email); c != nil {
+		c.Set(0)
+		return true
+	}
+	_, found := m.quotas[email]
+	return found
+}
+
+// Connection is an active connection of a user that is subject to a quota.
+type Connection struct {
+	state     *userState
+	interrupt func()
+}
+
+// Consume charges n bytes to the user of the connection.
+// If the user runs out of quota, all connections of the user are closed and an error is returned.
+// Bytes beyond the quota are charged as well, so that used bytes of the user reflect the overage.
+func (c *Connection) Consume(n uint64) error {
+	s := c.state
+	if err := s.check(time.Now()); err != nil {
+		s.interrupt()
+		return err
+	}
+	used := uint64(s.used.Add(int64(n)))
+	if bytes := atomic.LoadUint64(&s.bytes); bytes > 0 && used > bytes {
+		s.interrupt()
+		return errExceeded
+	}
+	return nil
+}
+
+// Close unregisters the connection.
+func (c *Connection) Close() error {
+	c.state.access.Lock()
+	delete(c.state.connections, c)
+	c.state.access.Unlock()
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,176 @@
+package quota_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	. "v2ray.com/core/app/quota"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/ext/assert"
+)
+
+func TestQuotaExceeded(t *testing.T) {
+	assert := With(t)
+
+	m, err := New(context.Background(), &Config{
+		User: map[string]*Quota{
+			"love@v2ray.com": {Bytes: 100},
+		},
+	})
+	assert(err, IsNil)
+
+	user := &protocol.User{Email: "love@v2ray.com"}
+	interrupted := 0
+	c1, err := m.Open(user, func() { interrupted++ })
+	assert(err, IsNil)
+	assert(c1, IsNotNil)
+	c2, err := m.Open(user, func() { interrupted++ })
+	assert(err, IsNil)
+
+	assert(c1.Consume(60), IsNil)
+	assert(c2.Consume(40), IsNil)
+	assert(interrupted, Equals, 0)
+
+	assert(c2.Consume(1), IsNotNil)
+	assert(interrupted, Equals, 2)
+
+	_, err = m.Open(user, func() {})
+	assert(err, IsNotNil)
+
+	c1.Close()
+	c2.Close()
+
+	assert(m.ResetQuota("love@v2ray.com"), IsTrue)
+	c3, err := m.Open(user, func() {})
+	assert(err, IsNil)
+	assert(c3.Consume(100), IsNil)
+	c3.Close()
+
+	q, used, found := m.GetQuota(user)
+	assert(found, IsTrue)
+	assert(q.Bytes, Equals, uint64(100))
+	assert(used, Equals, uint64(100))
+
+	// Bytes beyond the quota are still recorded.
+	assert(m.ResetQuota("love@v2ray.com"), IsTrue)
+	c4, err := m.Open(user, func() {})
+	assert(err, IsNil)
+	assert(c4.Consume(60), IsNil)
+	assert(c4.Consume(60), IsNotNil)
+	c4.Close()
+	_, used, _ = m.GetQuota(user)
+	assert(used, Equals, uint64(120))
+}
+
+func TestQuotaLevelAndExpire(t *testing.T) {
+	assert := With(t)
+
+	m, err := New(context.Background(), &Config{
+		Level: map[uint32]*Quota{
+			1: {Bytes: 100},
+		},
+	})
+	assert(err, IsNil)
+
+	c, err := m.Open(&protocol.User{Email: "nobody@v2ray.com"}, func() {})
+	assert(err, IsNil)
+	assert(c == nil, IsTrue)
+
+	user := &protocol.User{Email: "love@v2ray.com", Level: 1}
+	interrupted := false
+	c, err = m.Open(user, func() { interrupted = true })
+	assert(err, IsNil)
+	assert(c.Consume(50), IsNil)
+
+	m.SetQuota("love@v2ray.com", Quota{Expire: time.Now().Add(-time.Second).Unix()})
+	assert(interrupted, IsTrue)
+	assert(c.Consume(1), IsNotNil)
+	c.Close()
+
+	_, err = m.Open(user, func() {})
+	assert(err, IsNotNil)
+
+	other := &protocol.User{Email: "other@v2ray.com", Level: 1}
+	c, err = m.Open(other, func() {})
+	assert(err, IsNil)
+	assert(c.Consume(100), IsNil)
+	c.Close()
+}
+
+func TestQuotaPersistence(t *testing.T) {
+	assert := With(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-quota")
+	assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&Config{
+				Level: map[uint32]*Quota{
+					1: {Bytes: 100},
+				},
+			}),
+			serial.ToTypedMessage(&stats.Config{
+				PersistenceFile: filepath.Join(dir, "stats.json"),
+			}),
+		},
+	}
+	user := &protocol.User{Email: "love@v2ray.com", Level: 1}
+
+	v, err := core.New(config)
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	m := v.GetFeature((*Manager)(nil)).(*Manager)
+
+	// Users are unknown until their first connection.
+	_, _, found := m.GetQuota(user)
+	assert(found, IsFalse)
+
+	c, err := m.Open(user, func() {})
+	assert(err, IsNil)
+	assert(c.Consume(60), IsNil)
+	c.Close()
+	q, used, found := m.GetQuota(user)
+	assert(found, IsTrue)
+	assert(q.Bytes, Equals, uint64(100))
+	assert(used, Equals, uint64(60))
+
+	// Used bytes are not exposed to clients of StatsService.
+	v.Stats().Visit(func(name string, c core.StatCounter) bool {
+		assert(name, NotEquals, "user>>>love@v2ray.com>>>quota>>>used")
+		assert(stats.IsReserved(name), IsFalse)
+		return true
+	})
+	assert(v.Close(), IsNil)
+
+	v, err = core.New(config)
+	assert(err, IsNil)
+	assert(v.Start(), IsNil)
+	m = v.GetFeature((*Manager)(nil)).(*Manager)
+
+	_, used, found = m.GetQuota(user)
+	assert(found, IsTrue)
+	assert(used, Equals, uint64(60))
+
+	c, err = m.Open(user, func() {})
+	assert(err, IsNil)
+	assert(c.Consume(40), IsNil)
+	assert(c.Consume(1), IsNotNil)
+	c.Close()
+	assert(v.Close(), IsNil)
+}
@@ -0,0 +1,30 @@
+package quota
+
+import (
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/transport/pipe"
+)
+
+// Writer is a buf.Writer that charges all written bytes to the quota of a Connection.
+type Writer struct {
+	Connection *Connection
+	Writer     buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *Writer) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := w.Connection.Consume(uint64(mb.Len())); err != nil {
+		mb.Release()
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *Writer) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *Writer) CloseError() {
+	pipe.CloseError(w.Writer)
+}
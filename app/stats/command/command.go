@@ -4,17 +4,34 @@ package command
 
 import (
 	"context"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
 
 	grpc "google.golang.org/grpc"
 	"v2ray.com/core"
+	"v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 )
 
 type statsServer struct {
-	stats core.StatManager
+	stats     core.StatManager
+	startTime time.Time
+}
+
+// NewStatsServer creates a StatsServiceServer on top of the given StatManager.
+func NewStatsServer(manager core.StatManager) StatsServiceServer {
+	return &statsServer{
+		stats:     manager,
+		startTime: time.Now(),
+	}
 }
 
 func (s *statsServer) GetStats(ctx context.Context, request *GetStatsRequest) (*GetStatsResponse, error) {
+	if stats.IsReserved(request.Name) {
+		return nil, newError(request.Name, " not found.")
+	}
 	c := s.stats.GetCounter(request.Name)
 	if c == nil {
 		return nil, newError(request.Name, " not found.")
@@ -33,14 +50,64 @@ func (s *statsServer) GetStats(ctx context.Context, request *GetStatsRequest) (*
 	}, nil
 }
 
+func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest) (*QueryStatsResponse, error) {
+	match := func(name string) bool {
+		return strings.HasPrefix(name, request.Pattern)
+	}
+	if request.Regexp {
+		r, err := regexp.Compile(request.Pattern)
+		if err != nil {
+			return nil, newError("invalid pattern: ", request.Pattern).Base(err)
+		}
+		match = r.MatchString
+	}
+
+	response := &QueryStatsResponse{}
+	s.stats.Visit(func(name string, c core.StatCounter) bool {
+		if !match(name) {
+			return true
+		}
+		var value int64
+		if request.Reset_ {
+			value = c.Set(0)
+		} else {
+			value = c.Value()
+		}
+		response.Stat = append(response.Stat, &Stat{
+			Name:  name,
+			Value: value,
+		})
+		return true
+	})
+
+	return response, nil
+}
+
+func (s *statsServer) GetSysStats(ctx context.Context, request *SysStatsRequest) (*SysStatsResponse, error) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	return &SysStatsResponse{
+		NumGoroutine: uint32(runtime.NumGoroutine()),
+		NumGc:        rtm.NumGC,
+		Alloc:        rtm.Alloc,
+		TotalAlloc:   rtm.TotalAlloc,
+		Sys:          rtm.Sys,
+		Mallocs:      rtm.Mallocs,
+		Frees:        rtm.Frees,
+		LiveObjects:  rtm.Mallocs - rtm.Frees,
+		PauseTotalNs: rtm.PauseTotalNs,
+		LastPauseNs:  rtm.PauseNs[(rtm.NumGC+255)%256],
+		Uptime:       uint32(time.Since(s.startTime).Seconds()),
+	}, nil
+}
+
 type service struct {
 	v *core.Instance
 }
 
 func (s *service) Register(server *grpc.Server) {
-	RegisterStatsServiceServer(server, &statsServer{
-		stats: s.v.Stats(),
-	})
+	RegisterStatsServiceServer(server, NewStatsServer(s.v.Stats()))
 }
 
 func init() {
@@ -86,18 +86,184 @@ func (m *GetStatsResponse) GetStat() *Stat {
 	return nil
 }
 
+type QueryStatsRequest struct {
+	// Pattern of the stat counter names. Counters whose names start with the
+	// pattern are returned. All counters are returned if the pattern is empty.
+	Pattern string `protobuf:"bytes,1,opt,name=pattern" json:"pattern,omitempty"`
+	// Whether or not to reset the counters after fetching their values.
+	Reset_ bool `protobuf:"varint,2,opt,name=reset" json:"reset,omitempty"`
+	// Whether or not to treat the pattern as a regular expression.
+	Regexp bool `protobuf:"varint,3,opt,name=regexp" json:"regexp,omitempty"`
+}
+
+func (m *QueryStatsRequest) Reset()                    { *m = QueryStatsRequest{} }
+func (m *QueryStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryStatsRequest) ProtoMessage()               {}
+func (*QueryStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *QueryStatsRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *QueryStatsRequest) GetReset_() bool {
+	if m != nil {
+		return m.Reset_
+	}
+	return false
+}
+
+func (m *QueryStatsRequest) GetRegexp() bool {
+	if m != nil {
+		return m.Regexp
+	}
+	return false
+}
+
+type QueryStatsResponse struct {
+	Stat []*Stat `protobuf:"bytes,1,rep,name=stat" json:"stat,omitempty"`
+}
+
+func (m *QueryStatsResponse) Reset()                    { *m = QueryStatsResponse{} }
+func (m *QueryStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryStatsResponse) ProtoMessage()               {}
+func (*QueryStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *QueryStatsResponse) GetStat() []*Stat {
+	if m != nil {
+		return m.Stat
+	}
+	return nil
+}
+
+type SysStatsRequest struct {
+}
+
+func (m *SysStatsRequest) Reset()                    { *m = SysStatsRequest{} }
+func (m *SysStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SysStatsRequest) ProtoMessage()               {}
+func (*SysStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type SysStatsResponse struct {
+	NumGoroutine uint32 `protobuf:"varint,1,opt,name=num_goroutine,json=numGoroutine" json:"num_goroutine,omitempty"`
+	NumGc        uint32 `protobuf:"varint,2,opt,name=num_gc,json=numGc" json:"num_gc,omitempty"`
+	// Bytes of allocated heap objects.
+	Alloc uint64 `protobuf:"varint,3,opt,name=alloc" json:"alloc,omitempty"`
+	// Cumulative bytes allocated for heap objects.
+	TotalAlloc uint64 `protobuf:"varint,4,opt,name=total_alloc,json=totalAlloc" json:"total_alloc,omitempty"`
+	// Total bytes of memory obtained from the OS.
+	Sys         uint64 `protobuf:"varint,5,opt,name=sys" json:"sys,omitempty"`
+	Mallocs     uint64 `protobuf:"varint,6,opt,name=mallocs" json:"mallocs,omitempty"`
+	Frees       uint64 `protobuf:"varint,7,opt,name=frees" json:"frees,omitempty"`
+	LiveObjects uint64 `protobuf:"varint,8,opt,name=live_objects,json=liveObjects" json:"live_objects,omitempty"`
+	// Cumulative nanoseconds in GC stop-the-world pauses.
+	PauseTotalNs uint64 `protobuf:"varint,9,opt,name=pause_total_ns,json=pauseTotalNs" json:"pause_total_ns,omitempty"`
+	// Nanoseconds of the most recent GC stop-the-world pause.
+	LastPauseNs uint64 `protobuf:"varint,10,opt,name=last_pause_ns,json=lastPauseNs" json:"last_pause_ns,omitempty"`
+	// Seconds since the stats service was started.
+	Uptime uint32 `protobuf:"varint,11,opt,name=uptime" json:"uptime,omitempty"`
+}
+
+func (m *SysStatsResponse) Reset()                    { *m = SysStatsResponse{} }
+func (m *SysStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*SysStatsResponse) ProtoMessage()               {}
+func (*SysStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *SysStatsResponse) GetNumGoroutine() uint32 {
+	if m != nil {
+		return m.NumGoroutine
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetNumGc() uint32 {
+	if m != nil {
+		return m.NumGc
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetAlloc() uint64 {
+	if m != nil {
+		return m.Alloc
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetTotalAlloc() uint64 {
+	if m != nil {
+		return m.TotalAlloc
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetSys() uint64 {
+	if m != nil {
+		return m.Sys
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetMallocs() uint64 {
+	if m != nil {
+		return m.Mallocs
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetFrees() uint64 {
+	if m != nil {
+		return m.Frees
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetLiveObjects() uint64 {
+	if m != nil {
+		return m.LiveObjects
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetPauseTotalNs() uint64 {
+	if m != nil {
+		return m.PauseTotalNs
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetLastPauseNs() uint64 {
+	if m != nil {
+		return m.LastPauseNs
+	}
+	return 0
+}
+
+func (m *SysStatsResponse) GetUptime() uint32 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
 type Config struct {
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func init() {
 	proto.RegisterType((*GetStatsRequest)(nil), "v2ray.core.app.stats.command.GetStatsRequest")
 	proto.RegisterType((*Stat)(nil), "v2ray.core.app.stats.command.Stat")
 	proto.RegisterType((*GetStatsResponse)(nil), "v2ray.core.app.stats.command.GetStatsResponse")
+	proto.RegisterType((*QueryStatsRequest)(nil), "v2ray.core.app.stats.command.QueryStatsRequest")
+	proto.RegisterType((*QueryStatsResponse)(nil), "v2ray.core.app.stats.command.QueryStatsResponse")
+	proto.RegisterType((*SysStatsRequest)(nil), "v2ray.core.app.stats.command.SysStatsRequest")
+	proto.RegisterType((*SysStatsResponse)(nil), "v2ray.core.app.stats.command.SysStatsResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.stats.command.Config")
 }
 
@@ -113,6 +279,8 @@ const _ = grpc.SupportPackageIsVersion4
 
 type StatsServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	QueryStats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
+	GetSysStats(ctx context.Context, in *SysStatsRequest, opts ...grpc.CallOption) (*SysStatsResponse, error)
 }
 
 type statsServiceClient struct {
@@ -132,10 +300,30 @@ func (c *statsServiceClient) GetStats(ctx context.Context, in *GetStatsRequest,
 	return out, nil
 }
 
+func (c *statsServiceClient) QueryStats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error) {
+	out := new(QueryStatsResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.stats.command.StatsService/QueryStats", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *statsServiceClient) GetSysStats(ctx context.Context, in *SysStatsRequest, opts ...grpc.CallOption) (*SysStatsResponse, error) {
+	out := new(SysStatsResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.stats.command.StatsService/GetSysStats", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for StatsService service
 
 type StatsServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	QueryStats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
+	GetSysStats(context.Context, *SysStatsRequest) (*SysStatsResponse, error)
 }
 
 func RegisterStatsServiceServer(s *grpc.Server, srv StatsServiceServer) {
@@ -160,6 +348,42 @@ func _StatsService_GetStats_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatsService_QueryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).QueryStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.stats.command.StatsService/QueryStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).QueryStats(ctx, req.(*QueryStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StatsService_GetSysStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SysStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetSysStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.stats.command.StatsService/GetSysStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetSysStats(ctx, req.(*SysStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StatsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v2ray.core.app.stats.command.StatsService",
 	HandlerType: (*StatsServiceServer)(nil),
@@ -168,6 +392,14 @@ var _StatsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _StatsService_GetStats_Handler,
 		},
+		{
+			MethodName: "QueryStats",
+			Handler:    _StatsService_QueryStats_Handler,
+		},
+		{
+			MethodName: "GetSysStats",
+			Handler:    _StatsService_GetSysStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v2ray.com/core/app/stats/command/command.proto",
@@ -176,22 +408,39 @@ var _StatsService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("v2ray.com/core/app/stats/command/command.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0x3f, 0x4b, 0x03, 0x31,
-	0x14, 0xc0, 0xbd, 0x5a, 0xeb, 0xf9, 0x14, 0x94, 0xe0, 0x50, 0xa4, 0xc3, 0x91, 0xa9, 0x8b, 0xef,
-	0xe4, 0x04, 0x17, 0x27, 0xbd, 0x41, 0x10, 0x07, 0x49, 0xc1, 0xc1, 0x2d, 0xc6, 0xa7, 0x14, 0xcd,
-	0x25, 0x26, 0xe9, 0x41, 0xf1, 0x1b, 0xf9, 0x29, 0x25, 0xb9, 0x1e, 0x82, 0xe0, 0xe1, 0x94, 0xf7,
-	0x92, 0xdf, 0xef, 0xfd, 0x21, 0x80, 0x6d, 0xe5, 0xe4, 0x1a, 0x95, 0xd1, 0xa5, 0x32, 0x8e, 0x4a,
-	0x69, 0x6d, 0xe9, 0x83, 0x0c, 0xbe, 0x54, 0x46, 0x6b, 0xd9, 0x3c, 0xf7, 0x27, 0x5a, 0x67, 0x82,
-	0x61, 0xb3, 0x9e, 0x77, 0x84, 0xd2, 0x5a, 0x4c, 0x2c, 0x6e, 0x18, 0x7e, 0x09, 0x87, 0x37, 0x14,
-	0x16, 0xf1, 0x4e, 0xd0, 0xc7, 0x8a, 0x7c, 0x60, 0x0c, 0xc6, 0x8d, 0xd4, 0x34, 0xcd, 0x8a, 0x6c,
-	0xbe, 0x27, 0x52, 0xcc, 0x8e, 0x61, 0xc7, 0x91, 0xa7, 0x30, 0x1d, 0x15, 0xd9, 0x3c, 0x17, 0x5d,
-	0xc2, 0xcf, 0x60, 0x1c, 0xcd, 0xbf, 0x8c, 0x56, 0xbe, 0xaf, 0x28, 0x19, 0xdb, 0xa2, 0x4b, 0xf8,
-	0x2d, 0x1c, 0xfd, 0xb4, 0xf3, 0xd6, 0x34, 0x9e, 0xd8, 0x05, 0x8c, 0xe3, 0x4c, 0xc9, 0xde, 0xaf,
-	0x38, 0x0e, 0xcd, 0x8b, 0x51, 0x15, 0x89, 0xe7, 0x39, 0x4c, 0x6a, 0xd3, 0xbc, 0x2c, 0x5f, 0xab,
-	0x4f, 0x38, 0x48, 0x25, 0x17, 0xe4, 0xda, 0xa5, 0x22, 0xf6, 0x06, 0x79, 0xdf, 0x85, 0x9d, 0x0e,
-	0xd7, 0xfb, 0xb5, 0xfc, 0x09, 0xfe, 0x17, 0xef, 0x86, 0xe7, 0x5b, 0xd7, 0x77, 0x50, 0x28, 0xa3,
-	0x07, 0xb5, 0xfb, 0xec, 0x71, 0x77, 0x13, 0x7e, 0x8d, 0x66, 0x0f, 0x95, 0x90, 0x6b, 0xac, 0x23,
-	0x79, 0x65, 0x6d, 0xda, 0xc8, 0x63, 0xdd, 0x3d, 0x3f, 0x4d, 0xd2, 0xa7, 0x9d, 0x7f, 0x07, 0x00,
-	0x00, 0xff, 0xff, 0x10, 0x3a, 0x8a, 0xf3, 0xe6, 0x01, 0x00, 0x00,
+	// 536 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x6f, 0x13, 0x31,
+	0x10, 0x25, 0xdf, 0xe9, 0x24, 0xa1, 0xa9, 0x05, 0x68, 0x15, 0x55, 0x22, 0x2c, 0x1c, 0x7a, 0xc1,
+	0xa9, 0x82, 0xc4, 0x85, 0x53, 0xc9, 0xa1, 0x12, 0xaa, 0x4a, 0xd9, 0x20, 0x0e, 0x70, 0x88, 0xdc,
+	0x65, 0x1a, 0x05, 0x76, 0xd7, 0xae, 0xed, 0x8d, 0xd8, 0xbf, 0xc4, 0x6f, 0xe1, 0x37, 0xf0, 0x5b,
+	0x90, 0xc7, 0xbb, 0xa4, 0xb4, 0x10, 0x85, 0x53, 0xfc, 0xde, 0xbc, 0xe7, 0x79, 0x63, 0x7b, 0x03,
+	0x7c, 0x3d, 0xd5, 0xa2, 0xe0, 0xb1, 0x4c, 0x27, 0xb1, 0xd4, 0x38, 0x11, 0x4a, 0x4d, 0x8c, 0x15,
+	0xd6, 0x4c, 0x62, 0x99, 0xa6, 0x22, 0xfb, 0x5c, 0xfd, 0x72, 0xa5, 0xa5, 0x95, 0xec, 0xb0, 0xd2,
+	0x6b, 0xe4, 0x42, 0x29, 0x4e, 0x5a, 0x5e, 0x6a, 0xc2, 0x57, 0xb0, 0x7f, 0x8a, 0x76, 0xee, 0xb8,
+	0x08, 0xaf, 0x73, 0x34, 0x96, 0x31, 0x68, 0x66, 0x22, 0xc5, 0xa0, 0x36, 0xae, 0x1d, 0xed, 0x45,
+	0xb4, 0x66, 0x0f, 0xa0, 0xa5, 0xd1, 0xa0, 0x0d, 0xea, 0xe3, 0xda, 0x51, 0x37, 0xf2, 0x20, 0x3c,
+	0x86, 0xa6, 0x73, 0xfe, 0xcb, 0xb1, 0x16, 0x49, 0x8e, 0xe4, 0x68, 0x44, 0x1e, 0x84, 0x6f, 0x60,
+	0xb8, 0x69, 0x67, 0x94, 0xcc, 0x0c, 0xb2, 0x97, 0xd0, 0x74, 0x99, 0xc8, 0xdd, 0x9b, 0x86, 0x7c,
+	0x5b, 0x5e, 0xee, 0xac, 0x11, 0xe9, 0xc3, 0x4f, 0x70, 0xf0, 0x2e, 0x47, 0x5d, 0xfc, 0x11, 0x3e,
+	0x80, 0x8e, 0x12, 0xd6, 0xa2, 0xce, 0xca, 0x34, 0x15, 0xfc, 0xfb, 0x08, 0xec, 0x11, 0xb4, 0x35,
+	0x2e, 0xf1, 0x9b, 0x0a, 0x1a, 0x44, 0x97, 0x28, 0x3c, 0x03, 0x76, 0x73, 0xf3, 0x3b, 0x51, 0x1b,
+	0xff, 0x15, 0xf5, 0x00, 0xf6, 0xe7, 0x85, 0xb9, 0x19, 0x34, 0xfc, 0x51, 0x87, 0xe1, 0x86, 0x2b,
+	0xf7, 0x7f, 0x0a, 0x83, 0x2c, 0x4f, 0x17, 0x4b, 0xa9, 0x65, 0x6e, 0x57, 0x99, 0x3f, 0xd1, 0x41,
+	0xd4, 0xcf, 0xf2, 0xf4, 0xb4, 0xe2, 0xd8, 0x43, 0x68, 0x93, 0x28, 0xa6, 0x49, 0x06, 0x51, 0xcb,
+	0x55, 0x63, 0x37, 0x9f, 0x48, 0x12, 0x19, 0xd3, 0x20, 0xcd, 0xc8, 0x03, 0xf6, 0x18, 0x7a, 0x56,
+	0x5a, 0x91, 0x2c, 0x7c, 0xad, 0x49, 0x35, 0x20, 0xea, 0x84, 0x04, 0x43, 0x68, 0x98, 0xc2, 0x04,
+	0x2d, 0x2a, 0xb8, 0xa5, 0x3b, 0xc2, 0x94, 0xd4, 0x26, 0x68, 0x13, 0x5b, 0x41, 0xd7, 0xe2, 0x4a,
+	0x23, 0x9a, 0xa0, 0xe3, 0x5b, 0x10, 0x60, 0x4f, 0xa0, 0x9f, 0xac, 0xd6, 0xb8, 0x90, 0x97, 0x5f,
+	0x30, 0xb6, 0x26, 0xe8, 0x52, 0xb1, 0xe7, 0xb8, 0xb7, 0x9e, 0x62, 0xcf, 0xe0, 0xbe, 0x12, 0xb9,
+	0xc1, 0x85, 0xcf, 0x92, 0x99, 0x60, 0x8f, 0x44, 0x7d, 0x62, 0xdf, 0x3b, 0xf2, 0xdc, 0xb0, 0x10,
+	0x06, 0x89, 0x30, 0x76, 0xe1, 0xa5, 0x99, 0x09, 0xa0, 0xdc, 0x49, 0x18, 0x7b, 0xe1, 0xb8, 0x73,
+	0xe3, 0xee, 0x2b, 0x57, 0x76, 0x95, 0x62, 0xd0, 0xa3, 0xe1, 0x4b, 0x14, 0x76, 0xa1, 0x3d, 0x93,
+	0xd9, 0xd5, 0x6a, 0x39, 0xfd, 0x59, 0x87, 0x3e, 0x9d, 0xea, 0x1c, 0xf5, 0x7a, 0x15, 0x23, 0xfb,
+	0x0a, 0xdd, 0xea, 0xcd, 0xb1, 0xe7, 0xdb, 0xaf, 0xec, 0xd6, 0xa7, 0x30, 0xe2, 0xbb, 0xca, 0xfd,
+	0xfd, 0x85, 0xf7, 0xd8, 0x35, 0xc0, 0xe6, 0xdd, 0xb0, 0xc9, 0x76, 0xff, 0x9d, 0xe7, 0x3b, 0x3a,
+	0xde, 0xdd, 0xf0, 0xbb, 0x65, 0x06, 0x3d, 0x17, 0xa4, 0x30, 0x3b, 0x8d, 0x78, 0xeb, 0x1d, 0x8e,
+	0xf8, 0xae, 0xf2, 0xaa, 0xdf, 0xeb, 0x33, 0x18, 0xc7, 0x32, 0xdd, 0x6a, 0xbb, 0xa8, 0x7d, 0xec,
+	0x94, 0xcb, 0xef, 0xf5, 0xc3, 0x0f, 0xd3, 0x48, 0x14, 0x7c, 0xe6, 0x94, 0x27, 0x4a, 0xd1, 0x77,
+	0x61, 0xf8, 0xcc, 0x97, 0x2f, 0xdb, 0xf4, 0x2f, 0xf5, 0xe2, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff,
+	0xf8, 0x05, 0x78, 0x40, 0xd7, 0x04, 0x00, 0x00,
 }
@@ -0,0 +1,87 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app/stats"
+	. "v2ray.com/core/app/stats/command"
+	"v2ray.com/core/common"
+	. "v2ray.com/ext/assert"
+)
+
+func TestQueryStats(t *testing.T) {
+	assert := With(t)
+
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	assert(err, IsNil)
+
+	for _, name := range []string{
+		"user>>>a@v2ray.com>>>traffic>>>uplink",
+		"user>>>a@v2ray.com>>>traffic>>>downlink",
+		"user>>>b@v2ray.com>>>traffic>>>uplink",
+		"inbound>>>api>>>traffic>>>uplink",
+	} {
+		c, err := manager.RegisterCounter(name)
+		assert(err, IsNil)
+		c.Add(10)
+	}
+
+	server := NewStatsServer(manager)
+
+	resp, err := server.QueryStats(context.Background(), &QueryStatsRequest{
+		Pattern: "user>>>a@v2ray.com>>>",
+	})
+	assert(err, IsNil)
+	assert(len(resp.Stat), Equals, 2)
+
+	resp, err = server.QueryStats(context.Background(), &QueryStatsRequest{
+		Pattern: "^user>>>.*>>>uplink$",
+		Regexp:  true,
+		Reset_:  true,
+	})
+	assert(err, IsNil)
+	assert(len(resp.Stat), Equals, 2)
+	for _, stat := range resp.Stat {
+		assert(stat.Value, Equals, int64(10))
+	}
+	assert(manager.GetCounter("user>>>b@v2ray.com>>>traffic>>>uplink").Value(), Equals, int64(0))
+	assert(manager.GetCounter("inbound>>>api>>>traffic>>>uplink").Value(), Equals, int64(10))
+
+	resp, err = server.QueryStats(context.Background(), &QueryStatsRequest{})
+	assert(err, IsNil)
+	assert(len(resp.Stat), Equals, 4)
+
+	reserved, err := manager.RegisterCounter(stats.ReservedPrefix + "quota>>>a@v2ray.com>>>used")
+	assert(err, IsNil)
+	reserved.Add(10)
+	resp, err = server.QueryStats(context.Background(), &QueryStatsRequest{
+		Reset_: true,
+	})
+	assert(err, IsNil)
+	assert(len(resp.Stat), Equals, 4)
+	_, err = server.GetStats(context.Background(), &GetStatsRequest{
+		Name:   stats.ReservedPrefix + "quota>>>a@v2ray.com>>>used",
+		Reset_: true,
+	})
+	assert(err, IsNotNil)
+	assert(reserved.Value(), Equals, int64(10))
+
+	_, err = server.QueryStats(context.Background(), &QueryStatsRequest{
+		Pattern: "(",
+		Regexp:  true,
+	})
+	assert(err, IsNotNil)
+}
+
+func TestGetSysStats(t *testing.T) {
+	assert := With(t)
+
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	resp, err := NewStatsServer(manager).GetSysStats(context.Background(), &SysStatsRequest{})
+	assert(err, IsNil)
+	assert(resp.NumGoroutine, GreaterThan, uint32(0))
+	assert(resp.Sys, GreaterThan, uint64(0))
+}
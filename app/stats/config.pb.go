@@ -16,6 +16,10 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Config struct {
+	// Path of the file that counters are persisted to. Counters are kept in memory only if empty.
+	PersistenceFile string `protobuf:"bytes,1,opt,name=persistence_file,json=persistenceFile" json:"persistence_file,omitempty"`
+	// Interval in seconds between two snapshots of the counters. Default value is 60 if unset.
+	PersistenceInterval uint32 `protobuf:"varint,2,opt,name=persistence_interval,json=persistenceInterval" json:"persistence_interval,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -23,6 +27,20 @@ func (m *Config) String() string            { return proto.CompactTextString(m)
 func (*Config) ProtoMessage()               {}
 func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+func (m *Config) GetPersistenceFile() string {
+	if m != nil {
+		return m.PersistenceFile
+	}
+	return ""
+}
+
+func (m *Config) GetPersistenceInterval() uint32 {
+	if m != nil {
+		return m.PersistenceInterval
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.stats.Config")
 }
@@ -30,13 +48,17 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/app/stats/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 123 bytes of a gzipped FileDescriptorProto
+	// 184 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2d, 0x33, 0x2a, 0x4a,
 	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0x2c, 0x28, 0xd0, 0x2f,
 	0x2e, 0x49, 0x2c, 0x29, 0xd6, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0x12, 0x81, 0x29, 0x2b, 0x4a, 0xd5, 0x4b, 0x2c, 0x28, 0xd0, 0x03, 0x2b, 0x51, 0xe2,
-	0xe0, 0x62, 0x73, 0x06, 0xab, 0x72, 0xb2, 0xe2, 0x92, 0x48, 0xce, 0xcf, 0xd5, 0xc3, 0xa6, 0x2a,
-	0x80, 0x31, 0x8a, 0x15, 0xcc, 0x58, 0xc5, 0x24, 0x12, 0x66, 0x14, 0x94, 0x58, 0xa9, 0xe7, 0x0c,
-	0x92, 0x77, 0x2c, 0x28, 0xd0, 0x0b, 0x06, 0x09, 0x27, 0xb1, 0x81, 0xad, 0x30, 0x06, 0x04, 0x00,
-	0x00, 0xff, 0xff, 0x88, 0x24, 0xc6, 0x41, 0x8b, 0x00, 0x00, 0x00,
+	0xc9, 0x17, 0x12, 0x81, 0x29, 0x2b, 0x4a, 0xd5, 0x4b, 0x2c, 0x28, 0xd0, 0x03, 0x2b, 0x51, 0x4a,
+	0xe3, 0x62, 0x73, 0x06, 0xab, 0x12, 0xd2, 0xe4, 0x12, 0x28, 0x48, 0x2d, 0x2a, 0xce, 0x2c, 0x2e,
+	0x49, 0xcd, 0x4b, 0x4e, 0x8d, 0x4f, 0xcb, 0xcc, 0x49, 0x95, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c,
+	0xe2, 0x47, 0x12, 0x77, 0xcb, 0xcc, 0x49, 0x15, 0x32, 0xe4, 0x12, 0x41, 0x56, 0x9a, 0x99, 0x57,
+	0x92, 0x5a, 0x54, 0x96, 0x98, 0x23, 0xc1, 0xa4, 0xc0, 0xa8, 0xc1, 0x1b, 0x24, 0x8c, 0x24, 0xe7,
+	0x09, 0x95, 0x72, 0xb2, 0xe2, 0x92, 0x48, 0xce, 0xcf, 0xd5, 0xc3, 0xe6, 0x86, 0x00, 0xc6, 0x28,
+	0x56, 0x30, 0x63, 0x15, 0x93, 0x48, 0x98, 0x51, 0x50, 0x62, 0xa5, 0x9e, 0x33, 0x48, 0xde, 0xb1,
+	0xa0, 0x40, 0x2f, 0x18, 0x24, 0x9c, 0xc4, 0x06, 0xf6, 0x80, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff,
+	0x14, 0x52, 0x5b, 0xf3, 0xe9, 0x00, 0x00, 0x00,
 }
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"v2ray.com/core/common/signal"
+)
+
+func (c *Config) getPersistenceInterval() time.Duration {
+	if c.PersistenceInterval == 0 {
+		return time.Minute
+	}
+	return time.Duration(c.PersistenceInterval) * time.Second
+}
+
+// persistence periodically writes counters of a Manager into a local file.
+type persistence struct {
+	file    string
+	manager *Manager
+	task    *signal.PeriodicTask
+}
+
+func newPersistence(file string, interval time.Duration, manager *Manager) *persistence {
+	p := &persistence{
+		file:    file,
+		manager: manager,
+	}
+	p.task = &signal.PeriodicTask{
+		Interval: interval,
+		Execute:  p.save,
+		OnError: func(err error) {
+			newError("failed to persist counters").Base(err).AtWarning().WriteToLog()
+		},
+	}
+	return p
+}
+
+// load restores counters from the persistence file. A missing file is not an error.
+func (p *persistence) load() error {
+	content, err := ioutil.ReadFile(p.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return newError("failed to read ", p.file).Base(err)
+	}
+
+	values := make(map[string]int64)
+	if err := json.Unmarshal(content, &values); err != nil {
+		return newError("failed to parse ", p.file).Base(err)
+	}
+
+	for name, value := range values {
+		p.manager.getOrRegisterCounter(name).Add(value)
+	}
+	newError("restored ", len(values), " counters from ", p.file).AtInfo().WriteToLog()
+	return nil
+}
+
+// save writes all counters into the persistence file. The file is replaced atomically.
+func (p *persistence) save() error {
+	values := make(map[string]int64)
+	p.manager.visitCounters(func(name string, c *Counter) bool {
+		values[name] = c.Value()
+		return true
+	})
+
+	content, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p.file), filepath.Base(p.file)+".tmp")
+	if err != nil {
+		return newError("failed to create temp file").Base(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return newError("failed to write ", tmp.Name()).Base(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return newError("failed to sync ", tmp.Name()).Base(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), p.file); err != nil {
+		return newError("failed to replace ", p.file).Base(err)
+	}
+	return nil
+}
+
+func (p *persistence) Start() error {
+	if err := p.load(); err != nil {
+		return err
+	}
+	return p.task.Start()
+}
+
+func (p *persistence) Close() error {
+	p.task.Close()
+	return p.save()
+}
@@ -4,12 +4,22 @@ package stats
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"v2ray.com/core"
 )
 
+// ReservedPrefix is the prefix of counters that V2Ray keeps for its own bookkeeping, such as quota usage of users.
+// Reserved counters are persisted like other counters, but they are not visited, and StatsService neither reports nor resets them.
+const ReservedPrefix = "internal>>>"
+
+// IsReserved returns true if the counter of the given name is a reserved counter.
+func IsReserved(name string) bool {
+	return strings.HasPrefix(name, ReservedPrefix)
+}
+
 // Counter is an implementation of core.StatCounter.
 type Counter struct {
 	value int64
@@ -32,8 +42,9 @@ func (c *Counter) Add(delta int64) int64 {
 
 // Manager is an implementation of core.StatManager.
 type Manager struct {
-	access   sync.RWMutex
-	counters map[string]*Counter
+	access      sync.RWMutex
+	counters    map[string]*Counter
+	persistence *persistence
 }
 
 func NewManager(ctx context.Context, config *Config) (*Manager, error) {
@@ -41,6 +52,10 @@ func NewManager(ctx context.Context, config *Config) (*Manager, error) {
 		counters: make(map[string]*Counter),
 	}
 
+	if len(config.PersistenceFile) > 0 {
+		m.persistence = newPersistence(config.PersistenceFile, config.getPersistenceInterval(), m)
+	}
+
 	v := core.FromContext(ctx)
 	if v != nil {
 		if err := v.RegisterFeature((*core.StatManager)(nil), m); err != nil {
@@ -64,6 +79,18 @@ func (m *Manager) RegisterCounter(name string) (core.StatCounter, error) {
 	return c, nil
 }
 
+func (m *Manager) getOrRegisterCounter(name string) *Counter {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	c, found := m.counters[name]
+	if !found {
+		c = new(Counter)
+		m.counters[name] = c
+	}
+	return c
+}
+
 func (m *Manager) GetCounter(name string) core.StatCounter {
 	m.access.RLock()
 	defer m.access.RUnlock()
@@ -74,10 +101,39 @@ func (m *Manager) GetCounter(name string) core.StatCounter {
 	return nil
 }
 
+func (m *Manager) visitCounters(visitor func(string, *Counter) bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	for name, c := range m.counters {
+		if !visitor(name, c) {
+			break
+		}
+	}
+}
+
+// Visit implements core.StatManager. Reserved counters are skipped.
+func (m *Manager) Visit(visitor func(string, core.StatCounter) bool) {
+	m.visitCounters(func(name string, c *Counter) bool {
+		if IsReserved(name) {
+			return true
+		}
+		return visitor(name, c)
+	})
+}
+
+// Start implements common.Runnable. Persisted counters, if any, are restored here.
 func (m *Manager) Start() error {
+	if m.persistence != nil {
+		return m.persistence.Start()
+	}
 	return nil
 }
 
+// Close implements common.Closable. Counters are flushed to the persistence file, if any.
 func (m *Manager) Close() error {
+	if m.persistence != nil {
+		return m.persistence.Close()
+	}
 	return nil
 }
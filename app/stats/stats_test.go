@@ -2,6 +2,9 @@ package stats_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"v2ray.com/core"
@@ -30,3 +33,43 @@ func TestStatsCounter(t *testing.T) {
 	assert(c.Set(0), Equals, int64(1))
 	assert(c.Value(), Equals, int64(0))
 }
+
+func TestStatsPersistence(t *testing.T) {
+	assert := With(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		PersistenceFile: filepath.Join(dir, "stats.json"),
+	}
+
+	m, err := NewManager(context.Background(), config)
+	assert(err, IsNil)
+	assert(m.Start(), IsNil)
+
+	c, err := m.RegisterCounter("test.counter")
+	assert(err, IsNil)
+	c.Add(100)
+	assert(m.Close(), IsNil)
+
+	m, err = NewManager(context.Background(), config)
+	assert(err, IsNil)
+	assert(m.Start(), IsNil)
+
+	c = m.GetCounter("test.counter")
+	assert(c, IsNotNil)
+	assert(c.Value(), Equals, int64(100))
+
+	c, err = core.GetOrRegisterStatCounter(m, "test.counter")
+	assert(err, IsNil)
+	c.Add(1)
+	assert(m.Close(), IsNil)
+
+	m, err = NewManager(context.Background(), config)
+	assert(err, IsNil)
+	assert(m.Start(), IsNil)
+	assert(m.GetCounter("test.counter").Value(), Equals, int64(101))
+	assert(m.Close(), IsNil)
+}
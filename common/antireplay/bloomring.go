@@ -0,0 +1,116 @@
+// Package antireplay provides filters for detecting replayed messages.
+package antireplay
+
+import (
+	"crypto/rand"
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"v2ray.com/core/common"
+)
+
+// bloomFilter is a plain Bloom filter with k hash functions derived from one 64-bit hash by double hashing.
+type bloomFilter struct {
+	bits  []uint64
+	m     uint64
+	k     uint64
+	count int
+}
+
+func newBloomFilter(m uint64, k uint64) *bloomFilter {
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (f *bloomFilter) test(h1, h2 uint64) bool {
+	for i := uint64(0); i < f.k; i++ {
+		idx := (h1 + i*h2) % f.m
+		if f.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *bloomFilter) add(h1, h2 uint64) {
+	for i := uint64(0); i < f.k; i++ {
+		idx := (h1 + i*h2) % f.m
+		f.bits[idx/64] |= 1 << (idx % 64)
+	}
+	f.count++
+}
+
+func (f *bloomFilter) reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+	f.count = 0
+}
+
+// BloomRing is a replay filter made of two Bloom filters. New entries go to the current filter. When it has
+// received its capacity, the older filter is cleared and becomes the current one. So each entry is remembered
+// until at least capacity newer entries are added. A BloomRing is safe for concurrent use.
+type BloomRing struct {
+	access   sync.Mutex
+	seed     [16]byte
+	capacity int
+	current  *bloomFilter
+	previous *bloomFilter
+}
+
+// NewBloomRing creates a BloomRing in which each filter holds capacity entries with the given false positive rate.
+func NewBloomRing(capacity int, falsePositiveRate float64) *BloomRing {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 1e-6
+	}
+
+	// Optimal size and number of hash functions of a Bloom filter.
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Ceil(math.Ln2 * float64(m) / float64(capacity)))
+	if k == 0 {
+		k = 1
+	}
+
+	r := &BloomRing{
+		capacity: capacity,
+		current:  newBloomFilter(m, k),
+		previous: newBloomFilter(m, k),
+	}
+	common.Must2(rand.Read(r.seed[:]))
+	return r
+}
+
+func (r *BloomRing) hash(b []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	common.Must2(h.Write(r.seed[:]))
+	common.Must2(h.Write(b))
+	sum := h.Sum64()
+	h1 := sum & 0xffffffff
+	h2 := sum>>32 | 1
+	return h1, h2
+}
+
+// Check returns true if b has not been seen before, and records it. It returns false for a replay.
+func (r *BloomRing) Check(b []byte) bool {
+	h1, h2 := r.hash(b)
+
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	if r.current.test(h1, h2) || r.previous.test(h1, h2) {
+		return false
+	}
+	if r.current.count >= r.capacity {
+		r.previous.reset()
+		r.current, r.previous = r.previous, r.current
+	}
+	r.current.add(h1, h2)
+	return true
+}
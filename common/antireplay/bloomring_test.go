@@ -0,0 +1,55 @@
+package antireplay_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core/common/antireplay"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/ext/assert"
+)
+
+func TestBloomRingReplay(t *testing.T) {
+	assert := With(t)
+
+	ring := NewBloomRing(100, 1e-6)
+	assert(ring.Check([]byte("salt")), IsTrue)
+	assert(ring.Check([]byte("salt")), IsFalse)
+	assert(ring.Check([]byte("another salt")), IsTrue)
+}
+
+func TestBloomRingRotation(t *testing.T) {
+	assert := With(t)
+
+	ring := NewBloomRing(10, 1e-6)
+	assert(ring.Check([]byte("first")), IsTrue)
+	for i := 0; i < 9; i++ {
+		assert(ring.Check(serial.IntToBytes(i, nil)), IsTrue)
+	}
+
+	// The first generation is kept until the second one is full.
+	for i := 10; i < 20; i++ {
+		assert(ring.Check(serial.IntToBytes(i, nil)), IsTrue)
+	}
+	assert(ring.Check([]byte("first")), IsFalse)
+
+	assert(ring.Check(serial.IntToBytes(20, nil)), IsTrue)
+	assert(ring.Check([]byte("first")), IsTrue)
+	assert(ring.Check(serial.IntToBytes(15, nil)), IsFalse)
+}
+
+func TestBloomRingFalsePositive(t *testing.T) {
+	assert := With(t)
+
+	ring := NewBloomRing(10000, 1e-3)
+	for i := 0; i < 10000; i++ {
+		ring.Check(serial.IntToBytes(i, nil))
+	}
+
+	falsePositives := 0
+	for i := 10000; i < 20000; i++ {
+		if !ring.Check(serial.IntToBytes(i, nil)) {
+			falsePositives++
+		}
+	}
+	assert(falsePositives < 100, IsTrue)
+}
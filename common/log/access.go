@@ -11,6 +11,7 @@ type AccessStatus string
 const (
 	AccessAccepted = AccessStatus("accepted")
 	AccessRejected = AccessStatus("rejected")
+	AccessLimited  = AccessStatus("limited")
 )
 
 type AccessMessage struct {
@@ -0,0 +1,148 @@
+package proxyproto
+
+import (
+	"bufio"
+	"sync"
+	"syscall"
+	"time"
+
+	"v2ray.com/core/common/net"
+)
+
+// headerTimeout is the max time to wait for the PROXY protocol header after a connection is accepted.
+const headerTimeout = time.Second * 10
+
+// Conn is a net.Conn that reads a PROXY protocol header at the beginning of the stream,
+// and reports the addresses in the header as its own.
+// The header is read on first call to Read(), LocalAddr() or RemoteAddr().
+type Conn struct {
+	net.Conn
+	reader   *bufio.Reader
+	required bool
+
+	once   sync.Once
+	header *Header
+	err    error
+}
+
+// NewConn creates a new Conn. If required is true, the connection fails when it doesn't start with a PROXY protocol header.
+func NewConn(conn net.Conn, required bool) *Conn {
+	return &Conn{
+		Conn:     conn,
+		reader:   bufio.NewReader(conn),
+		required: required,
+	}
+}
+
+func (c *Conn) readHeader() {
+	c.Conn.SetReadDeadline(time.Now().Add(headerTimeout))
+	defer c.Conn.SetReadDeadline(time.Time{})
+
+	header, err := ReadHeader(c.reader)
+	if err != nil {
+		c.err = newError("failed to read PROXY protocol header from ", c.Conn.RemoteAddr()).Base(err)
+		return
+	}
+	if header == nil && c.required {
+		c.err = newError("PROXY protocol header is required, but not received from ", c.Conn.RemoteAddr())
+		return
+	}
+	c.header = header
+}
+
+// Header returns the PROXY protocol header of this connection, or nil if there is none.
+func (c *Conn) Header() (*Header, error) {
+	c.once.Do(c.readHeader)
+	return c.header, c.err
+}
+
+// Read implements net.Conn.Read().
+func (c *Conn) Read(b []byte) (int, error) {
+	if _, err := c.Header(); err != nil {
+		return 0, err
+	}
+	return c.reader.Read(b)
+}
+
+func toAddr(dest net.Destination) net.Addr {
+	if dest.Network == net.Network_UDP {
+		return &net.UDPAddr{IP: dest.Address.IP(), Port: int(dest.Port)}
+	}
+	return &net.TCPAddr{IP: dest.Address.IP(), Port: int(dest.Port)}
+}
+
+// RemoteAddr implements net.Conn.RemoteAddr(). It returns the source address in the PROXY protocol header, if any.
+func (c *Conn) RemoteAddr() net.Addr {
+	if header, _ := c.Header(); header != nil && header.Source.IsValid() {
+		return toAddr(header.Source)
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr implements net.Conn.LocalAddr(). It returns the destination address in the PROXY protocol header, if any.
+func (c *Conn) LocalAddr() net.Addr {
+	if header, _ := c.Header(); header != nil && header.Destination.IsValid() {
+		return toAddr(header.Destination)
+	}
+	return c.Conn.LocalAddr()
+}
+
+// SyscallConn implements syscall.Conn.
+func (c *Conn) SyscallConn() (syscall.RawConn, error) {
+	if sc, ok := c.Conn.(syscall.Conn); ok {
+		return sc.SyscallConn()
+	}
+	return nil, newError("underlying connection doesn't support syscall.Conn")
+}
+
+// Listener is a net.Listener whose accepted connections are wrapped with PROXY protocol support.
+type Listener struct {
+	net.Listener
+	required bool
+	trusted  []*net.IPNet
+}
+
+// NewListener creates a new Listener. See NewConn() for the meaning of required.
+// PROXY protocol headers are only read from peers in trusted, or from loopback peers if trusted is empty.
+// Connections from other peers are accepted as is, or closed if required is true.
+func NewListener(listener net.Listener, required bool, trusted []*net.IPNet) *Listener {
+	return &Listener{
+		Listener: listener,
+		required: required,
+		trusted:  trusted,
+	}
+}
+
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	if len(l.trusted) == 0 {
+		return tcpAddr.IP.IsLoopback()
+	}
+	for _, n := range l.trusted {
+		if n.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// Accept implements net.Listener.Accept().
+func (l *Listener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.isTrusted(conn.RemoteAddr()) {
+			return NewConn(conn, l.required), nil
+		}
+		if !l.required {
+			return conn, nil
+		}
+		newError("rejecting connection from untrusted PROXY protocol peer ", conn.RemoteAddr()).AtWarning().WriteToLog()
+		conn.Close()
+	}
+}
@@ -0,0 +1,101 @@
+package proxyproto_test
+
+import (
+	"net"
+	"testing"
+
+	. "v2ray.com/core/common/protocol/proxyproto"
+	. "v2ray.com/ext/assert"
+)
+
+func TestConnRemoteAddr(t *testing.T) {
+	assert := With(t)
+
+	for _, testCase := range []struct {
+		payload  string
+		required bool
+		source   string
+		err      bool
+	}{
+		{payload: "PROXY TCP4 10.0.0.1 10.0.0.2 1234 80\r\nhello", source: "10.0.0.1:1234"},
+		{payload: "PROXY TCP4 10.0.0.1 10.0.0.2 1234 80\r\nhello", required: true, source: "10.0.0.1:1234"},
+		{payload: "hello", source: "pipe"},
+		{payload: "hello", required: true, err: true},
+	} {
+		client, server := net.Pipe()
+		go func() {
+			client.Write([]byte(testCase.payload))
+			client.Close()
+		}()
+
+		conn := NewConn(server, testCase.required)
+		b := make([]byte, 16)
+		n, err := conn.Read(b)
+		if testCase.err {
+			assert(err, IsNotNil)
+			continue
+		}
+		assert(err, IsNil)
+		assert(string(b[:n]), Equals, "hello")
+		assert(conn.RemoteAddr().String(), Equals, testCase.source)
+		conn.Close()
+	}
+}
+
+func TestListenerTrustedPeers(t *testing.T) {
+	assert := With(t)
+
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	assert(err, IsNil)
+	_, other, err := net.ParseCIDR("10.0.0.0/8")
+	assert(err, IsNil)
+
+	for _, testCase := range []struct {
+		required bool
+		trusted  []*net.IPNet
+		source   string
+		err      bool
+	}{
+		{source: "10.0.0.1:1234"},
+		{trusted: []*net.IPNet{loopback}, source: "10.0.0.1:1234"},
+		{trusted: []*net.IPNet{other}, source: "127.0.0.1"},
+		{required: true, trusted: []*net.IPNet{other}, err: true},
+	} {
+		rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert(err, IsNil)
+		listener := NewListener(rawListener, testCase.required, testCase.trusted)
+
+		go func() {
+			client, err := net.Dial("tcp", rawListener.Addr().String())
+			if err != nil {
+				return
+			}
+			client.Write([]byte("PROXY TCP4 10.0.0.1 10.0.0.2 1234 80\r\nhello"))
+			b := make([]byte, 1)
+			client.Read(b)
+			client.Close()
+			listener.Close()
+		}()
+
+		conn, err := listener.Accept()
+		if testCase.err {
+			// The untrusted connection is closed, and Accept() returns once the listener is closed.
+			assert(err, IsNotNil)
+			continue
+		}
+		assert(err, IsNil)
+
+		b := make([]byte, 64)
+		n, err := conn.Read(b)
+		assert(err, IsNil)
+		host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+		if testCase.source == "127.0.0.1" {
+			assert(host, Equals, testCase.source)
+			assert(string(b[:n]), HasPrefix, "PROXY TCP4")
+		} else {
+			assert(conn.RemoteAddr().String(), Equals, testCase.source)
+			assert(string(b[:n]), Equals, "hello")
+		}
+		conn.Close()
+	}
+}
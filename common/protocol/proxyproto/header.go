@@ -0,0 +1,272 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+)
+
+const (
+	// maxV1Length is the max length of a v1 header, including the trailing CRLF.
+	maxV1Length = 107
+
+	v2CommandLocal = 0x0
+	v2CommandProxy = 0x1
+
+	v2FamilyTCP4 = 0x11
+	v2FamilyUDP4 = 0x12
+	v2FamilyTCP6 = 0x21
+	v2FamilyUDP6 = 0x22
+)
+
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// Header is a PROXY protocol header.
+type Header struct {
+	// Version is either 1 or 2.
+	Version byte
+	// Source is the original source of the connection. It is invalid if the addresses are unknown.
+	Source net.Destination
+	// Destination is the original destination of the connection. It is invalid if the addresses are unknown.
+	Destination net.Destination
+}
+
+// hasPrefix returns true if the reader starts with the given prefix. It peeks the reader byte by byte,
+// so that it doesn't wait for more data when the prefix already mismatches.
+func hasPrefix(reader *bufio.Reader, prefix []byte) (bool, error) {
+	for i := range prefix {
+		b, err := reader.Peek(i + 1)
+		if err != nil {
+			return false, err
+		}
+		if b[i] != prefix[i] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ReadHeader reads a PROXY protocol header from the reader. It returns nil without error if the reader doesn't
+// start with a PROXY protocol header, in which case nothing is consumed from the reader.
+func ReadHeader(reader *bufio.Reader) (*Header, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch first[0] {
+	case v1Prefix[0]:
+		ok, err := hasPrefix(reader, v1Prefix)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return readV1(reader)
+	case v2Signature[0]:
+		ok, err := hasPrefix(reader, v2Signature)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return readV2(reader)
+	default:
+		return nil, nil
+	}
+}
+
+func readV1(reader *bufio.Reader) (*Header, error) {
+	line := make([]byte, 0, maxV1Length)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, newError("failed to read v1 header").Base(err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= maxV1Length {
+			return nil, newError("v1 header too long")
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, newError("invalid v1 header: missing CRLF")
+	}
+
+	fields := strings.Split(string(line[len(v1Prefix):len(line)-2]), " ")
+	header := &Header{Version: 1}
+	switch fields[0] {
+	case "UNKNOWN":
+		return header, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, newError("unknown v1 protocol: ", fields[0])
+	}
+	if len(fields) != 5 {
+		return nil, newError("invalid v1 header: ", string(line))
+	}
+
+	src, err := parseV1Destination(fields[1], fields[3])
+	if err != nil {
+		return nil, err
+	}
+	dst, err := parseV1Destination(fields[2], fields[4])
+	if err != nil {
+		return nil, err
+	}
+	header.Source = src
+	header.Destination = dst
+	return header, nil
+}
+
+func parseV1Destination(address string, port string) (net.Destination, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return net.Destination{}, newError("invalid v1 address: ", address)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return net.Destination{}, newError("invalid v1 port: ", port).Base(err)
+	}
+	return net.TCPDestination(net.IPAddress(ip), net.Port(p)), nil
+}
+
+func readV2(reader *bufio.Reader) (*Header, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(reader, fixed[:]); err != nil {
+		return nil, newError("failed to read v2 header").Base(err)
+	}
+	if fixed[12]>>4 != 0x2 {
+		return nil, newError("unknown v2 version: ", fixed[12]>>4)
+	}
+	command := fixed[12] & 0x0f
+	family := fixed[13]
+	length := serial.BytesToUint16(fixed[14:16])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, newError("failed to read v2 addresses").Base(err)
+	}
+
+	header := &Header{Version: 2}
+	switch command {
+	case v2CommandLocal:
+		return header, nil
+	case v2CommandProxy:
+	default:
+		return nil, newError("unknown v2 command: ", command)
+	}
+
+	var ipLen int
+	var network net.Network
+	switch family {
+	case v2FamilyTCP4:
+		ipLen, network = net.IPv4len, net.Network_TCP
+	case v2FamilyUDP4:
+		ipLen, network = net.IPv4len, net.Network_UDP
+	case v2FamilyTCP6:
+		ipLen, network = net.IPv6len, net.Network_TCP
+	case v2FamilyUDP6:
+		ipLen, network = net.IPv6len, net.Network_UDP
+	default:
+		// Unix sockets and unspecified families. Addresses are ignored as the spec suggests.
+		return header, nil
+	}
+	if len(payload) < ipLen*2+4 {
+		return nil, newError("v2 addresses too short: ", len(payload))
+	}
+
+	src := net.IPAddress(payload[:ipLen])
+	dst := net.IPAddress(payload[ipLen : ipLen*2])
+	ports := payload[ipLen*2:]
+	header.Source = net.Destination{Network: network, Address: src, Port: net.PortFromBytes(ports[0:2])}
+	header.Destination = net.Destination{Network: network, Address: dst, Port: net.PortFromBytes(ports[2:4])}
+	return header, nil
+}
+
+// ips returns the IPs of source and destination in the same family, or nil if either of them is not an IP.
+func (h *Header) ips() (net.IP, net.IP) {
+	if !h.Source.IsValid() || !h.Destination.IsValid() {
+		return nil, nil
+	}
+	if !h.Source.Address.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) || !h.Destination.Address.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) {
+		return nil, nil
+	}
+	src := h.Source.Address.IP()
+	dst := h.Destination.Address.IP()
+	if h.Source.Address.Family().IsIPv4() && h.Destination.Address.Family().IsIPv4() {
+		return src.To4(), dst.To4()
+	}
+	return src.To16(), dst.To16()
+}
+
+// WriteTo writes the header into the given buffer.
+func (h *Header) WriteTo(b *buf.Buffer) error {
+	switch h.Version {
+	case 1:
+		return h.writeV1(b)
+	case 2:
+		return h.writeV2(b)
+	default:
+		return newError("unknown version: ", h.Version)
+	}
+}
+
+func (h *Header) writeV1(b *buf.Buffer) error {
+	src, dst := h.ips()
+	if src == nil {
+		return b.AppendSupplier(serial.WriteString("PROXY UNKNOWN\r\n"))
+	}
+	protocol, srcText, dstText := "TCP4", src.String(), dst.String()
+	if len(src) != net.IPv4len {
+		protocol, srcText, dstText = "TCP6", v1IPv6String(src), v1IPv6String(dst)
+	}
+	return b.AppendSupplier(serial.WriteString(strings.Join([]string{
+		"PROXY", protocol, srcText, dstText, h.Source.Port.String(), h.Destination.Port.String(),
+	}, " ") + "\r\n"))
+}
+
+// v1IPv6String returns the IPv6 text of a 16-byte IP. Unlike net.IP.String(), IPv4-mapped addresses are not printed in
+// dotted form, as TCP6 requires IPv6 addresses.
+func v1IPv6String(ip net.IP) string {
+	if ip.To4() == nil {
+		return ip.String()
+	}
+	return "::ffff:" + strconv.FormatUint(uint64(ip[12])<<8|uint64(ip[13]), 16) + ":" + strconv.FormatUint(uint64(ip[14])<<8|uint64(ip[15]), 16)
+}
+
+func (h *Header) writeV2(b *buf.Buffer) error {
+	common.Must2(b.Write(v2Signature))
+
+	src, dst := h.ips()
+	if src == nil {
+		common.Must2(b.AppendBytes(0x20|v2CommandLocal, 0x00, 0x00, 0x00))
+		return nil
+	}
+
+	var family byte
+	switch {
+	case len(src) == net.IPv4len && h.Destination.Network == net.Network_UDP:
+		family = v2FamilyUDP4
+	case len(src) == net.IPv4len:
+		family = v2FamilyTCP4
+	case h.Destination.Network == net.Network_UDP:
+		family = v2FamilyUDP6
+	default:
+		family = v2FamilyTCP6
+	}
+	common.Must2(b.AppendBytes(0x20|v2CommandProxy, family))
+	common.Must(b.AppendSupplier(serial.WriteUint16(uint16(len(src)*2 + 4))))
+	common.Must2(b.Write(src))
+	common.Must2(b.Write(dst))
+	common.Must(b.AppendSupplier(serial.WriteUint16(h.Source.Port.Value())))
+	return b.AppendSupplier(serial.WriteUint16(h.Destination.Port.Value()))
+}
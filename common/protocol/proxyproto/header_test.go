@@ -0,0 +1,185 @@
+package proxyproto_test
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/common/protocol/proxyproto"
+	. "v2ray.com/ext/assert"
+)
+
+func TestReadV1Header(t *testing.T) {
+	assert := With(t)
+
+	reader := bufio.NewReader(bytes.NewReader([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET /")))
+	header, err := ReadHeader(reader)
+	assert(err, IsNil)
+	assert(header.Version, Equals, byte(1))
+	assert(header.Source, Equals, net.TCPDestination(net.ParseAddress("192.168.0.1"), 56324))
+	assert(header.Destination, Equals, net.TCPDestination(net.ParseAddress("192.168.0.11"), 443))
+
+	rest, err := ioutil.ReadAll(reader)
+	assert(err, IsNil)
+	assert(string(rest), Equals, "GET /")
+}
+
+func TestReadV1UnknownHeader(t *testing.T) {
+	assert := With(t)
+
+	reader := bufio.NewReader(bytes.NewReader([]byte("PROXY UNKNOWN\r\n")))
+	header, err := ReadHeader(reader)
+	assert(err, IsNil)
+	assert(header.Version, Equals, byte(1))
+	assert(header.Source.IsValid(), IsFalse)
+}
+
+func TestReadV2Header(t *testing.T) {
+	assert := With(t)
+
+	b := []byte("\r\n\r\n\x00\r\nQUIT\n")
+	b = append(b, 0x21, 0x21, 0x00, 36+3)
+	b = append(b, net.ParseIP("2001:db8::1")...)
+	b = append(b, net.ParseIP("2001:db8::2")...)
+	b = append(b, 0x1f, 0x90, 0x01, 0xbb)
+	// A TLV, which is ignored.
+	b = append(b, 0x04, 0x00, 0x00)
+	b = append(b, []byte("payload")...)
+
+	reader := bufio.NewReader(bytes.NewReader(b))
+	header, err := ReadHeader(reader)
+	assert(err, IsNil)
+	assert(header.Version, Equals, byte(2))
+	assert(header.Source, Equals, net.TCPDestination(net.ParseAddress("2001:db8::1"), 8080))
+	assert(header.Destination, Equals, net.TCPDestination(net.ParseAddress("2001:db8::2"), 443))
+
+	rest, err := ioutil.ReadAll(reader)
+	assert(err, IsNil)
+	assert(string(rest), Equals, "payload")
+}
+
+func TestReadNoHeader(t *testing.T) {
+	assert := With(t)
+
+	for _, payload := range []string{"PROXZ", "\r\n\r\nabc", "GET /"} {
+		reader := bufio.NewReader(bytes.NewReader([]byte(payload)))
+		header, err := ReadHeader(reader)
+		assert(err, IsNil)
+		assert(header == nil, IsTrue)
+
+		rest, err := ioutil.ReadAll(reader)
+		assert(err, IsNil)
+		assert(string(rest), Equals, payload)
+	}
+}
+
+func TestReadInvalidHeader(t *testing.T) {
+	assert := With(t)
+
+	for _, payload := range []string{"PROXY TCP4 1.2.3.4\r\n", "PROXY TCP4 a b 1 2\r\n", "PROXY TCP4 1.2.3.4 1.2.3.4 1 2\n"} {
+		_, err := ReadHeader(bufio.NewReader(bytes.NewReader([]byte(payload))))
+		assert(err, IsNotNil)
+	}
+}
+
+func TestWriteHeader(t *testing.T) {
+	assert := With(t)
+
+	for _, testCase := range []struct {
+		header   Header
+		expected Header
+	}{
+		{
+			header: Header{
+				Version:     1,
+				Source:      net.TCPDestination(net.ParseAddress("10.0.0.1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("10.0.0.2"), 80),
+			},
+		},
+		{
+			header: Header{
+				Version:     1,
+				Source:      net.TCPDestination(net.ParseAddress("10.0.0.1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 80),
+			},
+			expected: Header{
+				Version:     1,
+				Source:      net.TCPDestination(net.ParseAddress("::ffff:10.0.0.1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 80),
+			},
+		},
+		{
+			header: Header{
+				Version:     2,
+				Source:      net.TCPDestination(net.ParseAddress("2001:db8::1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 80),
+			},
+		},
+		{
+			header: Header{
+				Version:     2,
+				Source:      net.UDPDestination(net.ParseAddress("10.0.0.1"), 1234),
+				Destination: net.UDPDestination(net.ParseAddress("10.0.0.2"), 53),
+			},
+		},
+		{
+			header: Header{
+				Version:     2,
+				Destination: net.TCPDestination(net.ParseAddress("10.0.0.2"), 80),
+			},
+			expected: Header{Version: 2},
+		},
+	} {
+		b := buf.New()
+		common.Must(testCase.header.WriteTo(b))
+		common.Must2(b.Write([]byte("payload")))
+
+		reader := bufio.NewReader(bytes.NewReader(b.Bytes()))
+		header, err := ReadHeader(reader)
+		assert(err, IsNil)
+		expected := testCase.expected
+		if expected.Version == 0 {
+			expected = testCase.header
+		}
+		assert(*header, Equals, expected)
+
+		rest, err := ioutil.ReadAll(reader)
+		assert(err, IsNil)
+		assert(string(rest), Equals, "payload")
+	}
+}
+
+func TestWriteV1MixedFamilies(t *testing.T) {
+	assert := With(t)
+
+	for _, testCase := range []struct {
+		header   Header
+		expected string
+	}{
+		{
+			header: Header{
+				Version:     1,
+				Source:      net.TCPDestination(net.ParseAddress("10.0.0.1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 80),
+			},
+			expected: "PROXY TCP6 ::ffff:a00:1 2001:db8::2 1234 80\r\n",
+		},
+		{
+			header: Header{
+				Version:     1,
+				Source:      net.TCPDestination(net.ParseAddress("2001:db8::1"), 1234),
+				Destination: net.TCPDestination(net.ParseAddress("192.168.1.20"), 80),
+			},
+			expected: "PROXY TCP6 2001:db8::1 ::ffff:c0a8:114 1234 80\r\n",
+		},
+	} {
+		b := buf.New()
+		common.Must(testCase.header.WriteTo(b))
+		assert(b.String(), Equals, testCase.expected)
+		b.Release()
+	}
+}
@@ -0,0 +1,5 @@
+// Package proxyproto implements the PROXY protocol (v1 and v2) of HAProxy,
+// which carries the original addresses of a connection through load balancers.
+package proxyproto
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg proxyproto -path Protocol,ProxyProto
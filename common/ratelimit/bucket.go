@@ -0,0 +1,107 @@
+// Package ratelimit provides token bucket rate limiters for traffic.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket. Each token allows one byte of traffic. A Bucket is safe for concurrent use.
+type Bucket struct {
+	access sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket creates a new Bucket that refills rate tokens per second, and holds at most burst tokens.
+func NewBucket(rate uint64, burst uint64) *Bucket {
+	b := &Bucket{
+		last: time.Now(),
+	}
+	b.SetLimit(rate, burst)
+	b.tokens = b.burst
+	return b
+}
+
+// SetLimit changes the rate and burst of this Bucket.
+func (b *Bucket) SetLimit(rate uint64, burst uint64) {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	if burst < rate {
+		burst = rate
+	}
+	b.rate = float64(rate)
+	b.burst = float64(burst)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// Limit returns the rate and burst of this Bucket.
+func (b *Bucket) Limit() (uint64, uint64) {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	return uint64(b.rate), uint64(b.burst)
+}
+
+// RefillTime returns the duration before this Bucket is full again, or 0 if it is full. A full Bucket behaves the same as a new one.
+func (b *Bucket) RefillTime() time.Duration {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+	missing := b.burst - b.tokens - time.Since(b.last).Seconds()*b.rate
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// reserve takes n tokens from the bucket, and returns the duration before the tokens are available.
+func (b *Bucket) reserve(n int64) time.Duration {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until n tokens are taken from the bucket, or the context is done.
+// A request larger than burst is allowed, and it is paid back by following requests.
+func (b *Bucket) Wait(ctx context.Context, n int64) error {
+	d := b.reserve(n)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,48 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "v2ray.com/core/common/ratelimit"
+	. "v2ray.com/ext/assert"
+)
+
+func TestBucketBurst(t *testing.T) {
+	assert := With(t)
+
+	bucket := NewBucket(1024, 4096)
+
+	start := time.Now()
+	assert(bucket.Wait(context.Background(), 4096), IsNil)
+	assert(time.Since(start) < 100*time.Millisecond, IsTrue)
+
+	start = time.Now()
+	assert(bucket.Wait(context.Background(), 512), IsNil)
+	assert(time.Since(start) >= 400*time.Millisecond, IsTrue)
+}
+
+func TestBucketCancel(t *testing.T) {
+	assert := With(t)
+
+	bucket := NewBucket(1, 1)
+	assert(bucket.Wait(context.Background(), 1), IsNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert(bucket.Wait(ctx, 100), IsNotNil)
+}
+
+func TestBucketRefillTime(t *testing.T) {
+	assert := With(t)
+
+	bucket := NewBucket(1024, 4096)
+	assert(bucket.RefillTime(), Equals, time.Duration(0))
+
+	assert(bucket.Wait(context.Background(), 2048), IsNil)
+	d := bucket.RefillTime()
+	assert(d > 1900*time.Millisecond, IsTrue)
+	assert(d <= 2*time.Second, IsTrue)
+}
@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"context"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/transport/pipe"
+)
+
+// Writer is a buf.Writer that limits the rate of writing by a Bucket.
+type Writer struct {
+	Context context.Context
+	Bucket  *Bucket
+	Writer  buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *Writer) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := w.Bucket.Wait(w.Context, int64(mb.Len())); err != nil {
+		mb.Release()
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *Writer) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *Writer) CloseError() {
+	pipe.CloseError(w.Writer)
+}
@@ -27,6 +27,40 @@ type StatsPolicy struct {
 	UserDownlink bool
 }
 
+// RateLimit is a token bucket limit on traffic.
+type RateLimit struct {
+	// Number of bytes allowed per second. Traffic is not limited if zero.
+	BytesPerSecond uint64
+	// Max number of bytes that can be sent at once.
+	Burst uint64
+}
+
+// IsLimited returns true if the traffic is limited by this RateLimit.
+func (r RateLimit) IsLimited() bool {
+	return r.BytesPerSecond > 0
+}
+
+// BandwidthPolicy contains rate limits for user traffic. The limits are shared among all connections of the same user.
+type BandwidthPolicy struct {
+	// Rate limit for user uplink traffic.
+	Uplink RateLimit
+	// Rate limit for user downlink traffic.
+	Downlink RateLimit
+}
+
+// ConnectionPolicy contains limits on connections of a user.
+type ConnectionPolicy struct {
+	// Max number of concurrent connections of a user. Not limited if zero.
+	MaxConcurrent uint32
+	// Max number of distinct source IPs that a user connects from at the same time. Not limited if zero.
+	MaxSourceIP uint32
+}
+
+// IsLimited returns true if connections are limited by this ConnectionPolicy.
+func (p ConnectionPolicy) IsLimited() bool {
+	return p.MaxConcurrent > 0 || p.MaxSourceIP > 0
+}
+
 type SystemStatsPolicy struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
 	InboundUplink bool
@@ -40,8 +74,10 @@ type SystemPolicy struct {
 
 // Policy is session based settings for controlling V2Ray requests. It contains various settings (or limits) that may differ for different users in the context.
 type Policy struct {
-	Timeouts TimeoutPolicy // Timeout settings
-	Stats    StatsPolicy
+	Timeouts   TimeoutPolicy // Timeout settings
+	Stats      StatsPolicy
+	Bandwidth  BandwidthPolicy
+	Connection ConnectionPolicy
 }
 
 // PolicyManager is a feature that provides Policy for the given user by its id or level.
@@ -0,0 +1,102 @@
+// Package chain is an outbound handler that sends traffic through a list of other outbound handlers, one after another.
+package chain
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg chain -path Proxy,Chain
+
+import (
+	"context"
+	"strings"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
+	"v2ray.com/core/transport/pipe"
+)
+
+// Handler is an outbound handler for multi-hop chains.
+type Handler struct {
+	tags []string
+	ohm  core.OutboundHandlerManager
+}
+
+// New creates a new chain handler.
+func New(ctx context.Context, config *Config) (*Handler, error) {
+	if len(config.Tag) == 0 {
+		return nil, newError("empty chain")
+	}
+	v := core.MustFromContext(ctx)
+	return &Handler{
+		tags: append([]string(nil), config.Tag...),
+		ohm:  v.OutboundHandlerManager(),
+	}, nil
+}
+
+// Hops implements proxy.Chain.
+func (h *Handler) Hops() []string {
+	return h.tags
+}
+
+// hopDialer dials connections through the hop at the given index of a chain.
+type hopDialer struct {
+	tags     []string
+	handlers []core.OutboundHandler
+	index    int
+}
+
+// Tag implements internet.ProxySystemDialer.
+func (d *hopDialer) Tag() string {
+	return strings.Join(d.tags[:d.index+1], ">")
+}
+
+// Dial implements internet.SystemDialer.
+func (d *hopDialer) Dial(ctx context.Context, src net.Address, dest net.Destination) (net.Conn, error) {
+	newError("hop ", d.index+1, "/", len(d.tags), " [", d.tags[d.index], "] dialing to ", dest).AtDebug().WithContext(ctx).WriteToLog()
+
+	ctx = udp.ContextWithoutFullCone(ctx)
+	ctx = proxy.ContextWithTarget(ctx, dest)
+	ctx = contextForHop(ctx, d.tags, d.handlers, d.index)
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+
+	go d.handlers[d.index].Dispatch(ctx, &core.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	return net.NewConnection(net.ConnectionInputMulti(uplinkWriter), net.ConnectionOutputMulti(downlinkReader)), nil
+}
+
+// contextForHop returns a context in which the hop at the given index dials through the hop before it.
+func contextForHop(ctx context.Context, tags []string, handlers []core.OutboundHandler, index int) context.Context {
+	var dialer internet.SystemDialer
+	if index > 0 {
+		dialer = &hopDialer{
+			tags:     tags,
+			handlers: handlers,
+			index:    index - 1,
+		}
+	}
+	return internet.ContextWithSystemDialer(internet.ContextWithoutDialerSettings(ctx), dialer)
+}
+
+// Process implements proxy.Outbound.
+func (h *Handler) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	handlers := make([]core.OutboundHandler, len(h.tags))
+	for i, tag := range h.tags {
+		handler := h.ohm.GetHandler(tag)
+		if handler == nil {
+			return newError("hop ", i+1, "/", len(h.tags), " [", tag, "] not found")
+		}
+		handlers[i] = handler
+	}
+
+	last := len(handlers) - 1
+	handlers[last].Dispatch(contextForHop(ctx, h.tags, handlers, last), link)
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
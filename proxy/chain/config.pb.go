@@ -0,0 +1,53 @@
+package chain
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Config struct {
+	// Tags of outbound handlers, in the order of hops. Each handler dials
+	// through the one before it. The first handler dials directly.
+	Tag []string `protobuf:"bytes,1,rep,name=tag" json:"tag,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Config) GetTag() []string {
+	if m != nil {
+		return m.Tag
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.chain.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/proxy/chain/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 139 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2f, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x28, 0xca, 0xaf, 0xa8,
+	0xd4, 0x4f, 0xce, 0x48, 0xcc, 0xcc, 0xd3, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28,
+	0xca, 0x2f, 0xc9, 0x17, 0x12, 0x83, 0x29, 0x2c, 0x4a, 0xd5, 0x03, 0x2b, 0xd2, 0x03, 0x2b, 0x52,
+	0x92, 0xe2, 0x62, 0x73, 0x06, 0xab, 0x13, 0x12, 0xe0, 0x62, 0x2e, 0x49, 0x4c, 0x97, 0x60, 0x54,
+	0x60, 0xd6, 0xe0, 0x0c, 0x02, 0x31, 0x9d, 0xec, 0xb8, 0xa4, 0x92, 0xf3, 0x73, 0xf5, 0xb0, 0xeb,
+	0x0c, 0x60, 0x8c, 0x62, 0x05, 0x33, 0x56, 0x31, 0x89, 0x85, 0x19, 0x05, 0x25, 0x56, 0xea, 0x39,
+	0x83, 0x54, 0x04, 0x80, 0x55, 0x38, 0x83, 0x24, 0x92, 0xd8, 0xc0, 0x56, 0x1b, 0x03, 0x02, 0x00,
+	0x00, 0xff, 0xff, 0x7a, 0x52, 0x2e, 0x69, 0xa5, 0x00, 0x00, 0x00,
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/transport/internet"
 )
 
 type key int
@@ -15,6 +16,8 @@ const (
 	inboundEntryPointKey
 	inboundTagKey
 	resolvedIPsKey
+	authFailureKey
+	bindConnectionKey
 )
 
 // ContextWithSource creates a new context with given source.
@@ -76,3 +79,27 @@ func ResolvedIPsFromContext(ctx context.Context) (IPResolver, bool) {
 	ips, ok := ctx.Value(resolvedIPsKey).(IPResolver)
 	return ips, ok
 }
+
+// ContextWithAuthFailureHandler returns a new context in which authentication failures are reported to the given handler.
+func ContextWithAuthFailureHandler(ctx context.Context, handler func()) context.Context {
+	return context.WithValue(ctx, authFailureKey, handler)
+}
+
+// ReportAuthFailure is called by inbound proxies when the client fails to authenticate.
+func ReportAuthFailure(ctx context.Context) {
+	if handler, ok := ctx.Value(authFailureKey).(func()); ok {
+		handler()
+	}
+}
+
+// ContextWithBindConnection returns a new context in which the dispatcher relays the link to the given connection,
+// which was accepted by a BindListener, instead of sending it to an outbound handler.
+func ContextWithBindConnection(ctx context.Context, conn internet.Connection) context.Context {
+	return context.WithValue(ctx, bindConnectionKey, conn)
+}
+
+// BindConnectionFromContext returns the connection set by ContextWithBindConnection(), if any.
+func BindConnectionFromContext(ctx context.Context) (internet.Connection, bool) {
+	conn, ok := ctx.Value(bindConnectionKey).(internet.Connection)
+	return conn, ok
+}
@@ -24,6 +24,9 @@ type Config struct {
 	Timeout        uint32                              `protobuf:"varint,4,opt,name=timeout" json:"timeout,omitempty"`
 	FollowRedirect bool                                `protobuf:"varint,5,opt,name=follow_redirect,json=followRedirect" json:"follow_redirect,omitempty"`
 	UserLevel      uint32                              `protobuf:"varint,6,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
+	// Sends UDP packets of each client through a single full-cone link, so that responses from any source are passed
+	// back. Outbounds without full-cone support only send packets to the first destination.
+	UdpFullCone bool `protobuf:"varint,7,opt,name=udp_full_cone,json=udpFullCone" json:"udp_full_cone,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -73,6 +76,13 @@ func (m *Config) GetUserLevel() uint32 {
 	return 0
 }
 
+func (m *Config) GetUdpFullCone() bool {
+	if m != nil {
+		return m.UdpFullCone
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.dokodemo.Config")
 }
@@ -80,25 +90,26 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/proxy/dokodemo/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 310 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xcf, 0x4e, 0xc2, 0x40,
-	0x10, 0x87, 0xd3, 0x8a, 0x80, 0x8b, 0x7f, 0x92, 0x3d, 0x2d, 0x46, 0x12, 0xe4, 0x02, 0xf1, 0xb0,
-	0x4d, 0xf0, 0xe8, 0x0d, 0x30, 0xc6, 0x84, 0x28, 0xd9, 0x83, 0x07, 0x2f, 0xa4, 0xb6, 0x83, 0x69,
-	0xe8, 0xee, 0x90, 0xe9, 0x02, 0xf6, 0x05, 0x7c, 0x18, 0x9f, 0xd2, 0x74, 0xdb, 0x46, 0x63, 0x82,
-	0xb7, 0xd9, 0xdf, 0x7e, 0xf3, 0xcd, 0x64, 0xd8, 0xcd, 0x6e, 0x4c, 0x61, 0x2e, 0x23, 0xd4, 0x41,
-	0x84, 0x04, 0xc1, 0x86, 0xf0, 0x23, 0x0f, 0x62, 0x5c, 0x63, 0x0c, 0x1a, 0x83, 0x08, 0xcd, 0x2a,
-	0x79, 0x97, 0x1b, 0x42, 0x8b, 0xbc, 0x5b, 0xb3, 0x04, 0xd2, 0x71, 0xb2, 0xe6, 0x2e, 0x87, 0x7f,
-	0x34, 0x11, 0x6a, 0x8d, 0x26, 0x30, 0x60, 0x83, 0x30, 0x8e, 0x09, 0xb2, 0xac, 0x74, 0xfc, 0x07,
-	0x1a, 0xb0, 0x7b, 0xa4, 0x75, 0x09, 0x0e, 0x3e, 0x7d, 0xd6, 0x9c, 0xba, 0xe9, 0xfc, 0x8e, 0xb5,
-	0x2a, 0x89, 0xf0, 0xfa, 0xde, 0xa8, 0x33, 0xbe, 0x96, 0xbf, 0x36, 0x29, 0x0d, 0xd2, 0x80, 0x95,
-	0x8f, 0x8b, 0x67, 0x9a, 0xa1, 0x0e, 0x13, 0xa3, 0xea, 0x0e, 0xce, 0x59, 0x63, 0x83, 0x64, 0x85,
-	0xdf, 0xf7, 0x46, 0x67, 0xca, 0xd5, 0xfc, 0x9e, 0x9d, 0x56, 0xc3, 0x96, 0x69, 0x92, 0x59, 0x71,
-	0xe4, 0xac, 0x83, 0x03, 0xd6, 0xa7, 0x12, 0x9d, 0x27, 0x99, 0x55, 0x1d, 0xf3, 0xf3, 0xe0, 0x57,
-	0xac, 0x65, 0x13, 0x0d, 0xb8, 0xb5, 0xa2, 0x51, 0xd8, 0x27, 0xbe, 0xf0, 0x54, 0x1d, 0xf1, 0x21,
-	0xbb, 0x58, 0x61, 0x9a, 0xe2, 0x7e, 0x49, 0x10, 0x27, 0x04, 0x91, 0x15, 0xc7, 0x7d, 0x6f, 0xd4,
-	0x56, 0xe7, 0x65, 0xac, 0xaa, 0x94, 0xf7, 0x18, 0xdb, 0x66, 0x40, 0xcb, 0x14, 0x76, 0x90, 0x8a,
-	0xa6, 0xdb, 0xf3, 0xa4, 0x48, 0xe6, 0x45, 0x30, 0x79, 0x60, 0xbd, 0x08, 0xb5, 0x3c, 0x78, 0xfb,
-	0x85, 0xf7, 0xda, 0xae, 0xeb, 0x2f, 0xbf, 0xfb, 0x32, 0x56, 0x61, 0x2e, 0xa7, 0x05, 0xb7, 0x70,
-	0xdc, 0xac, 0xfa, 0x7b, 0x6b, 0xba, 0xc3, 0xde, 0x7e, 0x07, 0x00, 0x00, 0xff, 0xff, 0x33, 0x8e,
-	0x70, 0xce, 0xf3, 0x01, 0x00, 0x00,
+	// 335 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0x51, 0x4f, 0xea, 0x30,
+	0x14, 0xc7, 0xb3, 0x5d, 0x2e, 0x70, 0xcb, 0x45, 0x93, 0x3e, 0x15, 0x23, 0x09, 0xf2, 0x02, 0xf1,
+	0xa1, 0x4b, 0xf0, 0xd1, 0x37, 0x40, 0x8d, 0x09, 0x51, 0xb2, 0x07, 0x1f, 0x7c, 0x59, 0xe6, 0x7a,
+	0x30, 0x0b, 0x6d, 0xcf, 0xd2, 0x75, 0x20, 0x5f, 0x89, 0x4f, 0x69, 0xd6, 0x6d, 0xd1, 0x98, 0xe0,
+	0xdb, 0xe9, 0xbf, 0xbf, 0xfe, 0xce, 0x49, 0x0f, 0xb9, 0xde, 0xcd, 0x4c, 0x7c, 0xe0, 0x09, 0xaa,
+	0x20, 0x41, 0x03, 0x41, 0x66, 0xf0, 0xe3, 0x10, 0x08, 0xdc, 0xa2, 0x00, 0x85, 0x41, 0x82, 0x7a,
+	0x93, 0xbe, 0xf3, 0xcc, 0xa0, 0x45, 0x3a, 0x68, 0x58, 0x03, 0xdc, 0x71, 0xbc, 0xe1, 0x2e, 0x26,
+	0x3f, 0x34, 0x09, 0x2a, 0x85, 0x3a, 0xd0, 0x60, 0x83, 0x58, 0x08, 0x03, 0x79, 0x5e, 0x39, 0x7e,
+	0x03, 0x35, 0xd8, 0x3d, 0x9a, 0x6d, 0x05, 0x8e, 0x8f, 0x3e, 0x69, 0x2f, 0x5c, 0x77, 0x7a, 0x4b,
+	0x3a, 0xb5, 0x84, 0x79, 0x23, 0x6f, 0xda, 0x9b, 0x5d, 0xf1, 0x6f, 0x93, 0x54, 0x06, 0xae, 0xc1,
+	0xf2, 0xc7, 0xf5, 0xb3, 0x59, 0xa2, 0x8a, 0x53, 0x1d, 0x36, 0x2f, 0x28, 0x25, 0xad, 0x0c, 0x8d,
+	0x65, 0xfe, 0xc8, 0x9b, 0xf6, 0x43, 0x57, 0xd3, 0x3b, 0xf2, 0xbf, 0x6e, 0x16, 0xc9, 0x34, 0xb7,
+	0xec, 0x8f, 0xb3, 0x8e, 0x4f, 0x58, 0x9f, 0x2a, 0x74, 0x95, 0xe6, 0x36, 0xec, 0xe9, 0xaf, 0x03,
+	0xbd, 0x24, 0x1d, 0x9b, 0x2a, 0xc0, 0xc2, 0xb2, 0x56, 0x69, 0x9f, 0xfb, 0xcc, 0x0b, 0x9b, 0x88,
+	0x4e, 0xc8, 0xf9, 0x06, 0xa5, 0xc4, 0x7d, 0x64, 0x40, 0xa4, 0x06, 0x12, 0xcb, 0xfe, 0x8e, 0xbc,
+	0x69, 0x37, 0x3c, 0xab, 0xe2, 0xb0, 0x4e, 0xe9, 0x90, 0x90, 0x22, 0x07, 0x13, 0x49, 0xd8, 0x81,
+	0x64, 0x6d, 0x37, 0xe7, 0xbf, 0x32, 0x59, 0x95, 0x01, 0x1d, 0x93, 0x7e, 0x21, 0xb2, 0x68, 0x53,
+	0x48, 0x19, 0x25, 0xa8, 0x81, 0x75, 0x9c, 0xa5, 0x57, 0x88, 0xec, 0xbe, 0x90, 0x72, 0x81, 0x1a,
+	0xe6, 0x0f, 0x64, 0x98, 0xa0, 0xe2, 0x27, 0xf7, 0xb3, 0xf6, 0x5e, 0xbb, 0x4d, 0x7d, 0xf4, 0x07,
+	0x2f, 0xb3, 0x30, 0x3e, 0xf0, 0x45, 0xc9, 0xad, 0x1d, 0xb7, 0xac, 0xef, 0xde, 0xda, 0xee, 0xf3,
+	0x6f, 0x3e, 0x03, 0x00, 0x00, 0xff, 0xff, 0xfc, 0xe9, 0x9a, 0xca, 0x17, 0x02, 0x00, 0x00,
 }
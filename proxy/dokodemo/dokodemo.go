@@ -4,6 +4,7 @@ package dokodemo
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
@@ -68,6 +69,10 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		return newError("unable to get destination")
 	}
 
+	if network == net.Network_UDP && d.config.UdpFullCone {
+		return d.processFullCone(ctx, dest, conn, dispatcher)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, d.policy().Timeouts.ConnectionIdle)
 
@@ -125,6 +130,68 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 	return nil
 }
 
+// processFullCone sends UDP packets of the client to dest through a full-cone link. Responses from any source are
+// written back to the client, from their own source addresses in TPROXY mode.
+func (d *DokodemoDoor) processFullCone(ctx context.Context, dest net.Destination, conn internet.Connection, dispatcher core.Dispatcher) error {
+	var access sync.Mutex
+	plainWriter := buf.NewSequentialWriter(conn)
+	forgedConns := make(map[net.Destination]net.Conn)
+	defer func() {
+		access.Lock()
+		defer access.Unlock()
+		for _, c := range forgedConns {
+			c.Close()
+		}
+		forgedConns = nil
+	}()
+
+	writerFor := func(source net.Destination) (buf.Writer, error) {
+		if !d.config.FollowRedirect {
+			return plainWriter, nil
+		}
+		access.Lock()
+		defer access.Unlock()
+		if forgedConns == nil {
+			return nil, newError("connection closed")
+		}
+		c, found := forgedConns[source]
+		if !found {
+			srca := net.UDPAddr{IP: source.Address.IP(), Port: int(source.Port.Value())}
+			origsend, err := udp.TransmitSocket(&srca, conn.RemoteAddr())
+			if err != nil {
+				return nil, err
+			}
+			c = origsend
+			forgedConns[source] = c
+		}
+		return buf.NewSequentialWriter(c), nil
+	}
+
+	udpServer := udp.NewFullConeDispatcher(dispatcher, func(source net.Destination, payload *buf.Buffer) {
+		writer, err := writerFor(source)
+		if err != nil {
+			newError("failed to write UDP response from ", source).Base(err).WithContext(ctx).WriteToLog()
+			payload.Release()
+			return
+		}
+		if err := writer.WriteMultiBuffer(buf.NewMultiBufferValue(payload)); err != nil {
+			newError("failed to write UDP response from ", source).Base(err).WithContext(ctx).WriteToLog()
+		}
+	})
+	defer udpServer.Close()
+
+	reader := buf.NewReader(conn)
+	for {
+		mb, err := reader.ReadMultiBuffer()
+		if err != nil {
+			return nil
+		}
+		for _, payload := range mb {
+			udpServer.Dispatch(ctx, dest, payload)
+		}
+	}
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*Config))
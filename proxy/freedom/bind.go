@@ -0,0 +1,87 @@
+package freedom
+
+import (
+	"context"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+)
+
+// Bind implements proxy.Binder. It listens on a random TCP port of the address that dialer dials from, and accepts the
+// first connection from the peer. Connections from any host are accepted if the IP of the peer is unspecified.
+func (h *Handler) Bind(ctx context.Context, peer net.Destination, dialer proxy.Dialer) (proxy.BindListener, error) {
+	l, ok := dialer.(proxy.Listener)
+	if !ok {
+		return nil, newError("dialer is unable to listen for peer ", peer)
+	}
+
+	var peerIPs []net.IP
+	switch {
+	case peer.Address.Family().IsDomain():
+		ips, err := h.dns.LookupIP(peer.Address.Domain())
+		if err != nil || len(ips) == 0 {
+			return nil, newError("failed to get IP address for domain ", peer.Address.Domain()).Base(err)
+		}
+		peerIPs = ips
+	case !peer.Address.IP().IsUnspecified():
+		peerIPs = []net.IP{peer.Address.IP()}
+	}
+
+	listener, err := l.Listen(ctx)
+	if err != nil {
+		return nil, newError("failed to listen for peer ", peer).Base(err)
+	}
+	newError("listening on ", listener.Addr(), " for peer ", peer).WithContext(ctx).WriteToLog()
+
+	return &bindListener{
+		ctx:      ctx,
+		listener: listener,
+		peerIPs:  peerIPs,
+	}, nil
+}
+
+type bindListener struct {
+	ctx      context.Context
+	listener net.Listener
+	peerIPs  []net.IP
+}
+
+func (l *bindListener) Addr() net.Destination {
+	return net.DestinationFromAddr(l.listener.Addr())
+}
+
+func (l *bindListener) isPeer(addr net.Addr) bool {
+	if len(l.peerIPs) == 0 {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ip := range l.peerIPs {
+		if ip.Equal(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *bindListener) Accept() (internet.Connection, net.Destination, error) {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return nil, net.Destination{}, newError("failed to accept peer").Base(err)
+		}
+		if !l.isPeer(conn.RemoteAddr()) {
+			newError("rejecting connection from unexpected peer ", conn.RemoteAddr()).AtWarning().WithContext(l.ctx).WriteToLog()
+			conn.Close()
+			continue
+		}
+		return conn, net.DestinationFromAddr(conn.RemoteAddr()), nil
+	}
+}
+
+func (l *bindListener) Close() error {
+	return l.listener.Close()
+}
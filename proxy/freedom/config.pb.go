@@ -58,6 +58,10 @@ type Config struct {
 	Timeout             uint32                `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
 	DestinationOverride *DestinationOverride  `protobuf:"bytes,3,opt,name=destination_override,json=destinationOverride" json:"destination_override,omitempty"`
 	UserLevel           uint32                `protobuf:"varint,4,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
+	// Version of PROXY protocol header (1 or 2) sent on TCP connections to
+	// the destination, carrying the source address of the inbound connection.
+	// No header is sent if 0.
+	ProxyProtocol uint32 `protobuf:"varint,5,opt,name=proxy_protocol,json=proxyProtocol" json:"proxy_protocol,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -93,6 +97,13 @@ func (m *Config) GetUserLevel() uint32 {
 	return 0
 }
 
+func (m *Config) GetProxyProtocol() uint32 {
+	if m != nil {
+		return m.ProxyProtocol
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*DestinationOverride)(nil), "v2ray.core.proxy.freedom.DestinationOverride")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.freedom.Config")
@@ -102,27 +113,28 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/proxy/freedom/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 340 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x6f, 0x4b, 0x83, 0x50,
-	0x14, 0xc6, 0xd3, 0xca, 0xb1, 0x13, 0xad, 0xe1, 0x7a, 0x21, 0xb1, 0x60, 0xec, 0x4d, 0x2b, 0xe8,
-	0x1a, 0xf6, 0x09, 0xda, 0x9f, 0x60, 0x10, 0x34, 0x94, 0xa2, 0x7a, 0x63, 0xa6, 0x67, 0x43, 0x98,
-	0xf7, 0xc8, 0xf5, 0x4e, 0xf2, 0x2b, 0xed, 0x53, 0x86, 0x57, 0x47, 0x2d, 0xb6, 0x77, 0xfa, 0xf8,
-	0x7b, 0x9e, 0x73, 0x9e, 0x23, 0x5c, 0xe7, 0x8e, 0x08, 0x0a, 0x16, 0x52, 0x62, 0x87, 0x24, 0xd0,
-	0x4e, 0x05, 0x7d, 0x17, 0xf6, 0x5c, 0x20, 0x46, 0x4a, 0xe2, 0xf3, 0x78, 0xc1, 0x52, 0x41, 0x92,
-	0x4c, 0x6b, 0x83, 0x0a, 0x64, 0x0a, 0x63, 0x35, 0x76, 0x71, 0xf7, 0x2f, 0x24, 0xa4, 0x24, 0x21,
-	0x6e, 0x2b, 0x5b, 0x48, 0x4b, 0x3b, 0x43, 0x91, 0xa3, 0xf0, 0xb3, 0x14, 0xc3, 0x2a, 0xab, 0xff,
-	0x0e, 0x9d, 0x31, 0x66, 0x32, 0xe6, 0x81, 0x8c, 0x89, 0x3f, 0xe7, 0x28, 0x44, 0x1c, 0xa1, 0x39,
-	0x04, 0xa3, 0x62, 0x2d, 0xad, 0xa7, 0x0d, 0x4e, 0x9c, 0x1b, 0xf6, 0x67, 0x66, 0x95, 0xca, 0x36,
-	0xa9, 0xcc, 0x53, 0xe4, 0x84, 0x47, 0x29, 0xc5, 0x5c, 0xba, 0xb5, 0xb3, 0xbf, 0xd6, 0xc1, 0x18,
-	0xa9, 0xbd, 0xcd, 0x37, 0x38, 0x8b, 0x28, 0x09, 0x62, 0xee, 0x67, 0x52, 0x04, 0x12, 0x17, 0x85,
-	0xca, 0x6d, 0x39, 0x36, 0xdb, 0xd7, 0x85, 0x55, 0x56, 0x36, 0x56, 0x3e, 0xaf, 0xb6, 0xb9, 0xad,
-	0x68, 0xeb, 0xdd, 0xec, 0x42, 0x43, 0xc6, 0x09, 0xd2, 0x4a, 0x5a, 0x7a, 0x4f, 0x1b, 0x9c, 0x0e,
-	0x75, 0x4b, 0x73, 0x37, 0x92, 0xf9, 0x09, 0xe7, 0xd1, 0x6f, 0x3b, 0x9f, 0xea, 0x7a, 0xd6, 0xa1,
-	0x2a, 0x75, 0xbb, 0x7f, 0xf8, 0x8e, 0x9b, 0xb8, 0x9d, 0x68, 0xc7, 0xa1, 0x2e, 0x01, 0x56, 0x19,
-	0x0a, 0x7f, 0x89, 0x39, 0x2e, 0xad, 0xa3, 0x72, 0x05, 0xb7, 0x59, 0x2a, 0x4f, 0xa5, 0xd0, 0xbf,
-	0x82, 0xd6, 0x76, 0x01, 0xb3, 0x09, 0xc7, 0x0f, 0x9e, 0x3f, 0xf5, 0xda, 0x07, 0x26, 0x80, 0xf1,
-	0xe2, 0x4d, 0xfc, 0xe9, 0xac, 0xad, 0x0d, 0xc7, 0xd0, 0x0d, 0x29, 0xd9, 0xbb, 0xd0, 0x4c, 0xfb,
-	0x68, 0xd4, 0x8f, 0x6b, 0xdd, 0x7a, 0x75, 0xdc, 0xa0, 0x60, 0xa3, 0x92, 0x9a, 0x29, 0xea, 0xb1,
-	0xfa, 0xf4, 0x65, 0xa8, 0x7f, 0x72, 0xff, 0x13, 0x00, 0x00, 0xff, 0xff, 0xb7, 0x5e, 0xda, 0x4d,
-	0x4d, 0x02, 0x00, 0x00,
+	// 361 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xef, 0x6a, 0xa3, 0x40,
+	0x14, 0xc5, 0x57, 0x77, 0x63, 0xc8, 0x5d, 0xe2, 0x86, 0xc9, 0x7e, 0x18, 0x96, 0x2c, 0x84, 0xc0,
+	0xb2, 0x69, 0xa1, 0x63, 0xb1, 0x4f, 0xd0, 0xfc, 0x29, 0x04, 0x0a, 0x15, 0xa5, 0xa5, 0xed, 0x17,
+	0x6b, 0xf5, 0x26, 0x08, 0xd1, 0x91, 0x71, 0x22, 0xf5, 0x95, 0xfa, 0x36, 0x7d, 0xa3, 0xe2, 0xa8,
+	0xb4, 0x29, 0xc9, 0x37, 0x3d, 0xf3, 0x3b, 0xe7, 0xde, 0x73, 0xe1, 0xa4, 0xb0, 0x45, 0x50, 0xb2,
+	0x90, 0x27, 0x56, 0xc8, 0x05, 0x5a, 0x99, 0xe0, 0x2f, 0xa5, 0xb5, 0x16, 0x88, 0x91, 0x92, 0xd2,
+	0x75, 0xbc, 0x61, 0x99, 0xe0, 0x92, 0x13, 0xda, 0xa2, 0x02, 0x99, 0xc2, 0x58, 0x83, 0xfd, 0x39,
+	0xff, 0x12, 0x12, 0xf2, 0x24, 0xe1, 0xa9, 0xa5, 0x6c, 0x21, 0xdf, 0x5a, 0x39, 0x8a, 0x02, 0x85,
+	0x9f, 0x67, 0x18, 0xd6, 0x59, 0x93, 0x07, 0x18, 0x2e, 0x30, 0x97, 0x71, 0x1a, 0xc8, 0x98, 0xa7,
+	0x37, 0x05, 0x0a, 0x11, 0x47, 0x48, 0x66, 0x60, 0xd4, 0x2c, 0xd5, 0xc6, 0xda, 0xf4, 0xa7, 0x7d,
+	0xca, 0x3e, 0xcd, 0xac, 0x53, 0x59, 0x9b, 0xca, 0x3c, 0x45, 0x2e, 0xd3, 0x28, 0xe3, 0x71, 0x2a,
+	0xdd, 0xc6, 0x39, 0x79, 0xd3, 0xc1, 0x98, 0xab, 0xbd, 0xc9, 0x3d, 0xfc, 0x8a, 0x78, 0x12, 0xc4,
+	0xa9, 0x9f, 0x4b, 0x11, 0x48, 0xdc, 0x94, 0x2a, 0xd7, 0xb4, 0x2d, 0x76, 0xac, 0x0b, 0xab, 0xad,
+	0x6c, 0xa1, 0x7c, 0x5e, 0x63, 0x73, 0xcd, 0x68, 0xef, 0x9f, 0x8c, 0xa0, 0x2b, 0xe3, 0x04, 0xf9,
+	0x4e, 0x52, 0x7d, 0xac, 0x4d, 0xfb, 0x33, 0x9d, 0x6a, 0x6e, 0x2b, 0x91, 0x27, 0xf8, 0x1d, 0x7d,
+	0xb4, 0xf3, 0x79, 0x53, 0x8f, 0x7e, 0x57, 0xa5, 0xce, 0x8e, 0x0f, 0x3f, 0x70, 0x13, 0x77, 0x18,
+	0x1d, 0x38, 0xd4, 0x5f, 0x80, 0x5d, 0x8e, 0xc2, 0xdf, 0x62, 0x81, 0x5b, 0xfa, 0xa3, 0x5a, 0xc1,
+	0xed, 0x55, 0xca, 0x75, 0x25, 0x90, 0x7f, 0x60, 0xaa, 0x60, 0xbf, 0x3d, 0x16, 0xed, 0x28, 0xa4,
+	0xaf, 0x54, 0xa7, 0x11, 0x27, 0xff, 0xc1, 0xdc, 0xef, 0x49, 0x7a, 0xd0, 0xb9, 0xf4, 0xfc, 0x95,
+	0x37, 0xf8, 0x46, 0x00, 0x8c, 0x5b, 0x6f, 0xe9, 0xaf, 0x9c, 0x81, 0x36, 0x5b, 0xc0, 0x28, 0xe4,
+	0xc9, 0xd1, 0xbd, 0x1d, 0xed, 0xb1, 0xdb, 0x7c, 0xbe, 0xea, 0xf4, 0xce, 0x76, 0x83, 0x92, 0xcd,
+	0x2b, 0xca, 0x51, 0xd4, 0x55, 0xfd, 0xf4, 0x6c, 0xa8, 0x6d, 0x2e, 0xde, 0x03, 0x00, 0x00, 0xff,
+	0xff, 0xb9, 0x4d, 0x7a, 0xbd, 0x74, 0x02, 0x00, 0x00,
 }
@@ -4,17 +4,21 @@ package freedom
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 	"v2ray.com/core/common/retry"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
 )
 
 // Handler handles Freedom connections.
@@ -63,9 +67,120 @@ func (h *Handler) resolveIP(ctx context.Context, domain string) net.Address {
 	return net.IPAddress(ips[dice.Roll(len(ips))])
 }
 
+// SupportFullCone implements proxy.FullConeOutbound.
+func (h *Handler) SupportFullCone() bool {
+	return h.config.DestinationOverride == nil
+}
+
+// resolvePacketAddr returns the address to send a full-cone packet to.
+func (h *Handler) resolvePacketAddr(ctx context.Context, dest net.Destination) *net.UDPAddr {
+	if !dest.Address.Family().IsDomain() {
+		return &net.UDPAddr{IP: dest.Address.IP(), Port: int(dest.Port)}
+	}
+	ips, err := h.dns.LookupIP(dest.Address.Domain())
+	if err != nil || len(ips) == 0 {
+		newError("failed to get IP address for domain ", dest.Address).Base(err).WithContext(ctx).WriteToLog()
+		return nil
+	}
+	return &net.UDPAddr{IP: ips[dice.Roll(len(ips))], Port: int(dest.Port)}
+}
+
+// listenFullCone returns the UDP socket for a full-cone link. The socket is bound to the local address of the dialer.
+func listenFullCone(ctx context.Context, dialer proxy.Dialer) (*net.UDPConn, error) {
+	listener, ok := dialer.(proxy.Listener)
+	if !ok {
+		return nil, newError("dialer is not able to listen")
+	}
+	return listener.ListenUDP(ctx)
+}
+
+// processFullCone sends packets to all destinations from a single UDP socket, and passes back responses from any source.
+func (h *Handler) processFullCone(ctx context.Context, link *core.Link, conn *net.UDPConn) error {
+	defer conn.Close()
+	newError("opening full-cone UDP from ", conn.LocalAddr()).WithContext(ctx).WriteToLog()
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, h.policy().Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(h.policy().Timeouts.DownlinkOnly)
+
+		// Domains are resolved once per destination, as long as the link lives. Failed lookups are retried on the next packet.
+		addrs := make(map[net.Destination]*net.UDPAddr)
+		for {
+			mb, err := link.Reader.ReadMultiBuffer()
+			if err != nil {
+				if errors.Cause(err) == io.EOF {
+					return nil
+				}
+				return newError("failed to process request").Base(err)
+			}
+			timer.Update()
+
+			for _, b := range mb {
+				if dest, err := udp.DecodePacket(b); err == nil {
+					addr, found := addrs[dest]
+					if !found {
+						addr = h.resolvePacketAddr(ctx, dest)
+						if addr != nil {
+							addrs[dest] = addr
+						}
+					}
+					if addr != nil {
+						conn.WriteToUDP(b.Bytes(), addr)
+					}
+				}
+				b.Release()
+			}
+		}
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(h.policy().Timeouts.UplinkOnly)
+
+		for {
+			var addr *net.UDPAddr
+			b := buf.New()
+			err := b.Reset(func(v []byte) (int, error) {
+				n, a, err := conn.ReadFromUDP(v)
+				addr = a
+				return n, err
+			})
+			if err != nil {
+				b.Release()
+				return newError("failed to process response").Base(err)
+			}
+			timer.Update()
+
+			source := net.UDPDestination(net.IPAddress(addr.IP), net.Port(addr.Port))
+			if err := link.Writer.WriteMultiBuffer(buf.NewMultiBufferValue(udp.EncodePacket(b, source))); err != nil {
+				return newError("failed to process response").Base(err)
+			}
+		}
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestDone, responseDone); err != nil {
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
 // Process implements proxy.Outbound.
 func (h *Handler) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
 	destination, _ := proxy.TargetFromContext(ctx)
+	if destination.Network == net.Network_UDP && udp.IsFullCone(ctx) && h.SupportFullCone() {
+		conn, err := listenFullCone(ctx, dialer)
+		if err == nil {
+			return h.processFullCone(ctx, link, conn)
+		}
+		// Sending from the dialer, e.g., through other handlers, only works for a single destination.
+		newError("falling back to plain UDP to ", destination).Base(err).WithContext(ctx).WriteToLog()
+		link = &core.Link{
+			Reader: &udp.PayloadReader{Reader: link.Reader, Target: destination},
+			Writer: &udp.PacketWriter{Writer: link.Writer, Source: destination},
+		}
+	}
 	if h.config.DestinationOverride != nil {
 		server := h.config.DestinationOverride.Server
 		destination = net.Destination{
@@ -105,6 +220,12 @@ func (h *Handler) Process(ctx context.Context, link *core.Link, dialer proxy.Dia
 	}
 	defer conn.Close()
 
+	if destination.Network == net.Network_TCP && h.config.ProxyProtocol > 0 {
+		if err := h.writeProxyProtocol(ctx, conn); err != nil {
+			return err
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, h.policy().Timeouts.ConnectionIdle)
 
@@ -147,3 +268,25 @@ func init() {
 		return New(ctx, config.(*Config))
 	}))
 }
+
+// writeProxyProtocol sends a PROXY protocol header with the source of the inbound connection.
+func (h *Handler) writeProxyProtocol(ctx context.Context, conn internet.Connection) error {
+	header := &proxyproto.Header{
+		Version:     byte(h.config.ProxyProtocol),
+		Destination: net.DestinationFromAddr(conn.RemoteAddr()),
+	}
+	if source, ok := proxy.SourceFromContext(ctx); ok {
+		header.Source = source
+	}
+
+	b := buf.New()
+	defer b.Release()
+
+	if err := header.WriteTo(b); err != nil {
+		return newError("failed to create PROXY protocol header").Base(err)
+	}
+	if _, err := conn.Write(b.Bytes()); err != nil {
+		return newError("failed to write PROXY protocol header").Base(err)
+	}
+	return nil
+}
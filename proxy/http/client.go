@@ -1,4 +1,163 @@
 package http
 
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/retry"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+)
+
+// Client is an HTTP proxy client, which tunnels TCP connections through CONNECT requests.
 type Client struct {
+	serverPicker  protocol.ServerPicker
+	headers       []*Header
+	policyManager core.PolicyManager
+}
+
+// NewClient creates a new HTTP client based on the given config.
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	serverList := protocol.NewServerList()
+	for _, rec := range config.Server {
+		serverList.AddServer(protocol.NewServerSpecFromPB(*rec))
+	}
+	if serverList.Size() == 0 {
+		return nil, newError("0 target server")
+	}
+
+	v := core.MustFromContext(ctx)
+	return &Client{
+		serverPicker:  protocol.NewRoundRobinServerPicker(serverList),
+		headers:       config.Header,
+		policyManager: v.PolicyManager(),
+	}, nil
+}
+
+// Process implements proxy.Outbound.Process.
+func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	destination, ok := proxy.TargetFromContext(ctx)
+	if !ok {
+		return newError("target not specified.")
+	}
+	if destination.Network != net.Network_TCP {
+		return newError("only TCP is supported in HTTP proxy")
+	}
+
+	var server *protocol.ServerSpec
+	var conn internet.Connection
+
+	if err := retry.ExponentialBackoff(5, 100).On(func() error {
+		server = c.serverPicker.PickServer()
+		rawConn, err := dialer.Dial(ctx, server.Destination())
+		if err != nil {
+			return err
+		}
+		conn = rawConn
+
+		return nil
+	}); err != nil {
+		return newError("failed to find an available destination").Base(err)
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			newError("failed to closed connection").Base(err).WithContext(ctx).WriteToLog()
+		}
+	}()
+
+	p := c.policyManager.ForLevel(0)
+	user := server.PickUser()
+	if user != nil {
+		p = c.policyManager.ForLevel(user.Level)
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(p.Timeouts.Handshake)); err != nil {
+		newError("failed to set deadline for handshake").Base(err).WithContext(ctx).WriteToLog()
+	}
+	reader, err := c.connect(conn, destination, user)
+	if err != nil {
+		return newError("failed to establish connection to server").AtWarning().Base(err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		newError("failed to clear deadline after handshake").Base(err).WithContext(ctx).WriteToLog()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, p.Timeouts.ConnectionIdle)
+
+	requestFunc := func() error {
+		defer timer.SetTimeout(p.Timeouts.DownlinkOnly)
+		return buf.Copy(link.Reader, buf.NewWriter(conn), buf.UpdateActivity(timer))
+	}
+	responseFunc := func() error {
+		defer timer.SetTimeout(p.Timeouts.UplinkOnly)
+		return buf.Copy(buf.NewReader(reader), link.Writer, buf.UpdateActivity(timer))
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestFunc, responseFunc); err != nil {
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+// connect sends a CONNECT request to the server, and returns a reader for the tunneled data once the server accepts it.
+func (c *Client) connect(conn internet.Connection, destination net.Destination, user *protocol.User) (*bufio.Reader, error) {
+	target := destination.NetAddr()
+	request := &http.Request{
+		Method:     "CONNECT",
+		URL:        &url.URL{Host: target},
+		Host:       target,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+	}
+	for _, h := range c.headers {
+		request.Header.Add(h.Key, h.Value)
+	}
+	if user != nil && user.Account != nil {
+		rawAccount, err := user.GetTypedAccount()
+		if err != nil {
+			return nil, newError("failed to get user account").Base(err)
+		}
+		account, ok := rawAccount.(*Account)
+		if !ok {
+			return nil, newError("not an HTTP account")
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(account.Username + ":" + account.Password))
+		request.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, newError("failed to write CONNECT request").Base(err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, newError("failed to read CONNECT response").Base(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, newError("server rejects CONNECT request: ", response.Status)
+	}
+	return reader, nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ClientConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewClient(ctx, config.(*ClientConfig))
+	}))
 }
@@ -3,6 +3,7 @@ package http
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import v2ray_core_common_protocol1 "v2ray.com/core/common/protocol"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -15,6 +16,30 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Account struct {
+	Username string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *Account) Reset()                    { *m = Account{} }
+func (m *Account) String() string            { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()               {}
+func (*Account) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Account) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *Account) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
 // Config for HTTP proxy server.
 type ServerConfig struct {
 	Timeout          uint32            `protobuf:"varint,1,opt,name=timeout" json:"timeout,omitempty"`
@@ -26,7 +51,7 @@ type ServerConfig struct {
 func (m *ServerConfig) Reset()                    { *m = ServerConfig{} }
 func (m *ServerConfig) String() string            { return proto.CompactTextString(m) }
 func (*ServerConfig) ProtoMessage()               {}
-func (*ServerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*ServerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 func (m *ServerConfig) GetTimeout() uint32 {
 	if m != nil {
@@ -56,41 +81,93 @@ func (m *ServerConfig) GetUserLevel() uint32 {
 	return 0
 }
 
+type Header struct {
+	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Header) Reset()                    { *m = Header{} }
+func (m *Header) String() string            { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()               {}
+func (*Header) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Header) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Header) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 // ClientConfig for HTTP proxy client.
 type ClientConfig struct {
+	// HTTP proxy servers to connect to. Users of the servers have Accounts for
+	// Basic authentication.
+	Server []*v2ray_core_common_protocol1.ServerEndpoint `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
+	// Additional headers in CONNECT requests.
+	Header []*Header `protobuf:"bytes,2,rep,name=header" json:"header,omitempty"`
 }
 
 func (m *ClientConfig) Reset()                    { *m = ClientConfig{} }
 func (m *ClientConfig) String() string            { return proto.CompactTextString(m) }
 func (*ClientConfig) ProtoMessage()               {}
-func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ClientConfig) GetServer() []*v2ray_core_common_protocol1.ServerEndpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *ClientConfig) GetHeader() []*Header {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
 
 func init() {
+	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.http.Account")
 	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.http.ServerConfig")
+	proto.RegisterType((*Header)(nil), "v2ray.core.proxy.http.Header")
 	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.http.ClientConfig")
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/proxy/http/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 296 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xcf, 0x4a, 0x33, 0x31,
-	0x14, 0xc5, 0x99, 0x69, 0xbf, 0xcf, 0xf6, 0xda, 0x4a, 0x0d, 0x16, 0x46, 0x51, 0x28, 0x5d, 0x48,
-	0x41, 0xc8, 0x60, 0xdd, 0x88, 0x5d, 0xd9, 0x22, 0xb8, 0x50, 0x28, 0x51, 0x5c, 0xb8, 0x29, 0x31,
-	0x5c, 0xb5, 0x98, 0x26, 0x43, 0xe6, 0xce, 0xe8, 0xec, 0x7d, 0x1a, 0x9f, 0x52, 0x92, 0x5a, 0xff,
-	0x40, 0x57, 0x49, 0x7e, 0xe7, 0xe4, 0xe4, 0x9e, 0xc0, 0x61, 0x39, 0x74, 0xb2, 0xe2, 0xca, 0x2e,
-	0x52, 0x65, 0x1d, 0xa6, 0x99, 0xb3, 0x6f, 0x55, 0xfa, 0x4c, 0x94, 0xa5, 0xca, 0x9a, 0xc7, 0xf9,
-	0x13, 0xcf, 0x9c, 0x25, 0xcb, 0xba, 0x2b, 0x9f, 0x43, 0x1e, 0x3c, 0xdc, 0x7b, 0xfa, 0xef, 0x31,
-	0xb4, 0x6e, 0xd0, 0x95, 0xe8, 0x26, 0xc1, 0xcd, 0xf6, 0x61, 0x83, 0xe6, 0x0b, 0xb4, 0x05, 0x25,
-	0x51, 0x2f, 0x1a, 0xb4, 0xc7, 0x71, 0x12, 0x89, 0x15, 0x62, 0xd7, 0xd0, 0x90, 0x4a, 0xd9, 0xc2,
-	0x50, 0x9e, 0xc4, 0xbd, 0xda, 0x60, 0x73, 0x78, 0xcc, 0xd7, 0x06, 0xf3, 0xdf, 0xa1, 0xfc, 0xfc,
-	0xeb, 0xce, 0x85, 0x21, 0x57, 0x89, 0xef, 0x08, 0x76, 0x04, 0xdb, 0x52, 0x6b, 0xfb, 0x3a, 0x23,
-	0x27, 0x4d, 0x9e, 0x49, 0x87, 0x86, 0x92, 0x5a, 0x2f, 0x1a, 0x34, 0x44, 0x27, 0x08, 0xb7, 0x3f,
-	0x9c, 0x1d, 0x00, 0x14, 0x39, 0xba, 0x99, 0xc6, 0x12, 0x75, 0x52, 0xf7, 0xc3, 0x89, 0xa6, 0x27,
-	0x57, 0x1e, 0xec, 0x8d, 0xa0, 0xfd, 0xe7, 0x19, 0xd6, 0x81, 0xda, 0x0b, 0x56, 0xa1, 0x45, 0x53,
-	0xf8, 0x2d, 0xdb, 0x81, 0x7f, 0xa5, 0xd4, 0x05, 0x26, 0x71, 0x60, 0xcb, 0xc3, 0x59, 0x7c, 0x1a,
-	0xf5, 0xb7, 0xa0, 0x35, 0xd1, 0x73, 0x34, 0xb4, 0x1c, 0x78, 0x3c, 0x82, 0x5d, 0x65, 0x17, 0xeb,
-	0xab, 0x4d, 0xa3, 0xfb, 0xba, 0x5f, 0x3f, 0xe2, 0xee, 0xdd, 0x50, 0xc8, 0x8a, 0x4f, 0xbc, 0x3e,
-	0x0d, 0xfa, 0x25, 0x51, 0xf6, 0xf0, 0x3f, 0xfc, 0xf8, 0xc9, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x69, 0x94, 0x9f, 0xa7, 0x9b, 0x01, 0x00, 0x00,
+	// 406 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x51, 0x41, 0x6b, 0x14, 0x31,
+	0x14, 0x26, 0xb3, 0x75, 0xbb, 0x7d, 0xb6, 0x50, 0x83, 0x85, 0x71, 0xb1, 0xb0, 0xec, 0x41, 0x16,
+	0x85, 0x4c, 0x5d, 0x11, 0xc4, 0x9e, 0xba, 0x4b, 0xa1, 0x07, 0x85, 0x12, 0xc5, 0x83, 0x97, 0x25,
+	0x66, 0x9f, 0x76, 0x70, 0x26, 0x09, 0x49, 0x66, 0xea, 0xdc, 0xbd, 0xf8, 0x57, 0xfc, 0x95, 0x92,
+	0x4c, 0xa6, 0x56, 0xa9, 0xe0, 0x69, 0xe6, 0xbd, 0xef, 0x7b, 0x5f, 0xbe, 0xf7, 0x3d, 0x78, 0xd2,
+	0x2e, 0xad, 0xe8, 0x98, 0xd4, 0x75, 0x21, 0xb5, 0xc5, 0xc2, 0x58, 0xfd, 0xad, 0x2b, 0xae, 0xbc,
+	0x37, 0x85, 0xd4, 0xea, 0x73, 0xf9, 0x85, 0x19, 0xab, 0xbd, 0xa6, 0x47, 0x03, 0xcf, 0x22, 0x8b,
+	0x1c, 0x16, 0x38, 0xd3, 0x93, 0xbf, 0xc6, 0xa5, 0xae, 0x6b, 0xad, 0x8a, 0x38, 0x23, 0x75, 0x55,
+	0x38, 0xb4, 0x2d, 0xda, 0x8d, 0x33, 0x28, 0x7b, 0xa1, 0xf9, 0x19, 0xec, 0x9e, 0x49, 0xa9, 0x1b,
+	0xe5, 0xe9, 0x14, 0x26, 0x8d, 0x43, 0xab, 0x44, 0x8d, 0x39, 0x99, 0x91, 0xc5, 0x1e, 0xbf, 0xa9,
+	0x03, 0x66, 0x84, 0x73, 0xd7, 0xda, 0x6e, 0xf3, 0xac, 0xc7, 0x86, 0x7a, 0xfe, 0x3d, 0x83, 0xfd,
+	0x77, 0x51, 0x78, 0x1d, 0x2d, 0xd2, 0xc7, 0xb0, 0xeb, 0xcb, 0x1a, 0x75, 0xe3, 0xa3, 0xce, 0xc1,
+	0x2a, 0xcb, 0x09, 0x1f, 0x5a, 0xf4, 0x2d, 0x4c, 0x44, 0xff, 0xa2, 0xcb, 0xb3, 0xd9, 0x68, 0x71,
+	0x7f, 0xf9, 0x9c, 0xdd, 0xb9, 0x0d, 0xbb, 0x2d, 0xca, 0x92, 0x4b, 0x77, 0xae, 0xbc, 0xed, 0xf8,
+	0x8d, 0x04, 0x7d, 0x06, 0x0f, 0x44, 0x55, 0xe9, 0xeb, 0x8d, 0xb7, 0x42, 0x39, 0x23, 0x2c, 0x2a,
+	0x9f, 0x8f, 0x66, 0x64, 0x31, 0xe1, 0x87, 0x11, 0x78, 0xff, 0xbb, 0x4f, 0x8f, 0x01, 0xc2, 0x4a,
+	0x9b, 0x0a, 0x5b, 0xac, 0xf2, 0x9d, 0x60, 0x8e, 0xef, 0x85, 0xce, 0x9b, 0xd0, 0x98, 0x9e, 0xc2,
+	0xc1, 0x1f, 0xcf, 0xd0, 0x43, 0x18, 0x7d, 0xc5, 0x2e, 0xa5, 0x11, 0x7e, 0xe9, 0x43, 0xb8, 0xd7,
+	0x8a, 0xaa, 0xc1, 0x94, 0x42, 0x5f, 0xbc, 0xce, 0x5e, 0x91, 0xf9, 0x09, 0x8c, 0x2f, 0x50, 0x6c,
+	0xd1, 0xfe, 0xef, 0xd4, 0xfc, 0x07, 0x81, 0xfd, 0x75, 0x55, 0xa2, 0xf2, 0x29, 0xb8, 0x15, 0x8c,
+	0xfb, 0x0b, 0xe5, 0x24, 0x06, 0xf3, 0xf4, 0x76, 0x30, 0xfd, 0x2d, 0xd9, 0x70, 0xcb, 0x94, 0xce,
+	0xb9, 0xda, 0x1a, 0x5d, 0x2a, 0xcf, 0xd3, 0x24, 0x7d, 0x09, 0xe3, 0xab, 0x68, 0x23, 0x85, 0x7b,
+	0xfc, 0x8f, 0x70, 0x7b, 0xaf, 0x3c, 0x91, 0x57, 0xa7, 0xf0, 0x48, 0xea, 0xfa, 0x6e, 0xee, 0x25,
+	0xf9, 0xb8, 0x13, 0xbe, 0x3f, 0xb3, 0xa3, 0x0f, 0x4b, 0x2e, 0x3a, 0xb6, 0x0e, 0xf8, 0x65, 0xc4,
+	0x2f, 0xbc, 0x37, 0x9f, 0xc6, 0xd1, 0xd4, 0x8b, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x86, 0xcb,
+	0x0b, 0x14, 0xbe, 0x02, 0x00, 0x00,
 }
@@ -20,6 +20,7 @@ import (
 	"v2ray.com/core/common/net"
 	http_proto "v2ray.com/core/common/protocol/http"
 	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/internet"
 )
 
@@ -120,6 +121,9 @@ Start:
 	if len(s.config.Accounts) > 0 {
 		user, pass, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization"))
 		if !ok || !s.config.HasAccount(user, pass) {
+			if ok {
+				proxy.ReportAuthFailure(ctx)
+			}
 			return common.Error2(conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: Basic realm=\"proxy\"\r\n\r\n")))
 		}
 	}
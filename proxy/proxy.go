@@ -35,6 +35,48 @@ type Dialer interface {
 	Dial(ctx context.Context, destination net.Destination) (internet.Connection, error)
 }
 
+// Listener is implemented by Dialers that are also able to receive traffic from any host, for full-cone UDP and BIND.
+// Sockets are bound to the local address that connections are dialed from. Listening fails if connections are dialed
+// through other outbound handlers.
+type Listener interface {
+	// Listen listens for TCP connections on a random port.
+	Listen(ctx context.Context) (net.Listener, error)
+
+	// ListenUDP listens for UDP packets on a random port.
+	ListenUDP(ctx context.Context) (*net.UDPConn, error)
+}
+
+// Chain is the interface for Outbounds that send traffic through other outbound handlers.
+type Chain interface {
+	// Hops returns tags of the outbound handlers that the traffic goes through.
+	Hops() []string
+}
+
+// FullConeOutbound is the interface for Outbounds that may handle full-cone UDP links, where each packet has its own destination.
+// Full-cone links are dispatched to other Outbounds as plain UDP links to the first destination.
+type FullConeOutbound interface {
+	// SupportFullCone returns true if the Outbound handles full-cone UDP links as is.
+	SupportFullCone() bool
+}
+
+// Binder is the interface for Outbounds that can wait for a connection from a remote peer, as the BIND command of SOCKS 5.
+type Binder interface {
+	// Bind starts to wait for a connection from the given peer. The given dialer may be used to dial a system outbound connection.
+	Bind(ctx context.Context, peer net.Destination, dialer Dialer) (BindListener, error)
+}
+
+// BindListener is a pending bind started by a Binder.
+type BindListener interface {
+	// Addr returns the address that the peer is expected to connect to. The IP may be unspecified if the listener is on all interfaces.
+	Addr() net.Destination
+
+	// Accept waits for the connection from the peer, and returns the connection and the address of the peer.
+	Accept() (internet.Connection, net.Destination, error)
+
+	// Close stops waiting for the peer. Connections returned by Accept() are not affected.
+	Close() error
+}
+
 // UserManager is the interface for Inbounds and Outbounds that can manage their users.
 type UserManager interface {
 	// AddUser adds a new user.
@@ -17,6 +17,7 @@ import (
 // Client is a inbound handler for Shadowsocks protocol
 type Client struct {
 	serverPicker protocol.ServerPicker
+	obfs         *ObfsConfig
 	v            *core.Instance
 }
 
@@ -31,6 +32,7 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 	}
 	client := &Client{
 		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
+		obfs:         config.Obfs,
 		v:            core.MustFromContext(ctx),
 	}
 	return client, nil
@@ -94,8 +96,9 @@ func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dial
 	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
 
 	if request.Command == protocol.RequestCommandTCP {
+		conn := c.obfs.WrapClient(conn)
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		bodyWriter, err := WriteTCPRequest(request, bufferedWriter)
+		bodyWriter, requestIV, err := WriteTCPRequest(request, bufferedWriter)
 		if err != nil {
 			return newError("failed to write request").Base(err)
 		}
@@ -112,7 +115,7 @@ func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dial
 		responseDone := func() error {
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
-			responseReader, err := ReadTCPResponse(user, conn)
+			responseReader, err := ReadTCPResponse(user, requestIV, conn)
 			if err != nil {
 				return err
 			}
@@ -128,10 +131,12 @@ func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dial
 	}
 
 	if request.Command == protocol.RequestCommandUDP {
+		session := NewClientUDPSession()
 
 		writer := buf.NewSequentialWriter(&UDPWriter{
 			Writer:  conn,
 			Request: request,
+			Session: session,
 		})
 
 		requestDone := func() error {
@@ -147,8 +152,9 @@ func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dial
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
 			reader := &UDPReader{
-				Reader: conn,
-				User:   user,
+				Reader:  conn,
+				User:    user,
+				Session: session,
 			}
 
 			if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
@@ -5,16 +5,22 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha1"
+	"encoding/base64"
 	"io"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
+	"lukechampine.com/blake3"
 
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/crypto"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy/shadowsocks/obfs"
+	"v2ray.com/core/transport/internet"
 )
 
 // MemoryAccount is an account type converted from Account.
@@ -76,6 +82,23 @@ func (a *Account) getCipher() (Cipher, error) {
 		}, nil
 	case CipherType_NONE:
 		return NoneCipher{}, nil
+	case CipherType_BLAKE3_AES_128_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        16,
+			AEADAuthCreator: createAesGcm,
+			SeparateHeader:  true,
+		}, nil
+	case CipherType_BLAKE3_AES_256_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        32,
+			AEADAuthCreator: createAesGcm,
+			SeparateHeader:  true,
+		}, nil
+	case CipherType_BLAKE3_CHACHA20_POLY1305:
+		return &AEAD2022Cipher{
+			KeyBytes:        32,
+			AEADAuthCreator: createChacha20Poly1305,
+		}, nil
 	default:
 		return nil, newError("Unsupported cipher.")
 	}
@@ -87,13 +110,65 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	if err != nil {
 		return nil, newError("failed to get cipher").Base(err)
 	}
+	var key []byte
+	if _, ok := cipher.(*AEAD2022Cipher); ok {
+		key, err = base64.StdEncoding.DecodeString(a.Password)
+		if err != nil {
+			return nil, newError("failed to decode key of Shadowsocks 2022 cipher").Base(err)
+		}
+		if int32(len(key)) != cipher.KeySize() {
+			return nil, newError("invalid key size of Shadowsocks 2022 cipher: ", len(key))
+		}
+	} else {
+		key = passwordToCipherKey([]byte(a.Password), cipher.KeySize())
+	}
 	return &MemoryAccount{
 		Cipher:      cipher,
-		Key:         passwordToCipherKey([]byte(a.Password), cipher.KeySize()),
+		Key:         key,
 		OneTimeAuth: a.Ota,
 	}, nil
 }
 
+// Build creates the replay filter of this config. It returns nil if the config is nil.
+func (c *ReplayFilter) Build() *antireplay.BloomRing {
+	if c == nil {
+		return nil
+	}
+	capacity := int(c.Capacity)
+	if capacity == 0 {
+		capacity = 100000
+	}
+	rate := c.FalsePositiveRate
+	if rate == 0 {
+		rate = 1e-6
+	}
+	return antireplay.NewBloomRing(capacity, rate)
+}
+
+// WrapClient applies the obfuscation to a connection to server. It returns conn as is if obfuscation is disabled.
+func (c *ObfsConfig) WrapClient(conn internet.Connection) internet.Connection {
+	switch c.GetMode() {
+	case ObfsConfig_HTTP:
+		return obfs.NewHTTPClient(conn, c.Host)
+	case ObfsConfig_TLS:
+		return obfs.NewTLSClient(conn, c.Host)
+	default:
+		return conn
+	}
+}
+
+// WrapServer applies the obfuscation to a connection from client. It returns conn as is if obfuscation is disabled.
+func (c *ObfsConfig) WrapServer(conn internet.Connection) internet.Connection {
+	switch c.GetMode() {
+	case ObfsConfig_HTTP:
+		return obfs.NewHTTPServer(conn)
+	case ObfsConfig_TLS:
+		return obfs.NewTLSServer(conn)
+	default:
+		return conn
+	}
+}
+
 // Cipher is an interface for all Shadowsocks ciphers.
 type Cipher interface {
 	KeySize() int32
@@ -178,6 +253,23 @@ func (c *AEADCipher) createAuthenticator(key []byte, iv []byte) *crypto.AEADAuth
 	}
 }
 
+// chunkHeaderSize returns the size of the encrypted length at the beginning of a TCP stream.
+func (c *AEADCipher) chunkHeaderSize() int32 {
+	return 2 + int32(c.AEADAuthCreator(make([]byte, c.KeyBytes)).Overhead())
+}
+
+// matchChunkHeader returns true if the salt and the first encrypted length in b are encrypted with the given key.
+func (c *AEADCipher) matchChunkHeader(key []byte, b []byte) bool {
+	ivLen := c.IVSize()
+	size := c.chunkHeaderSize()
+	if int32(len(b)) < ivLen+size {
+		return false
+	}
+	auth := c.createAuthenticator(key, b[:ivLen])
+	_, err := auth.Open(nil, b[ivLen:ivLen+size])
+	return err == nil
+}
+
 func (c *AEADCipher) NewEncryptionWriter(key []byte, iv []byte, writer io.Writer) (buf.Writer, error) {
 	auth := c.createAuthenticator(key, iv)
 	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
@@ -225,6 +317,144 @@ func (c *AEADCipher) DecodePacket(key []byte, b *buf.Buffer) error {
 	return nil
 }
 
+// AEAD2022Cipher represents the Shadowsocks 2022 ciphers, whose session keys are derived with BLAKE3.
+type AEAD2022Cipher struct {
+	KeyBytes        int32
+	AEADAuthCreator func(key []byte) cipher.AEAD
+	// SeparateHeader is true if UDP packets start with a header encrypted by AES with the key, instead of a random nonce of XChaCha20-Poly1305.
+	SeparateHeader bool
+}
+
+func (*AEAD2022Cipher) IsAEAD() bool {
+	return true
+}
+
+func (c *AEAD2022Cipher) KeySize() int32 {
+	return c.KeyBytes
+}
+
+// IVSize returns the size of salt, which is the same as the key size.
+func (c *AEAD2022Cipher) IVSize() int32 {
+	return c.KeyBytes
+}
+
+func (c *AEAD2022Cipher) createAuthenticator(key []byte, salt []byte) *crypto.AEADAuthenticator {
+	return &crypto.AEADAuthenticator{
+		AEAD:           c.AEADAuthCreator(blake3SessionKey(key, salt)),
+		NonceGenerator: crypto.GenerateInitialAEADNonce(),
+	}
+}
+
+// chunkHeaderSize returns the size of the encrypted fixed-length request header.
+func (c *AEAD2022Cipher) chunkHeaderSize() int32 {
+	return requestHeaderSize2022 + int32(c.AEADAuthCreator(make([]byte, c.KeyBytes)).Overhead())
+}
+
+// matchChunkHeader returns true if the salt and the fixed-length request header in b are encrypted with the given key.
+func (c *AEAD2022Cipher) matchChunkHeader(key []byte, b []byte) bool {
+	ivLen := c.IVSize()
+	size := c.chunkHeaderSize()
+	if int32(len(b)) < ivLen+size {
+		return false
+	}
+	auth := c.createAuthenticator(key, b[:ivLen])
+	_, err := auth.Open(nil, b[ivLen:ivLen+size])
+	return err == nil
+}
+
+func (c *AEAD2022Cipher) NewEncryptionWriter(key []byte, iv []byte, writer io.Writer) (buf.Writer, error) {
+	auth := c.createAuthenticator(key, iv)
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream), nil
+}
+
+func (c *AEAD2022Cipher) NewDecryptionReader(key []byte, iv []byte, reader io.Reader) (buf.Reader, error) {
+	auth := c.createAuthenticator(key, iv)
+	return crypto.NewAuthenticationReader(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, reader, protocol.TransferTypeStream), nil
+}
+
+// packetNonceSize returns the size of the random nonce in front of UDP packets.
+func (c *AEAD2022Cipher) packetNonceSize() int32 {
+	if c.SeparateHeader {
+		return 0
+	}
+	return chacha20poly1305.NonceSizeX
+}
+
+// EncodePacket encrypts a UDP packet. b contains space for the nonce, followed by the 16-byte session ID and packet ID, and then the body.
+func (c *AEAD2022Cipher) EncodePacket(key []byte, b *buf.Buffer) error {
+	nonceSize := c.packetNonceSize()
+	payloadLen := b.Len()
+	if payloadLen < nonceSize+packetHeaderSize2022 {
+		return newError("insufficient data: ", payloadLen)
+	}
+
+	if !c.SeparateHeader {
+		aead, err := chacha20poly1305.NewX(key)
+		common.Must(err)
+		nonce := b.BytesTo(nonceSize)
+		common.Must2(rand.Read(nonce))
+		return b.Reset(func(bb []byte) (int, error) {
+			bbb := aead.Seal(bb[:nonceSize], bb[:nonceSize], bb[nonceSize:payloadLen], nil)
+			return len(bbb), nil
+		})
+	}
+
+	header := b.BytesTo(packetHeaderSize2022)
+	aead := c.AEADAuthCreator(blake3SessionKey(key, header[:8]))
+	if err := b.Reset(func(bb []byte) (int, error) {
+		bbb := aead.Seal(bb[:packetHeaderSize2022], header[4:16], bb[packetHeaderSize2022:payloadLen], nil)
+		return len(bbb), nil
+	}); err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(key)
+	common.Must(err)
+	block.Encrypt(header, header)
+	return nil
+}
+
+// DecodePacket decrypts a UDP packet. After decryption, b starts with the session ID and packet ID.
+func (c *AEAD2022Cipher) DecodePacket(key []byte, b *buf.Buffer) error {
+	nonceSize := c.packetNonceSize()
+	if b.Len() <= nonceSize+packetHeaderSize2022 {
+		return newError("insufficient data: ", b.Len())
+	}
+	payloadLen := b.Len()
+
+	if !c.SeparateHeader {
+		aead, err := chacha20poly1305.NewX(key)
+		common.Must(err)
+		if err := b.Reset(func(bb []byte) (int, error) {
+			bbb, err := aead.Open(bb[:nonceSize], bb[:nonceSize], bb[nonceSize:payloadLen], nil)
+			if err != nil {
+				return 0, err
+			}
+			return len(bbb), nil
+		}); err != nil {
+			return err
+		}
+		b.Advance(nonceSize)
+		return nil
+	}
+
+	header := b.BytesTo(packetHeaderSize2022)
+	block, err := aes.NewCipher(key)
+	common.Must(err)
+	block.Decrypt(header, header)
+	aead := c.AEADAuthCreator(blake3SessionKey(key, header[:8]))
+	return b.Reset(func(bb []byte) (int, error) {
+		bbb, err := aead.Open(bb[:packetHeaderSize2022], header[4:16], bb[packetHeaderSize2022:payloadLen], nil)
+		if err != nil {
+			return 0, err
+		}
+		return len(bbb), nil
+	})
+}
+
 type ChaCha20 struct {
 	IVBytes int32
 }
@@ -310,6 +540,16 @@ func passwordToCipherKey(password []byte, keySize int32) []byte {
 	return key
 }
 
+// blake3SessionKey derives the session key of Shadowsocks 2022 from the key and salt (or session ID for UDP).
+func blake3SessionKey(key []byte, salt []byte) []byte {
+	material := make([]byte, 0, len(key)+len(salt))
+	material = append(material, key...)
+	material = append(material, salt...)
+	subkey := make([]byte, len(key))
+	blake3.DeriveKey(subkey, "shadowsocks 2022 session subkey", material)
+	return subkey
+}
+
 func hkdfSHA1(secret, salt, outkey []byte) {
 	r := hkdf.New(sha1.New, secret, salt, []byte("ss-subkey"))
 	common.Must2(io.ReadFull(r, outkey))
@@ -30,29 +30,39 @@ const (
 	CipherType_AES_256_GCM       CipherType = 6
 	CipherType_CHACHA20_POLY1305 CipherType = 7
 	CipherType_NONE              CipherType = 8
+	// Shadowsocks 2022 ciphers. Password of these ciphers is the base64 encoded key.
+	CipherType_BLAKE3_AES_128_GCM       CipherType = 9
+	CipherType_BLAKE3_AES_256_GCM       CipherType = 10
+	CipherType_BLAKE3_CHACHA20_POLY1305 CipherType = 11
 )
 
 var CipherType_name = map[int32]string{
-	0: "UNKNOWN",
-	1: "AES_128_CFB",
-	2: "AES_256_CFB",
-	3: "CHACHA20",
-	4: "CHACHA20_IETF",
-	5: "AES_128_GCM",
-	6: "AES_256_GCM",
-	7: "CHACHA20_POLY1305",
-	8: "NONE",
+	0:  "UNKNOWN",
+	1:  "AES_128_CFB",
+	2:  "AES_256_CFB",
+	3:  "CHACHA20",
+	4:  "CHACHA20_IETF",
+	5:  "AES_128_GCM",
+	6:  "AES_256_GCM",
+	7:  "CHACHA20_POLY1305",
+	8:  "NONE",
+	9:  "BLAKE3_AES_128_GCM",
+	10: "BLAKE3_AES_256_GCM",
+	11: "BLAKE3_CHACHA20_POLY1305",
 }
 var CipherType_value = map[string]int32{
-	"UNKNOWN":           0,
-	"AES_128_CFB":       1,
-	"AES_256_CFB":       2,
-	"CHACHA20":          3,
-	"CHACHA20_IETF":     4,
-	"AES_128_GCM":       5,
-	"AES_256_GCM":       6,
-	"CHACHA20_POLY1305": 7,
-	"NONE":              8,
+	"UNKNOWN":                  0,
+	"AES_128_CFB":              1,
+	"AES_256_CFB":              2,
+	"CHACHA20":                 3,
+	"CHACHA20_IETF":            4,
+	"AES_128_GCM":              5,
+	"AES_256_GCM":              6,
+	"CHACHA20_POLY1305":        7,
+	"NONE":                     8,
+	"BLAKE3_AES_128_GCM":       9,
+	"BLAKE3_AES_256_GCM":       10,
+	"BLAKE3_CHACHA20_POLY1305": 11,
 }
 
 func (x CipherType) String() string {
@@ -84,6 +94,30 @@ func (x Account_OneTimeAuth) String() string {
 }
 func (Account_OneTimeAuth) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
 
+type ObfsConfig_Mode int32
+
+const (
+	ObfsConfig_None ObfsConfig_Mode = 0
+	ObfsConfig_HTTP ObfsConfig_Mode = 1
+	ObfsConfig_TLS  ObfsConfig_Mode = 2
+)
+
+var ObfsConfig_Mode_name = map[int32]string{
+	0: "None",
+	1: "HTTP",
+	2: "TLS",
+}
+var ObfsConfig_Mode_value = map[string]int32{
+	"None": 0,
+	"HTTP": 1,
+	"TLS":  2,
+}
+
+func (x ObfsConfig_Mode) String() string {
+	return proto.EnumName(ObfsConfig_Mode_name, int32(x))
+}
+func (ObfsConfig_Mode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3, 0} }
+
 type Account struct {
 	Password   string              `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
 	CipherType CipherType          `protobuf:"varint,2,opt,name=cipher_type,json=cipherType,enum=v2ray.core.proxy.shadowsocks.CipherType" json:"cipher_type,omitempty"`
@@ -122,6 +156,17 @@ type ServerConfig struct {
 	UdpEnabled bool                             `protobuf:"varint,1,opt,name=udp_enabled,json=udpEnabled" json:"udp_enabled,omitempty"`
 	User       *v2ray_core_common_protocol.User `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
 	Network    []v2ray_core_common_net.Network  `protobuf:"varint,3,rep,packed,name=network,enum=v2ray.core.common.net.Network" json:"network,omitempty"`
+	// Additional users on the same port. All users must use AEAD ciphers when
+	// there are more than one.
+	Users []*v2ray_core_common_protocol.User `protobuf:"bytes,4,rep,name=users" json:"users,omitempty"`
+	// Filter of replayed salts of AEAD ciphers. Replayed requests are rejected as if the key were invalid.
+	// The filter is disabled if not set.
+	ReplayFilter *ReplayFilter `protobuf:"bytes,5,opt,name=replay_filter,json=replayFilter" json:"replay_filter,omitempty"`
+	// Obfuscation of TCP connections from clients.
+	Obfs *ObfsConfig `protobuf:"bytes,6,opt,name=obfs" json:"obfs,omitempty"`
+	// Sends UDP packets of each client through a single full-cone link, so that responses from any source are passed
+	// back. Outbounds without full-cone support only send packets to the first destination.
+	UdpFullCone bool `protobuf:"varint,7,opt,name=udp_full_cone,json=udpFullCone" json:"udp_full_cone,omitempty"`
 }
 
 func (m *ServerConfig) Reset()                    { *m = ServerConfig{} }
@@ -150,14 +195,98 @@ func (m *ServerConfig) GetNetwork() []v2ray_core_common_net.Network {
 	return nil
 }
 
+func (m *ServerConfig) GetUsers() []*v2ray_core_common_protocol.User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetReplayFilter() *ReplayFilter {
+	if m != nil {
+		return m.ReplayFilter
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetObfs() *ObfsConfig {
+	if m != nil {
+		return m.Obfs
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetUdpFullCone() bool {
+	if m != nil {
+		return m.UdpFullCone
+	}
+	return false
+}
+
+// ReplayFilter remembers the salts of recent requests in two rotating Bloom filters.
+type ReplayFilter struct {
+	// Number of salts in each Bloom filter. Salts are remembered until at least this number of newer requests arrive.
+	// Default to 100000.
+	Capacity uint32 `protobuf:"varint,1,opt,name=capacity" json:"capacity,omitempty"`
+	// False positive rate of each Bloom filter. Default to 1e-6.
+	FalsePositiveRate float64 `protobuf:"fixed64,2,opt,name=false_positive_rate,json=falsePositiveRate" json:"false_positive_rate,omitempty"`
+}
+
+func (m *ReplayFilter) Reset()                    { *m = ReplayFilter{} }
+func (m *ReplayFilter) String() string            { return proto.CompactTextString(m) }
+func (*ReplayFilter) ProtoMessage()               {}
+func (*ReplayFilter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ReplayFilter) GetCapacity() uint32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ReplayFilter) GetFalsePositiveRate() float64 {
+	if m != nil {
+		return m.FalsePositiveRate
+	}
+	return 0
+}
+
+// ObfsConfig is the simple-obfs obfuscation of TCP connections.
+type ObfsConfig struct {
+	Mode ObfsConfig_Mode `protobuf:"varint,1,opt,name=mode,enum=v2ray.core.proxy.shadowsocks.ObfsConfig_Mode" json:"mode,omitempty"`
+	// Host in HTTP Host header, or server name in TLS client hello. Only used by client.
+	Host string `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *ObfsConfig) Reset()                    { *m = ObfsConfig{} }
+func (m *ObfsConfig) String() string            { return proto.CompactTextString(m) }
+func (*ObfsConfig) ProtoMessage()               {}
+func (*ObfsConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ObfsConfig) GetMode() ObfsConfig_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return ObfsConfig_None
+}
+
+func (m *ObfsConfig) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
 type ClientConfig struct {
 	Server []*v2ray_core_common_protocol1.ServerEndpoint `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
+	// Obfuscation of TCP connections to servers.
+	Obfs *ObfsConfig `protobuf:"bytes,2,opt,name=obfs" json:"obfs,omitempty"`
 }
 
 func (m *ClientConfig) Reset()                    { *m = ClientConfig{} }
 func (m *ClientConfig) String() string            { return proto.CompactTextString(m) }
 func (*ClientConfig) ProtoMessage()               {}
-func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *ClientConfig) GetServer() []*v2ray_core_common_protocol1.ServerEndpoint {
 	if m != nil {
@@ -166,49 +295,74 @@ func (m *ClientConfig) GetServer() []*v2ray_core_common_protocol1.ServerEndpoint
 	return nil
 }
 
+func (m *ClientConfig) GetObfs() *ObfsConfig {
+	if m != nil {
+		return m.Obfs
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.shadowsocks.Account")
 	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.shadowsocks.ServerConfig")
+	proto.RegisterType((*ReplayFilter)(nil), "v2ray.core.proxy.shadowsocks.ReplayFilter")
+	proto.RegisterType((*ObfsConfig)(nil), "v2ray.core.proxy.shadowsocks.ObfsConfig")
 	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.shadowsocks.ClientConfig")
 	proto.RegisterEnum("v2ray.core.proxy.shadowsocks.CipherType", CipherType_name, CipherType_value)
 	proto.RegisterEnum("v2ray.core.proxy.shadowsocks.Account_OneTimeAuth", Account_OneTimeAuth_name, Account_OneTimeAuth_value)
+	proto.RegisterEnum("v2ray.core.proxy.shadowsocks.ObfsConfig_Mode", ObfsConfig_Mode_name, ObfsConfig_Mode_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/proxy/shadowsocks/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 522 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x6e, 0xd3, 0x4e,
-	0x10, 0xc6, 0xbb, 0x71, 0xff, 0x4d, 0xfe, 0xe3, 0x50, 0xdc, 0x95, 0x90, 0xac, 0xa8, 0x42, 0x56,
-	0x38, 0x10, 0x2a, 0xb1, 0x4e, 0x5c, 0x8a, 0x7a, 0x75, 0x4c, 0x4a, 0x2b, 0xc0, 0x89, 0x9c, 0x14,
-	0x04, 0x17, 0xcb, 0x5d, 0x2f, 0xc4, 0x6a, 0xe2, 0xb5, 0xd6, 0x76, 0x43, 0x9e, 0x86, 0x03, 0x37,
-	0xde, 0x8c, 0xb7, 0x40, 0x5e, 0x3b, 0xa9, 0x85, 0xaa, 0x70, 0x88, 0x94, 0x99, 0xfd, 0x7d, 0x9f,
-	0x66, 0xbe, 0x31, 0xbc, 0xbc, 0xb3, 0x44, 0xb0, 0x26, 0x94, 0x2f, 0x4d, 0xca, 0x05, 0x33, 0x13,
-	0xc1, 0xbf, 0xaf, 0xcd, 0x74, 0x1e, 0x84, 0x7c, 0x95, 0x72, 0x7a, 0x9b, 0x9a, 0x94, 0xc7, 0x5f,
-	0xa3, 0x6f, 0x24, 0x11, 0x3c, 0xe3, 0xf8, 0x78, 0x83, 0x0b, 0x46, 0x24, 0x4a, 0x6a, 0x68, 0xe7,
-	0xf9, 0x5f, 0x66, 0x94, 0x2f, 0x97, 0x3c, 0x36, 0x63, 0x96, 0x15, 0xbf, 0x15, 0x17, 0xb7, 0xa5,
-	0x4d, 0xe7, 0xc5, 0xc3, 0xa0, 0x7c, 0xa4, 0x7c, 0x61, 0xe6, 0x29, 0x13, 0x15, 0xda, 0xff, 0x07,
-	0x9a, 0x32, 0x71, 0xc7, 0x84, 0x9f, 0x26, 0x8c, 0x96, 0x8a, 0xee, 0x6f, 0x04, 0x4d, 0x9b, 0x52,
-	0x9e, 0xc7, 0x19, 0xee, 0x40, 0x2b, 0x09, 0xd2, 0x74, 0xc5, 0x45, 0xa8, 0x23, 0x03, 0xf5, 0xfe,
-	0xf7, 0xb6, 0x35, 0xbe, 0x02, 0x95, 0x46, 0xc9, 0x9c, 0x09, 0x3f, 0x5b, 0x27, 0x4c, 0x6f, 0x18,
-	0xa8, 0x77, 0x68, 0xf5, 0xc8, 0xae, 0x0d, 0x89, 0x23, 0x05, 0xb3, 0x75, 0xc2, 0x3c, 0xa0, 0xdb,
-	0xff, 0xd8, 0x01, 0x85, 0x67, 0x81, 0xae, 0x48, 0x8b, 0xc1, 0x6e, 0x8b, 0x6a, 0x34, 0x32, 0x8e,
-	0xd9, 0x2c, 0x5a, 0x32, 0x3b, 0xcf, 0xe6, 0x5e, 0xa1, 0xee, 0x5a, 0xa0, 0xd6, 0x7a, 0xb8, 0x05,
-	0xfb, 0x76, 0x9e, 0x71, 0x6d, 0x0f, 0xb7, 0xa1, 0xf5, 0x26, 0x4a, 0x83, 0x9b, 0x05, 0x0b, 0x35,
-	0x84, 0x55, 0x68, 0x8e, 0xe2, 0xb2, 0x68, 0x74, 0x7f, 0x22, 0x68, 0x4f, 0x65, 0x02, 0x8e, 0x3c,
-	0x13, 0x7e, 0x06, 0x6a, 0x1e, 0x26, 0x3e, 0x2b, 0x09, 0xb9, 0x73, 0x6b, 0xd8, 0xd0, 0x91, 0x07,
-	0x79, 0x98, 0x54, 0x3a, 0xfc, 0x0a, 0xf6, 0x8b, 0x84, 0xe5, 0xca, 0xaa, 0x65, 0xd4, 0xe7, 0x2d,
-	0xe3, 0x25, 0x9b, 0x78, 0xc9, 0x75, 0xca, 0x84, 0x27, 0x69, 0x7c, 0x0e, 0xcd, 0xea, 0x8a, 0xba,
-	0x62, 0x28, 0xbd, 0x43, 0xeb, 0xe9, 0x03, 0xc2, 0x98, 0x65, 0xc4, 0x2d, 0x29, 0x6f, 0x83, 0x77,
-	0x3d, 0x68, 0x3b, 0x8b, 0x88, 0xc5, 0x59, 0x35, 0xe4, 0x10, 0x0e, 0xca, 0xb3, 0xe9, 0xc8, 0x50,
-	0x7a, 0xaa, 0x75, 0xb2, 0x6b, 0x82, 0x72, 0xbd, 0x51, 0x1c, 0x26, 0x3c, 0x8a, 0x33, 0xaf, 0x52,
-	0x9e, 0xfc, 0x40, 0x00, 0xf7, 0xd7, 0x28, 0x52, 0xb9, 0x76, 0xdf, 0xb9, 0xe3, 0x4f, 0xae, 0xb6,
-	0x87, 0x1f, 0x83, 0x6a, 0x8f, 0xa6, 0xfe, 0xc0, 0x3a, 0xf7, 0x9d, 0x8b, 0xa1, 0x86, 0x36, 0x0d,
-	0xeb, 0xec, 0xb5, 0x6c, 0x34, 0x8a, 0x48, 0x9d, 0x4b, 0xdb, 0xb9, 0xb4, 0xad, 0xbe, 0xa6, 0xe0,
-	0x23, 0x78, 0xb4, 0xa9, 0xfc, 0xab, 0xd1, 0xec, 0x42, 0xdb, 0xaf, 0x5b, 0xbc, 0x75, 0x3e, 0x68,
-	0xff, 0xd5, 0x2d, 0x8a, 0xc6, 0x01, 0x7e, 0x02, 0x47, 0x5b, 0xd1, 0x64, 0xfc, 0xfe, 0xf3, 0xe0,
-	0xb4, 0x7f, 0xa6, 0x35, 0x8b, 0xb3, 0xb9, 0x63, 0x77, 0xa4, 0xb5, 0x86, 0x13, 0x30, 0x28, 0x5f,
-	0xee, 0xfc, 0x18, 0x26, 0xe8, 0x8b, 0x5a, 0x2b, 0x7f, 0x35, 0x8e, 0x3f, 0x5a, 0x5e, 0xb0, 0x26,
-	0x4e, 0x41, 0x4f, 0x24, 0x3d, 0xbd, 0x7f, 0xbe, 0x39, 0x90, 0xa1, 0x9c, 0xfe, 0x09, 0x00, 0x00,
-	0xff, 0xff, 0xdc, 0x7e, 0x6b, 0x61, 0xb5, 0x03, 0x00, 0x00,
+	// 756 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xd1, 0x6e, 0xdb, 0x36,
+	0x14, 0x8d, 0x2c, 0x25, 0x76, 0xae, 0xec, 0x4c, 0xe1, 0xb0, 0x41, 0x08, 0x82, 0xc1, 0x50, 0x1f,
+	0xe6, 0x15, 0xa8, 0x9c, 0x28, 0x6b, 0xd1, 0x87, 0xbd, 0xc8, 0x9a, 0xbd, 0x14, 0x4d, 0x6d, 0x83,
+	0x76, 0x37, 0xac, 0x2f, 0x82, 0x2c, 0xd1, 0xb3, 0x50, 0x59, 0x14, 0x48, 0x2a, 0x99, 0xff, 0x60,
+	0x4f, 0xc3, 0xbe, 0x63, 0x7f, 0xb6, 0x87, 0xfd, 0xc3, 0x20, 0x4a, 0x76, 0x04, 0xaf, 0x70, 0x8b,
+	0x3e, 0x08, 0xd0, 0xbd, 0x3c, 0xe7, 0xf0, 0xf2, 0x1c, 0x12, 0x9e, 0xdd, 0x3b, 0x2c, 0xd8, 0xd8,
+	0x21, 0x5d, 0xf7, 0x43, 0xca, 0x48, 0x3f, 0x63, 0xf4, 0xf7, 0x4d, 0x9f, 0xaf, 0x82, 0x88, 0x3e,
+	0x70, 0x1a, 0xbe, 0xe7, 0xfd, 0x90, 0xa6, 0xcb, 0xf8, 0x37, 0x3b, 0x63, 0x54, 0x50, 0x74, 0xb9,
+	0x85, 0x33, 0x62, 0x4b, 0xa8, 0x5d, 0x83, 0x5e, 0x7c, 0xbb, 0x27, 0x16, 0xd2, 0xf5, 0x9a, 0xa6,
+	0xfd, 0x94, 0x88, 0xe2, 0x7b, 0xa0, 0xec, 0x7d, 0x29, 0x73, 0xf1, 0xdd, 0x87, 0x81, 0x72, 0x31,
+	0xa4, 0x49, 0x3f, 0xe7, 0x84, 0x55, 0xd0, 0xab, 0x8f, 0x40, 0x39, 0x61, 0xf7, 0x84, 0xf9, 0x3c,
+	0x23, 0x61, 0xc9, 0xb0, 0xfe, 0x51, 0xa0, 0xe9, 0x86, 0x21, 0xcd, 0x53, 0x81, 0x2e, 0xa0, 0x95,
+	0x05, 0x9c, 0x3f, 0x50, 0x16, 0x99, 0x4a, 0x57, 0xe9, 0x9d, 0xe2, 0x5d, 0x8d, 0x5e, 0x81, 0x1e,
+	0xc6, 0xd9, 0x8a, 0x30, 0x5f, 0x6c, 0x32, 0x62, 0x36, 0xba, 0x4a, 0xef, 0xcc, 0xe9, 0xd9, 0x87,
+	0x4e, 0x68, 0x7b, 0x92, 0x30, 0xdf, 0x64, 0x04, 0x43, 0xb8, 0xfb, 0x47, 0x1e, 0xa8, 0x54, 0x04,
+	0xa6, 0x2a, 0x25, 0xae, 0x0f, 0x4b, 0x54, 0xa3, 0xd9, 0x93, 0x94, 0xcc, 0xe3, 0x35, 0x71, 0x73,
+	0xb1, 0xc2, 0x05, 0xdb, 0x72, 0x40, 0xaf, 0xf5, 0x50, 0x0b, 0x34, 0x37, 0x17, 0xd4, 0x38, 0x42,
+	0x6d, 0x68, 0xfd, 0x18, 0xf3, 0x60, 0x91, 0x90, 0xc8, 0x50, 0x90, 0x0e, 0xcd, 0x61, 0x5a, 0x16,
+	0x0d, 0xeb, 0x4f, 0x15, 0xda, 0x33, 0xe9, 0x80, 0x27, 0x63, 0x42, 0x4f, 0x40, 0xcf, 0xa3, 0xcc,
+	0x27, 0x25, 0x42, 0x9e, 0xb9, 0x35, 0x68, 0x98, 0x0a, 0x86, 0x3c, 0xca, 0x2a, 0x1e, 0xfa, 0x1e,
+	0xb4, 0xc2, 0x61, 0x79, 0x64, 0xdd, 0xe9, 0xd6, 0xe7, 0x2d, 0xed, 0xb5, 0xb7, 0xf6, 0xda, 0x6f,
+	0x39, 0x61, 0x58, 0xa2, 0xd1, 0x4b, 0x68, 0x56, 0x29, 0x9a, 0x6a, 0x57, 0xed, 0x9d, 0x39, 0xdf,
+	0x7c, 0x80, 0x98, 0x12, 0x61, 0x8f, 0x4b, 0x14, 0xde, 0xc2, 0xd1, 0x0b, 0x38, 0x2e, 0x14, 0xb8,
+	0xa9, 0x75, 0xd5, 0x4f, 0xda, 0xb0, 0x84, 0xa3, 0x09, 0x74, 0x18, 0xc9, 0x92, 0x60, 0xe3, 0x2f,
+	0xe3, 0x44, 0x10, 0x66, 0x1e, 0xcb, 0x81, 0x9f, 0x1e, 0x36, 0x18, 0x4b, 0xca, 0x48, 0x32, 0x70,
+	0x9b, 0xd5, 0x2a, 0xf4, 0x03, 0x68, 0x74, 0xb1, 0xe4, 0xe6, 0x89, 0xd4, 0xf9, 0x48, 0xd6, 0x93,
+	0xc5, 0x92, 0x97, 0xae, 0x62, 0xc9, 0x42, 0x16, 0x74, 0x0a, 0x6f, 0x97, 0x79, 0x92, 0xf8, 0x21,
+	0x4d, 0x89, 0xd9, 0x2c, 0xdc, 0xc5, 0x85, 0xe1, 0xa3, 0x3c, 0x49, 0x3c, 0x9a, 0x12, 0xeb, 0x1d,
+	0xb4, 0xeb, 0xfb, 0x17, 0x17, 0x30, 0x0c, 0xb2, 0x20, 0x8c, 0xc5, 0x46, 0x86, 0xd1, 0xc1, 0xbb,
+	0x1a, 0xd9, 0xf0, 0xe5, 0x32, 0x48, 0x38, 0xf1, 0x33, 0xca, 0x63, 0x11, 0xdf, 0x13, 0x9f, 0x05,
+	0xa2, 0xbc, 0x88, 0x0a, 0x3e, 0x97, 0x4b, 0xd3, 0x6a, 0x05, 0x07, 0x82, 0x58, 0x7f, 0x28, 0x00,
+	0x8f, 0x43, 0x21, 0x17, 0xb4, 0x35, 0x8d, 0x88, 0x94, 0x3d, 0x73, 0x9e, 0x7d, 0xea, 0x61, 0xec,
+	0x37, 0x34, 0x22, 0x58, 0x52, 0x11, 0x02, 0x6d, 0x45, 0xb9, 0x90, 0x5b, 0x9e, 0x62, 0xf9, 0x6f,
+	0x3d, 0x01, 0xad, 0x40, 0x14, 0xf7, 0x6f, 0x4c, 0x53, 0x62, 0x1c, 0x15, 0x7f, 0xb7, 0xf3, 0xf9,
+	0xd4, 0x50, 0x50, 0x13, 0xd4, 0xf9, 0xdd, 0xcc, 0x68, 0x58, 0x7f, 0x29, 0xd0, 0xf6, 0x92, 0x98,
+	0xa4, 0xa2, 0x1a, 0x66, 0x00, 0x27, 0xe5, 0x4b, 0x34, 0x95, 0xae, 0xba, 0x9f, 0xd1, 0x7e, 0xc6,
+	0xe5, 0x8d, 0x1d, 0xa6, 0x51, 0x46, 0xe3, 0x54, 0xe0, 0x8a, 0xb9, 0x4b, 0xa7, 0xf1, 0x39, 0xe9,
+	0x3c, 0xfd, 0x57, 0x01, 0x78, 0x7c, 0x9e, 0xc5, 0x33, 0x79, 0x3b, 0x7e, 0x3d, 0x9e, 0xfc, 0x32,
+	0x36, 0x8e, 0xd0, 0x17, 0xa0, 0xbb, 0xc3, 0x99, 0x7f, 0xed, 0xbc, 0xf4, 0xbd, 0xd1, 0xc0, 0x50,
+	0xb6, 0x0d, 0xe7, 0xf9, 0x0b, 0xd9, 0x68, 0x14, 0x6f, 0xcc, 0xbb, 0x75, 0xbd, 0x5b, 0xd7, 0xb9,
+	0x32, 0x54, 0x74, 0x0e, 0x9d, 0x6d, 0xe5, 0xbf, 0x1a, 0xce, 0x47, 0x86, 0x56, 0x97, 0xf8, 0xc9,
+	0x7b, 0x63, 0x1c, 0xd7, 0x25, 0x8a, 0xc6, 0x09, 0xfa, 0x0a, 0xce, 0x77, 0xa4, 0xe9, 0xe4, 0xee,
+	0xd7, 0xeb, 0x9b, 0xab, 0xe7, 0x46, 0x53, 0xfa, 0x38, 0x19, 0x0f, 0x8d, 0x16, 0xfa, 0x1a, 0xd0,
+	0xe0, 0xce, 0x7d, 0x3d, 0xbc, 0xf1, 0xeb, 0x4a, 0xa7, 0x7b, 0xfd, 0xad, 0x20, 0xa0, 0x4b, 0x30,
+	0xab, 0xfe, 0xff, 0x75, 0xf5, 0xc1, 0x14, 0xba, 0x21, 0x5d, 0x1f, 0x34, 0x69, 0xaa, 0xbc, 0xd3,
+	0x6b, 0xe5, 0xdf, 0x8d, 0xcb, 0x9f, 0x1d, 0x1c, 0x6c, 0x6c, 0xaf, 0x40, 0x4f, 0x25, 0x7a, 0xf6,
+	0xb8, 0xbc, 0x38, 0x91, 0x01, 0xdd, 0xfc, 0x17, 0x00, 0x00, 0xff, 0xff, 0xd9, 0xc9, 0xa7, 0x1f,
+	0x14, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,133 @@
+package obfs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+)
+
+// httpClientConn sends the first payload in the body of a WebSocket upgrade request, and strips the upgrade
+// response from the server. All later data is sent as is.
+type httpClientConn struct {
+	net.Conn
+	host string
+
+	requestSent  bool
+	reader       *bufio.Reader
+	responseRead bool
+}
+
+// NewHTTPClient wraps a connection to a simple-obfs HTTP server. host is sent in the Host header.
+func NewHTTPClient(conn net.Conn, host string) net.Conn {
+	return &httpClientConn{
+		Conn: conn,
+		host: host,
+	}
+}
+
+func (c *httpClientConn) Write(b []byte) (int, error) {
+	if c.requestSent {
+		return c.Conn.Write(b)
+	}
+	c.requestSent = true
+
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+
+	var header bytes.Buffer
+	header.WriteString("GET / HTTP/1.1\r\n")
+	header.WriteString("Host: " + c.host + "\r\n")
+	header.WriteString("User-Agent: curl/7." + serial.IntToString(dice.Roll(51)) + "." + serial.IntToString(dice.Roll(2)) + "\r\n")
+	header.WriteString("Upgrade: websocket\r\n")
+	header.WriteString("Connection: Upgrade\r\n")
+	header.WriteString("Sec-WebSocket-Key: " + base64.StdEncoding.EncodeToString(key) + "\r\n")
+	header.WriteString("Content-Length: " + serial.IntToString(len(b)) + "\r\n")
+	header.WriteString("\r\n")
+	header.Write(b)
+
+	if _, err := c.Conn.Write(header.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *httpClientConn) Read(b []byte) (int, error) {
+	if !c.responseRead {
+		c.reader = bufio.NewReader(c.Conn)
+		resp, err := http.ReadResponse(c.reader, nil)
+		if err != nil {
+			return 0, newError("failed to read HTTP response").Base(err)
+		}
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			return 0, newError("unexpected HTTP status: ", resp.Status)
+		}
+		c.responseRead = true
+	}
+	return c.reader.Read(b)
+}
+
+// httpServerConn strips the WebSocket upgrade request from the client, and sends the upgrade response along with
+// the first payload. All later data is sent as is.
+type httpServerConn struct {
+	net.Conn
+
+	reader       *bufio.Reader
+	requestRead  bool
+	responseSent bool
+}
+
+// NewHTTPServer wraps a connection from a simple-obfs HTTP client.
+func NewHTTPServer(conn net.Conn) net.Conn {
+	return &httpServerConn{
+		Conn: conn,
+	}
+}
+
+func (c *httpServerConn) Read(b []byte) (int, error) {
+	if !c.requestRead {
+		c.reader = bufio.NewReader(c.Conn)
+		req, err := http.ReadRequest(c.reader)
+		if err != nil {
+			return 0, newError("failed to read HTTP request").Base(err)
+		}
+		if req.Method != "GET" || !strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			return 0, newError("not a WebSocket upgrade request: ", req.Method, " ", req.URL)
+		}
+		c.requestRead = true
+	}
+	return c.reader.Read(b)
+}
+
+func (c *httpServerConn) Write(b []byte) (int, error) {
+	if c.responseSent {
+		return c.Conn.Write(b)
+	}
+	c.responseSent = true
+
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+
+	var header bytes.Buffer
+	header.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	header.WriteString("Server: nginx/1." + serial.IntToString(dice.Roll(11)) + "." + serial.IntToString(dice.Roll(12)) + "\r\n")
+	header.WriteString("Date: " + time.Now().UTC().Format(http.TimeFormat) + "\r\n")
+	header.WriteString("Upgrade: websocket\r\n")
+	header.WriteString("Connection: Upgrade\r\n")
+	header.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(key) + "\r\n")
+	header.WriteString("\r\n")
+	header.Write(b)
+
+	if _, err := c.Conn.Write(header.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,5 @@
+// Package obfs implements the HTTP and TLS obfuscation of simple-obfs, which disguises Shadowsocks streams as
+// WebSocket upgrades or TLS sessions.
+package obfs
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg obfs -path Proxy,Shadowsocks,Obfs
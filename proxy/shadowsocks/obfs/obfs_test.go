@@ -0,0 +1,94 @@
+package obfs_test
+
+import (
+	"crypto/rand"
+	"io"
+	"net"
+	"testing"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/proxy/shadowsocks/obfs"
+	. "v2ray.com/ext/assert"
+)
+
+// runEcho starts a server that echoes everything back through the server side obfuscation, and returns a client
+// connection wrapped by the client side obfuscation.
+func runEcho(t *testing.T, wrapServer func(net.Conn) net.Conn, wrapClient func(net.Conn) net.Conn) net.Conn {
+	assert := With(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert(err, IsNil)
+
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		serverConn := wrapServer(conn)
+		b := make([]byte, 32*1024)
+		for {
+			n, err := serverConn.Read(b)
+			if err != nil {
+				return
+			}
+			if _, err := serverConn.Write(b[:n]); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert(err, IsNil)
+	return wrapClient(conn)
+}
+
+func testEcho(t *testing.T, conn net.Conn) {
+	assert := With(t)
+	defer conn.Close()
+
+	for _, size := range []int{100, 1, 40000} {
+		payload := make([]byte, size)
+		common.Must2(rand.Read(payload))
+
+		nBytes, err := conn.Write(payload)
+		assert(err, IsNil)
+		assert(nBytes, Equals, size)
+
+		response := make([]byte, size)
+		_, err = io.ReadFull(conn, response)
+		assert(err, IsNil)
+		assert(response, Equals, payload)
+	}
+}
+
+func TestHTTPObfs(t *testing.T) {
+	conn := runEcho(t, NewHTTPServer, func(conn net.Conn) net.Conn {
+		return NewHTTPClient(conn, "www.bing.com")
+	})
+	testEcho(t, conn)
+}
+
+func TestTLSObfs(t *testing.T) {
+	conn := runEcho(t, NewTLSServer, func(conn net.Conn) net.Conn {
+		return NewTLSClient(conn, "www.bing.com")
+	})
+	testEcho(t, conn)
+}
+
+func TestTLSObfsRejectsPlainData(t *testing.T) {
+	assert := With(t)
+
+	conn := runEcho(t, NewTLSServer, func(conn net.Conn) net.Conn {
+		return conn
+	})
+	defer conn.Close()
+
+	_, err := conn.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	assert(err, IsNil)
+
+	_, err = conn.Read(make([]byte, 16))
+	assert(err, IsNotNil)
+}
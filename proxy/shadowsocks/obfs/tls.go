@@ -0,0 +1,345 @@
+package obfs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+)
+
+const (
+	recordTypeChangeCipherSpec = 0x14
+	recordTypeHandshake        = 0x16
+	recordTypeApplicationData  = 0x17
+
+	recordHeaderSize = 5
+	maxRecordSize    = 16 * 1024
+
+	extensionServerName    = 0x0000
+	extensionSessionTicket = 0x0023
+)
+
+var (
+	cipherSuites = []byte{
+		0xc0, 0x2c, 0xc0, 0x30, 0x00, 0x9f, 0xcc, 0xa9, 0xcc, 0xa8, 0xcc, 0xaa, 0xc0, 0x2b, 0xc0, 0x2f,
+		0x00, 0x9e, 0xc0, 0x24, 0xc0, 0x28, 0x00, 0x6b, 0xc0, 0x23, 0xc0, 0x27, 0x00, 0x67, 0xc0, 0x0a,
+		0xc0, 0x14, 0x00, 0x39, 0xc0, 0x09, 0xc0, 0x13, 0x00, 0x33, 0x00, 0x9d, 0x00, 0x9c, 0x00, 0x3d,
+		0x00, 0x3c, 0x00, 0x35, 0x00, 0x2f, 0x00, 0xff,
+	}
+
+	// Extensions after session ticket and server name in client hello: ec_point_formats, elliptic_curves,
+	// signature_algorithms, encrypt_then_mac and extended_master_secret.
+	clientHelloExtensions = []byte{
+		0x00, 0x0b, 0x00, 0x04, 0x03, 0x01, 0x00, 0x02,
+		0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x19, 0x00, 0x18,
+		0x00, 0x0d, 0x00, 0x20, 0x00, 0x1e,
+		0x06, 0x01, 0x06, 0x02, 0x06, 0x03, 0x05, 0x01, 0x05, 0x02, 0x05, 0x03, 0x04, 0x01, 0x04, 0x02,
+		0x04, 0x03, 0x03, 0x01, 0x03, 0x02, 0x03, 0x03, 0x02, 0x01, 0x02, 0x02, 0x02, 0x03,
+		0x00, 0x16, 0x00, 0x00,
+		0x00, 0x17, 0x00, 0x00,
+	}
+
+	// Extensions in server hello: renegotiation_info, extended_master_secret and ec_point_formats.
+	serverHelloExtensions = []byte{
+		0xff, 0x01, 0x00, 0x01, 0x00,
+		0x00, 0x17, 0x00, 0x00,
+		0x00, 0x0b, 0x00, 0x02, 0x01, 0x00,
+	}
+
+	changeCipherSpec = []byte{recordTypeChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01}
+)
+
+func putUint16(b *bytes.Buffer, v int) {
+	b.WriteByte(byte(v >> 8))
+	b.WriteByte(byte(v))
+}
+
+// writeRandom writes the random of hello messages: current time followed by 28 random bytes.
+func writeRandom(b *bytes.Buffer) {
+	var random [32]byte
+	binary.BigEndian.PutUint32(random[:], uint32(time.Now().Unix()))
+	common.Must2(rand.Read(random[4:]))
+	b.Write(random[:])
+}
+
+// writeFinished writes a handshake record of 32 random bytes, which looks like an encrypted Finished message.
+func writeFinished(b *bytes.Buffer) {
+	b.Write([]byte{recordTypeHandshake, 0x03, 0x03, 0x00, 0x20})
+	var finished [32]byte
+	common.Must2(rand.Read(finished[:]))
+	b.Write(finished[:])
+}
+
+// writeApplicationData writes payload in application data records.
+func writeApplicationData(b *bytes.Buffer, payload []byte) {
+	for len(payload) > 0 {
+		n := len(payload)
+		if n > maxRecordSize {
+			n = maxRecordSize
+		}
+		b.Write([]byte{recordTypeApplicationData, 0x03, 0x03})
+		putUint16(b, n)
+		b.Write(payload[:n])
+		payload = payload[n:]
+	}
+}
+
+// recordReader returns the content of application data records, and skips handshake and change cipher spec records.
+type recordReader struct {
+	reader   io.Reader
+	leftover int
+}
+
+func (r *recordReader) Read(b []byte) (int, error) {
+	for r.leftover == 0 {
+		var header [recordHeaderSize]byte
+		if _, err := io.ReadFull(r.reader, header[:]); err != nil {
+			return 0, err
+		}
+		size := int(binary.BigEndian.Uint16(header[3:]))
+		switch header[0] {
+		case recordTypeApplicationData:
+			r.leftover = size
+		case recordTypeHandshake, recordTypeChangeCipherSpec:
+			if _, err := io.CopyN(ioutil.Discard, r.reader, int64(size)); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, newError("unexpected TLS record type: ", header[0])
+		}
+	}
+
+	if len(b) > r.leftover {
+		b = b[:r.leftover]
+	}
+	n, err := r.reader.Read(b)
+	r.leftover -= n
+	return n, err
+}
+
+// tlsClientConn sends the first payload as the session ticket of a client hello, and all later data in
+// application data records.
+type tlsClientConn struct {
+	net.Conn
+	host string
+
+	helloSent    bool
+	finishedSent bool
+	reader       *recordReader
+}
+
+// NewTLSClient wraps a connection to a simple-obfs TLS server. host is sent as the server name.
+func NewTLSClient(conn net.Conn, host string) net.Conn {
+	return &tlsClientConn{
+		Conn:   conn,
+		host:   host,
+		reader: &recordReader{reader: conn},
+	}
+}
+
+func (c *tlsClientConn) clientHello(ticket []byte) []byte {
+	var ext bytes.Buffer
+	putUint16(&ext, extensionSessionTicket)
+	putUint16(&ext, len(ticket))
+	ext.Write(ticket)
+	putUint16(&ext, extensionServerName)
+	putUint16(&ext, len(c.host)+5)
+	putUint16(&ext, len(c.host)+3)
+	ext.WriteByte(0)
+	putUint16(&ext, len(c.host))
+	ext.WriteString(c.host)
+	ext.Write(clientHelloExtensions)
+
+	var hello bytes.Buffer
+	hello.Write([]byte{0x03, 0x03})
+	writeRandom(&hello)
+	hello.WriteByte(32)
+	var sessionID [32]byte
+	common.Must2(rand.Read(sessionID[:]))
+	hello.Write(sessionID[:])
+	putUint16(&hello, len(cipherSuites))
+	hello.Write(cipherSuites)
+	hello.Write([]byte{0x01, 0x00})
+	putUint16(&hello, ext.Len())
+	hello.Write(ext.Bytes())
+
+	var record bytes.Buffer
+	record.Write([]byte{recordTypeHandshake, 0x03, 0x01})
+	putUint16(&record, hello.Len()+4)
+	record.Write([]byte{0x01, 0x00})
+	putUint16(&record, hello.Len())
+	record.Write(hello.Bytes())
+	return record.Bytes()
+}
+
+func (c *tlsClientConn) Write(b []byte) (int, error) {
+	var out bytes.Buffer
+	payload := b
+
+	if !c.helloSent {
+		c.helloSent = true
+		ticket := payload
+		if len(ticket) > maxRecordSize {
+			ticket = ticket[:maxRecordSize]
+		}
+		out.Write(c.clientHello(ticket))
+		payload = payload[len(ticket):]
+	}
+
+	if len(payload) > 0 {
+		if !c.finishedSent {
+			c.finishedSent = true
+			out.Write(changeCipherSpec)
+			writeFinished(&out)
+		}
+		writeApplicationData(&out, payload)
+	}
+
+	if _, err := c.Conn.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *tlsClientConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// tlsServerConn takes the first payload from the session ticket of the client hello, and responds with server
+// hello along with the first payload. All later data is sent in application data records.
+type tlsServerConn struct {
+	net.Conn
+
+	helloRead bool
+	ticket    []byte
+	sessionID []byte
+	reader    *recordReader
+
+	helloSent bool
+}
+
+// NewTLSServer wraps a connection from a simple-obfs TLS client.
+func NewTLSServer(conn net.Conn) net.Conn {
+	return &tlsServerConn{
+		Conn:   conn,
+		reader: &recordReader{reader: conn},
+	}
+}
+
+// parseClientHello returns the session ID and session ticket in the given client hello message.
+func parseClientHello(hello []byte) ([]byte, []byte, error) {
+	// Handshake type, length, version and random.
+	if len(hello) < 4+2+32+1 || hello[0] != 0x01 {
+		return nil, nil, newError("not a client hello")
+	}
+	p := hello[4+2+32:]
+
+	sessionIDLen := int(p[0])
+	if len(p) < 1+sessionIDLen+2 {
+		return nil, nil, newError("invalid session ID")
+	}
+	sessionID := p[1 : 1+sessionIDLen]
+	p = p[1+sessionIDLen:]
+
+	cipherSuitesLen := int(binary.BigEndian.Uint16(p))
+	if len(p) < 2+cipherSuitesLen+1 {
+		return nil, nil, newError("invalid cipher suites")
+	}
+	p = p[2+cipherSuitesLen:]
+
+	compressionLen := int(p[0])
+	if len(p) < 1+compressionLen+2 {
+		return nil, nil, newError("invalid compression methods")
+	}
+	p = p[1+compressionLen:]
+
+	extLen := int(binary.BigEndian.Uint16(p))
+	p = p[2:]
+	if len(p) < extLen {
+		return nil, nil, newError("invalid extensions")
+	}
+	p = p[:extLen]
+
+	for len(p) >= 4 {
+		t := binary.BigEndian.Uint16(p)
+		l := int(binary.BigEndian.Uint16(p[2:]))
+		if len(p) < 4+l {
+			return nil, nil, newError("invalid extension: ", t)
+		}
+		if t == extensionSessionTicket {
+			return sessionID, p[4 : 4+l], nil
+		}
+		p = p[4+l:]
+	}
+	return nil, nil, newError("session ticket not found")
+}
+
+func (c *tlsServerConn) Read(b []byte) (int, error) {
+	if !c.helloRead {
+		var header [recordHeaderSize]byte
+		if _, err := io.ReadFull(c.Conn, header[:]); err != nil {
+			return 0, err
+		}
+		if header[0] != recordTypeHandshake {
+			return 0, newError("not a TLS handshake")
+		}
+		hello := make([]byte, binary.BigEndian.Uint16(header[3:]))
+		if _, err := io.ReadFull(c.Conn, hello); err != nil {
+			return 0, err
+		}
+		sessionID, ticket, err := parseClientHello(hello)
+		if err != nil {
+			return 0, newError("invalid client hello").Base(err)
+		}
+		c.sessionID = sessionID
+		c.ticket = ticket
+		c.helloRead = true
+	}
+
+	if len(c.ticket) > 0 {
+		n := copy(b, c.ticket)
+		c.ticket = c.ticket[n:]
+		return n, nil
+	}
+	return c.reader.Read(b)
+}
+
+func (c *tlsServerConn) serverHello() []byte {
+	var hello bytes.Buffer
+	hello.Write([]byte{0x03, 0x03})
+	writeRandom(&hello)
+	hello.WriteByte(byte(len(c.sessionID)))
+	hello.Write(c.sessionID)
+	hello.Write([]byte{0xcc, 0xa8, 0x00})
+	putUint16(&hello, len(serverHelloExtensions))
+	hello.Write(serverHelloExtensions)
+
+	var record bytes.Buffer
+	record.Write([]byte{recordTypeHandshake, 0x03, 0x01})
+	putUint16(&record, hello.Len()+4)
+	record.Write([]byte{0x02, 0x00})
+	putUint16(&record, hello.Len())
+	record.Write(hello.Bytes())
+	return record.Bytes()
+}
+
+func (c *tlsServerConn) Write(b []byte) (int, error) {
+	var out bytes.Buffer
+	if !c.helloSent {
+		c.helloSent = true
+		out.Write(c.serverHello())
+		out.Write(changeCipherSpec)
+		writeFinished(&out)
+	}
+	writeApplicationData(&out, b)
+
+	if _, err := c.Conn.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -3,14 +3,20 @@ package shadowsocks
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"io"
+	"sync"
+	"time"
 
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
 	"v2ray.com/core/common/bitmask"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/crypto"
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
 )
 
 const (
@@ -18,6 +24,29 @@ const (
 	RequestOptionOneTimeAuth bitmask.Byte = 0x01
 )
 
+const (
+	headerTypeClient2022 = 0
+	headerTypeServer2022 = 1
+
+	// requestHeaderSize2022 is the size of the fixed-length request header of Shadowsocks 2022: type, timestamp and length of the variable-length header.
+	requestHeaderSize2022 = 1 + 8 + 2
+	// packetHeaderSize2022 is the size of session ID and packet ID in Shadowsocks 2022 UDP packets.
+	packetHeaderSize2022 = 8 + 8
+	// maxTimeDiff2022 is the max difference between the timestamp in a Shadowsocks 2022 header and local time, in seconds.
+	maxTimeDiff2022  = 30
+	maxPadding2022   = 900
+	packetWindowSize = 64
+	// udpSessionTimeout2022 is the time after which an idle remote UDP session is forgotten. It must be longer than
+	// maxTimeDiff2022, so that packets of forgotten sessions are too old to be accepted again.
+	udpSessionTimeout2022 = time.Minute
+	// saltTimeout2022 is the time for which salts of Shadowsocks 2022 requests are remembered. Requests are accepted
+	// for maxTimeDiff2022 before and after their timestamps, so they are too old to be accepted again after twice the time.
+	saltTimeout2022 = 2 * maxTimeDiff2022 * time.Second
+)
+
+// timeNow returns the current time. Timestamps of Shadowsocks 2022 messages are checked and generated with it.
+var timeNow = time.Now
+
 var addrParser = protocol.NewAddressParser(
 	protocol.AddressFamilyByte(0x01, net.AddressFamilyIPv4),
 	protocol.AddressFamilyByte(0x04, net.AddressFamilyIPv6),
@@ -27,11 +56,59 @@ var addrParser = protocol.NewAddressParser(
 	}),
 )
 
-// ReadTCPSession reads a Shadowsocks TCP session from the given reader, returns its header and remaining parts.
-func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHeader, buf.Reader, error) {
+// checkSalt returns false if the salt of an AEAD cipher has been seen by the filter. filter may be nil.
+func checkSalt(filter *antireplay.BloomRing, cipher Cipher, salt []byte) bool {
+	if filter == nil || !cipher.IsAEAD() || len(salt) == 0 {
+		return true
+	}
+	return filter.Check(salt)
+}
+
+// SaltPool remembers salts of Shadowsocks 2022 requests for as long as the requests may be accepted, as SIP022 requires.
+// Expired salts are removed lazily.
+type SaltPool struct {
+	sync.Mutex
+	salts       map[string]time.Time
+	lastCleanup time.Time
+}
+
+// NewSaltPool creates a new SaltPool.
+func NewSaltPool() *SaltPool {
+	return &SaltPool{
+		salts:       make(map[string]time.Time),
+		lastCleanup: timeNow(),
+	}
+}
+
+// Check adds the salt to the pool. It returns false if the salt is already in the pool.
+func (p *SaltPool) Check(salt []byte) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	now := timeNow()
+	if now.Sub(p.lastCleanup) > saltTimeout2022 {
+		for s, expire := range p.salts {
+			if !now.Before(expire) {
+				delete(p.salts, s)
+			}
+		}
+		p.lastCleanup = now
+	}
+
+	if expire, found := p.salts[string(salt)]; found && now.Before(expire) {
+		return false
+	}
+	p.salts[string(salt)] = now.Add(saltTimeout2022)
+	return true
+}
+
+// ReadTCPSession reads a Shadowsocks TCP session from the given reader, returns its header, the IV of the request and remaining parts.
+// The IV is needed by WriteTCPResponse. Requests whose salts are seen by the filter are rejected. filter may be nil.
+// Salts of Shadowsocks 2022 requests are checked against salts instead of filter, which must not be nil for Shadowsocks 2022 ciphers.
+func ReadTCPSession(user *protocol.User, reader io.Reader, filter *antireplay.BloomRing, salts *SaltPool) (*protocol.RequestHeader, []byte, buf.Reader, error) {
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
-		return nil, nil, newError("failed to parse account").Base(err).AtError()
+		return nil, nil, nil, newError("failed to parse account").Base(err).AtError()
 	}
 	account := rawAccount.(*MemoryAccount)
 
@@ -42,15 +119,23 @@ func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHea
 	var iv []byte
 	if ivLen > 0 {
 		if err := buffer.AppendSupplier(buf.ReadFullFrom(reader, ivLen)); err != nil {
-			return nil, nil, newError("failed to read IV").Base(err)
+			return nil, nil, nil, newError("failed to read IV").Base(err)
 		}
 
 		iv = append([]byte(nil), buffer.BytesTo(ivLen)...)
 	}
 
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		request, bodyReader, err := readTCPSession2022(user, account, cipher, iv, reader, salts)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return request, iv, bodyReader, nil
+	}
+
 	r, err := account.Cipher.NewDecryptionReader(account.Key, iv, reader)
 	if err != nil {
-		return nil, nil, newError("failed to initialize decoding stream").Base(err).AtError()
+		return nil, nil, nil, newError("failed to initialize decoding stream").Base(err).AtError()
 	}
 	br := &buf.BufferedReader{Reader: r}
 	reader = nil
@@ -65,13 +150,18 @@ func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHea
 	buffer.Clear()
 
 	addr, port, err := addrParser.ReadAddressPort(buffer, br)
+	if err != nil {
+		err = newError("failed to read address").Base(err)
+	} else if !checkSalt(filter, account.Cipher, iv) {
+		err = newError("replayed salt")
+	}
 
 	if err != nil {
-		// Invalid address. Continue to read some bytes to confuse client.
+		// Invalid address or replayed request. Continue to read some bytes to confuse client.
 		nBytes := dice.Roll(32) + 1
 		buffer.Clear()
 		buffer.AppendSupplier(buf.ReadFullFrom(br, int32(nBytes)))
-		return nil, nil, newError("failed to read address").Base(err)
+		return nil, nil, nil, err
 	}
 
 	request.Address = addr
@@ -83,11 +173,11 @@ func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHea
 		}
 
 		if request.Option.Has(RequestOptionOneTimeAuth) && account.OneTimeAuth == Account_Disabled {
-			return nil, nil, newError("rejecting connection with OTA enabled, while server disables OTA")
+			return nil, nil, nil, newError("rejecting connection with OTA enabled, while server disables OTA")
 		}
 
 		if !request.Option.Has(RequestOptionOneTimeAuth) && account.OneTimeAuth == Account_Enabled {
-			return nil, nil, newError("rejecting connection with OTA disabled, while server enables OTA")
+			return nil, nil, nil, newError("rejecting connection with OTA disabled, while server enables OTA")
 		}
 	}
 
@@ -97,16 +187,16 @@ func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHea
 
 		err := buffer.AppendSupplier(buf.ReadFullFrom(br, AuthSize))
 		if err != nil {
-			return nil, nil, newError("Failed to read OTA").Base(err)
+			return nil, nil, nil, newError("Failed to read OTA").Base(err)
 		}
 
 		if !bytes.Equal(actualAuth, buffer.BytesFrom(-AuthSize)) {
-			return nil, nil, newError("invalid OTA")
+			return nil, nil, nil, newError("invalid OTA")
 		}
 	}
 
 	if request.Address == nil {
-		return nil, nil, newError("invalid remote address.")
+		return nil, nil, nil, newError("invalid remote address.")
 	}
 
 	br.Direct = true
@@ -118,15 +208,15 @@ func ReadTCPSession(user *protocol.User, reader io.Reader) (*protocol.RequestHea
 		chunkReader = buf.NewReader(br)
 	}
 
-	return request, chunkReader, nil
+	return request, iv, chunkReader, nil
 }
 
-// WriteTCPRequest writes Shadowsocks request into the given writer, and returns a writer for body.
-func WriteTCPRequest(request *protocol.RequestHeader, writer io.Writer) (buf.Writer, error) {
+// WriteTCPRequest writes Shadowsocks request into the given writer, and returns a writer for body, and the IV of the request which is needed by ReadTCPResponse.
+func WriteTCPRequest(request *protocol.RequestHeader, writer io.Writer) (buf.Writer, []byte, error) {
 	user := request.User
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
-		return nil, newError("failed to parse account").Base(err).AtError()
+		return nil, nil, newError("failed to parse account").Base(err).AtError()
 	}
 	account := rawAccount.(*MemoryAccount)
 
@@ -138,20 +228,31 @@ func WriteTCPRequest(request *protocol.RequestHeader, writer io.Writer) (buf.Wri
 	if account.Cipher.IVSize() > 0 {
 		iv = make([]byte, account.Cipher.IVSize())
 		common.Must2(rand.Read(iv))
+	}
+
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		w, err := writeTCPRequest2022(request, account, cipher, iv, writer)
+		if err != nil {
+			return nil, nil, err
+		}
+		return w, iv, nil
+	}
+
+	if len(iv) > 0 {
 		if _, err = writer.Write(iv); err != nil {
-			return nil, newError("failed to write IV")
+			return nil, nil, newError("failed to write IV")
 		}
 	}
 
 	w, err := account.Cipher.NewEncryptionWriter(account.Key, iv, writer)
 	if err != nil {
-		return nil, newError("failed to create encoding stream").Base(err).AtError()
+		return nil, nil, newError("failed to create encoding stream").Base(err).AtError()
 	}
 
 	header := buf.New()
 
 	if err := addrParser.WriteAddressPort(header, request.Address, request.Port); err != nil {
-		return nil, newError("failed to write address").Base(err)
+		return nil, nil, newError("failed to write address").Base(err)
 	}
 
 	if request.Option.Has(RequestOptionOneTimeAuth) {
@@ -162,7 +263,7 @@ func WriteTCPRequest(request *protocol.RequestHeader, writer io.Writer) (buf.Wri
 	}
 
 	if err := w.WriteMultiBuffer(buf.NewMultiBufferValue(header)); err != nil {
-		return nil, newError("failed to write header").Base(err)
+		return nil, nil, newError("failed to write header").Base(err)
 	}
 
 	var chunkWriter buf.Writer
@@ -172,10 +273,11 @@ func WriteTCPRequest(request *protocol.RequestHeader, writer io.Writer) (buf.Wri
 		chunkWriter = w
 	}
 
-	return chunkWriter, nil
+	return chunkWriter, iv, nil
 }
 
-func ReadTCPResponse(user *protocol.User, reader io.Reader) (buf.Reader, error) {
+// ReadTCPResponse reads the response of a request whose IV is requestIV, and returns a reader for the response body.
+func ReadTCPResponse(user *protocol.User, requestIV []byte, reader io.Reader) (buf.Reader, error) {
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
 		return nil, newError("failed to parse account").Base(err).AtError()
@@ -190,10 +292,15 @@ func ReadTCPResponse(user *protocol.User, reader io.Reader) (buf.Reader, error)
 		}
 	}
 
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		return readTCPResponse2022(account, cipher, iv, requestIV, reader)
+	}
+
 	return account.Cipher.NewDecryptionReader(account.Key, iv, reader)
 }
 
-func WriteTCPResponse(request *protocol.RequestHeader, writer io.Writer) (buf.Writer, error) {
+// WriteTCPResponse writes the response of a request whose IV is requestIV, and returns a writer for the response body.
+func WriteTCPResponse(request *protocol.RequestHeader, requestIV []byte, writer io.Writer) (buf.Writer, error) {
 	user := request.User
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
@@ -205,6 +312,19 @@ func WriteTCPResponse(request *protocol.RequestHeader, writer io.Writer) (buf.Wr
 	if account.Cipher.IVSize() > 0 {
 		iv = make([]byte, account.Cipher.IVSize())
 		common.Must2(rand.Read(iv))
+	}
+
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		return &responseWriter2022{
+			key:       account.Key,
+			cipher:    cipher,
+			iv:        iv,
+			requestIV: requestIV,
+			writer:    writer,
+		}, nil
+	}
+
+	if len(iv) > 0 {
 		if _, err = writer.Write(iv); err != nil {
 			return nil, newError("failed to write IV.").Base(err)
 		}
@@ -213,7 +333,267 @@ func WriteTCPResponse(request *protocol.RequestHeader, writer io.Writer) (buf.Wr
 	return account.Cipher.NewEncryptionWriter(account.Key, iv, writer)
 }
 
-func EncodeUDPPacket(request *protocol.RequestHeader, payload []byte) (*buf.Buffer, error) {
+// checkTimestamp2022 returns an error if the timestamp in a Shadowsocks 2022 header is too far away from local time.
+func checkTimestamp2022(timestamp uint64) error {
+	diff := timeNow().Unix() - int64(timestamp)
+	if diff > maxTimeDiff2022 || diff < -maxTimeDiff2022 {
+		return newError("invalid timestamp: ", timestamp)
+	}
+	return nil
+}
+
+// readAuthenticatedChunk reads a chunk of the given plain size from reader, and decrypts it.
+func readAuthenticatedChunk(auth *crypto.AEADAuthenticator, size int32, reader io.Reader) (*buf.Buffer, error) {
+	encryptedSize := size + int32(auth.Overhead())
+	b := buf.NewSize(encryptedSize)
+	if err := b.Reset(buf.ReadFullFrom(reader, encryptedSize)); err != nil {
+		b.Release()
+		return nil, err
+	}
+	plain, err := auth.Open(b.BytesTo(0), b.Bytes())
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	b.Resize(0, int32(len(plain)))
+	return b, nil
+}
+
+// sealChunk encrypts the content of b as a single chunk, and appends it to eb.
+func sealChunk(auth *crypto.AEADAuthenticator, eb *buf.Buffer, b []byte) error {
+	return eb.AppendSupplier(func(bb []byte) (int, error) {
+		out, err := auth.Seal(bb[:0], b)
+		return len(out), err
+	})
+}
+
+func readTCPSession2022(user *protocol.User, account *MemoryAccount, cipher *AEAD2022Cipher, iv []byte, reader io.Reader, salts *SaltPool) (*protocol.RequestHeader, buf.Reader, error) {
+	if salts == nil {
+		return nil, nil, newError("salt pool is required for Shadowsocks 2022").AtError()
+	}
+	auth := cipher.createAuthenticator(account.Key, iv)
+
+	fixedHeader, err := readAuthenticatedChunk(auth, requestHeaderSize2022, reader)
+	if err != nil {
+		return nil, nil, newError("failed to read request header").Base(err)
+	}
+	defer fixedHeader.Release()
+
+	if fixedHeader.Byte(0) != headerTypeClient2022 {
+		return nil, nil, newError("invalid header type: ", fixedHeader.Byte(0))
+	}
+	if err := checkTimestamp2022(binary.BigEndian.Uint64(fixedHeader.BytesRange(1, 9))); err != nil {
+		return nil, nil, err
+	}
+	if !salts.Check(iv) {
+		return nil, nil, newError("replayed salt")
+	}
+	headerLen := int32(binary.BigEndian.Uint16(fixedHeader.BytesRange(9, 11)))
+
+	header, err := readAuthenticatedChunk(auth, headerLen, reader)
+	if err != nil {
+		return nil, nil, newError("failed to read variable-length header").Base(err)
+	}
+
+	addr, port, err := addrParser.ReadAddressPort(nil, header)
+	if err != nil {
+		header.Release()
+		return nil, nil, newError("failed to read address").Base(err)
+	}
+	if header.Len() < 2 {
+		header.Release()
+		return nil, nil, newError("failed to read padding length")
+	}
+	paddingLen := int32(binary.BigEndian.Uint16(header.BytesTo(2)))
+	if paddingLen > maxPadding2022 || header.Len() < 2+paddingLen {
+		header.Release()
+		return nil, nil, newError("invalid padding length: ", paddingLen)
+	}
+	header.Advance(2 + paddingLen)
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandTCP,
+		Address: addr,
+		Port:    port,
+	}
+
+	bodyReader := crypto.NewAuthenticationReader(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, reader, protocol.TransferTypeStream)
+
+	if header.IsEmpty() {
+		header.Release()
+		return request, bodyReader, nil
+	}
+	return request, &prefixedReader{
+		prefix: buf.NewMultiBufferValue(header),
+		reader: bodyReader,
+	}, nil
+}
+
+func writeTCPRequest2022(request *protocol.RequestHeader, account *MemoryAccount, cipher *AEAD2022Cipher, iv []byte, writer io.Writer) (buf.Writer, error) {
+	auth := cipher.createAuthenticator(account.Key, iv)
+
+	header := buf.New()
+	defer header.Release()
+	if err := addrParser.WriteAddressPort(header, request.Address, request.Port); err != nil {
+		return nil, newError("failed to write address").Base(err)
+	}
+	// There is no initial payload in the header, so padding must not be empty.
+	paddingLen := int32(dice.Roll(maxPadding2022) + 1)
+	common.Must(header.AppendSupplier(serial.WriteUint16(uint16(paddingLen))))
+	common.Must(header.AppendSupplier(func(b []byte) (int, error) {
+		for i := int32(0); i < paddingLen; i++ {
+			b[i] = 0
+		}
+		return int(paddingLen), nil
+	}))
+
+	fixedHeader := make([]byte, requestHeaderSize2022)
+	fixedHeader[0] = headerTypeClient2022
+	binary.BigEndian.PutUint64(fixedHeader[1:], uint64(timeNow().Unix()))
+	binary.BigEndian.PutUint16(fixedHeader[9:], uint16(header.Len()))
+
+	eb := buf.New()
+	defer eb.Release()
+	eb.Write(iv)
+	if err := sealChunk(auth, eb, fixedHeader); err != nil {
+		return nil, err
+	}
+	if err := sealChunk(auth, eb, header.Bytes()); err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(eb.Bytes()); err != nil {
+		return nil, newError("failed to write header").Base(err)
+	}
+
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream), nil
+}
+
+func readTCPResponse2022(account *MemoryAccount, cipher *AEAD2022Cipher, iv []byte, requestIV []byte, reader io.Reader) (buf.Reader, error) {
+	auth := cipher.createAuthenticator(account.Key, iv)
+
+	ivLen := int32(len(iv))
+	fixedHeader, err := readAuthenticatedChunk(auth, 1+8+ivLen+2, reader)
+	if err != nil {
+		return nil, newError("failed to read response header").Base(err)
+	}
+	defer fixedHeader.Release()
+
+	if fixedHeader.Byte(0) != headerTypeServer2022 {
+		return nil, newError("invalid header type: ", fixedHeader.Byte(0))
+	}
+	if err := checkTimestamp2022(binary.BigEndian.Uint64(fixedHeader.BytesRange(1, 9))); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(fixedHeader.BytesRange(9, 9+ivLen), requestIV) {
+		return nil, newError("mismatched request salt in response")
+	}
+	payloadLen := int32(binary.BigEndian.Uint16(fixedHeader.BytesFrom(9 + ivLen)))
+
+	payload, err := readAuthenticatedChunk(auth, payloadLen, reader)
+	if err != nil {
+		return nil, newError("failed to read response payload").Base(err)
+	}
+
+	bodyReader := crypto.NewAuthenticationReader(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, reader, protocol.TransferTypeStream)
+
+	if payload.IsEmpty() {
+		payload.Release()
+		return bodyReader, nil
+	}
+	return &prefixedReader{
+		prefix: buf.NewMultiBufferValue(payload),
+		reader: bodyReader,
+	}, nil
+}
+
+// responseWriter2022 writes the Shadowsocks 2022 response header along with the first payload.
+type responseWriter2022 struct {
+	key       []byte
+	cipher    *AEAD2022Cipher
+	iv        []byte
+	requestIV []byte
+	writer    io.Writer
+	body      buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *responseWriter2022) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.body != nil {
+		return w.body.WriteMultiBuffer(mb)
+	}
+
+	auth := w.cipher.createAuthenticator(w.key, w.iv)
+
+	payload := buf.New()
+	defer payload.Release()
+	if !mb.IsEmpty() {
+		common.Must(payload.Reset(func(b []byte) (int, error) {
+			return mb.Read(b[:buf.Size-int32(auth.Overhead())])
+		}))
+	}
+
+	ivLen := len(w.iv)
+	fixedHeader := make([]byte, 1+8+ivLen+2)
+	fixedHeader[0] = headerTypeServer2022
+	binary.BigEndian.PutUint64(fixedHeader[1:], uint64(timeNow().Unix()))
+	copy(fixedHeader[9:], w.requestIV)
+	binary.BigEndian.PutUint16(fixedHeader[9+ivLen:], uint16(payload.Len()))
+
+	header := buf.New()
+	header.Write(w.iv)
+	if err := sealChunk(auth, header, fixedHeader); err != nil {
+		header.Release()
+		mb.Release()
+		return err
+	}
+	first := buf.New()
+	if err := sealChunk(auth, first, payload.Bytes()); err != nil {
+		header.Release()
+		first.Release()
+		mb.Release()
+		return err
+	}
+
+	w.body = crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, w.writer, protocol.TransferTypeStream)
+
+	if err := buf.NewWriter(w.writer).WriteMultiBuffer(buf.NewMultiBufferValue(header, first)); err != nil {
+		mb.Release()
+		return err
+	}
+	if mb.IsEmpty() {
+		return nil
+	}
+	return w.body.WriteMultiBuffer(mb)
+}
+
+// prefixedReader returns the prefix before reading from the underlying reader.
+type prefixedReader struct {
+	prefix buf.MultiBuffer
+	reader buf.Reader
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *prefixedReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if r.prefix != nil {
+		mb := r.prefix
+		r.prefix = nil
+		return mb, nil
+	}
+	return r.reader.ReadMultiBuffer()
+}
+
+// EncodeUDPPacket encodes a UDP packet of the given request. session is required by Shadowsocks 2022 ciphers, and ignored by others.
+func EncodeUDPPacket(request *protocol.RequestHeader, payload []byte, session *UDPSession) (*buf.Buffer, error) {
 	user := request.User
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
@@ -221,6 +601,10 @@ func EncodeUDPPacket(request *protocol.RequestHeader, payload []byte) (*buf.Buff
 	}
 	account := rawAccount.(*MemoryAccount)
 
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		return encodeUDPPacket2022(request, account, cipher, payload, session)
+	}
+
 	buffer := buf.New()
 	ivLen := account.Cipher.IVSize()
 	if ivLen > 0 {
@@ -247,15 +631,21 @@ func EncodeUDPPacket(request *protocol.RequestHeader, payload []byte) (*buf.Buff
 	return buffer, nil
 }
 
-func DecodeUDPPacket(user *protocol.User, payload *buf.Buffer) (*protocol.RequestHeader, *buf.Buffer, error) {
+// DecodeUDPPacket decodes a UDP packet of the given user. session is required by Shadowsocks 2022 ciphers, and ignored by others.
+// Packets of AEAD ciphers whose salts are seen by the filter are rejected. filter may be nil.
+func DecodeUDPPacket(user *protocol.User, payload *buf.Buffer, session *UDPSession, filter *antireplay.BloomRing) (*protocol.RequestHeader, *buf.Buffer, error) {
 	rawAccount, err := user.GetTypedAccount()
 	if err != nil {
 		return nil, nil, newError("failed to parse account").Base(err).AtError()
 	}
 	account := rawAccount.(*MemoryAccount)
 
+	if cipher, ok := account.Cipher.(*AEAD2022Cipher); ok {
+		return decodeUDPPacket2022(user, account, cipher, payload, session)
+	}
+
 	var iv []byte
-	if !account.Cipher.IsAEAD() && account.Cipher.IVSize() > 0 {
+	if account.Cipher.IVSize() > 0 && payload.Len() > account.Cipher.IVSize() {
 		// Keep track of IV as it gets removed from payload in DecodePacket.
 		iv = make([]byte, account.Cipher.IVSize())
 		copy(iv, payload.BytesTo(account.Cipher.IVSize()))
@@ -265,6 +655,10 @@ func DecodeUDPPacket(user *protocol.User, payload *buf.Buffer) (*protocol.Reques
 		return nil, nil, newError("failed to decrypt UDP payload").Base(err)
 	}
 
+	if !checkSalt(filter, account.Cipher, iv) {
+		return nil, nil, newError("replayed salt")
+	}
+
 	request := &protocol.RequestHeader{
 		Version: Version,
 		User:    user,
@@ -312,9 +706,289 @@ func DecodeUDPPacket(user *protocol.User, payload *buf.Buffer) (*protocol.Reques
 	return request, payload, nil
 }
 
+// UDPSession is the state of a Shadowsocks 2022 UDP session, shared by the packets sent and received in the same association.
+// It is not used by other ciphers.
+type UDPSession struct {
+	sync.Mutex
+	server   bool
+	id       uint64
+	packetID uint64
+	remotes  *UDPSessionTable
+
+	// Server side only. The client session that packets are sent to, which is the last one received in the association.
+	remoteID uint64
+	remote   *remoteUDPSession
+}
+
+func randomSessionID() uint64 {
+	var id [8]byte
+	common.Must2(rand.Read(id[:]))
+	return binary.BigEndian.Uint64(id[:])
+}
+
+// NewClientUDPSession creates a UDPSession for the client side.
+func NewClientUDPSession() *UDPSession {
+	return &UDPSession{
+		id:      randomSessionID(),
+		remotes: NewUDPSessionTable(),
+	}
+}
+
+// NewServerUDPSession creates a UDPSession for the server side. Associations of the same server must share the same table,
+// so that packets of a client session are not accepted again in another association.
+func NewServerUDPSession(table *UDPSessionTable) *UDPSession {
+	return &UDPSession{
+		server:  true,
+		remotes: table,
+	}
+}
+
+// nextPacket returns the session ID and packet ID of the next packet to send. On the server side, it also returns the ID of
+// the client session that the packet is sent to. Each client session is replied by a server session of its own.
+func (s *UDPSession) nextPacket() (sessionID uint64, packetID uint64, clientID uint64, err error) {
+	s.Lock()
+	remoteID, remote := s.remoteID, s.remote
+	if !s.server {
+		sessionID, packetID = s.id, s.packetID
+		s.packetID++
+	}
+	s.Unlock()
+
+	if !s.server {
+		return sessionID, packetID, 0, nil
+	}
+	if remote == nil {
+		return 0, 0, 0, newError("no client session to reply to")
+	}
+	sessionID, packetID = s.remotes.nextPacket(remote)
+	return sessionID, packetID, remoteID, nil
+}
+
+// accept returns true if the packet from the remote session is not a replay.
+func (s *UDPSession) accept(remoteID uint64, packetID uint64) bool {
+	remote, ok := s.remotes.accept(remoteID, packetID)
+	if !ok {
+		return false
+	}
+	if s.server {
+		s.Lock()
+		s.remoteID = remoteID
+		s.remote = remote
+		s.Unlock()
+	}
+	return true
+}
+
+// remoteUDPSession is the state of a remote Shadowsocks 2022 UDP session.
+type remoteUDPSession struct {
+	window   packetWindow
+	lastSeen time.Time
+
+	// Server side only. ID and packet ID of the server session that replies to this client session.
+	id       uint64
+	packetID uint64
+}
+
+// UDPSessionTable keeps the replay windows of remote Shadowsocks 2022 UDP sessions, by session ID.
+// Sessions that have been idle for a while are removed.
+type UDPSessionTable struct {
+	sync.Mutex
+	sessions    map[uint64]*remoteUDPSession
+	lastCleanup time.Time
+}
+
+// NewUDPSessionTable creates a new UDPSessionTable.
+func NewUDPSessionTable() *UDPSessionTable {
+	return &UDPSessionTable{
+		sessions:    make(map[uint64]*remoteUDPSession),
+		lastCleanup: time.Now(),
+	}
+}
+
+// accept returns the remote session of the given ID, and true if the packet ID is not a replay in the session.
+func (t *UDPSessionTable) accept(remoteID uint64, packetID uint64) (*remoteUDPSession, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	if now.Sub(t.lastCleanup) > udpSessionTimeout2022 {
+		for id, r := range t.sessions {
+			if now.Sub(r.lastSeen) > udpSessionTimeout2022 {
+				delete(t.sessions, id)
+			}
+		}
+		t.lastCleanup = now
+	}
+
+	r, found := t.sessions[remoteID]
+	if !found {
+		r = &remoteUDPSession{
+			id: randomSessionID(),
+		}
+		t.sessions[remoteID] = r
+	}
+	if !r.window.check(packetID) {
+		return nil, false
+	}
+	r.lastSeen = now
+	return r, true
+}
+
+func (t *UDPSessionTable) nextPacket(r *remoteUDPSession) (uint64, uint64) {
+	t.Lock()
+	defer t.Unlock()
+
+	packetID := r.packetID
+	r.packetID++
+	return r.id, packetID
+}
+
+// packetWindow is a sliding window for detecting replayed packet IDs.
+type packetWindow struct {
+	next   uint64
+	bitmap uint64
+}
+
+// check returns false if the packet ID has been seen, or is too old to tell. Otherwise it records the ID and returns true.
+func (w *packetWindow) check(id uint64) bool {
+	if id >= w.next {
+		shift := id - w.next + 1
+		if shift >= packetWindowSize {
+			w.bitmap = 0
+		} else {
+			w.bitmap <<= shift
+		}
+		w.bitmap |= 1
+		w.next = id + 1
+		return true
+	}
+
+	diff := w.next - 1 - id
+	if diff >= packetWindowSize {
+		return false
+	}
+	mask := uint64(1) << diff
+	if w.bitmap&mask != 0 {
+		return false
+	}
+	w.bitmap |= mask
+	return true
+}
+
+func encodeUDPPacket2022(request *protocol.RequestHeader, account *MemoryAccount, cipher *AEAD2022Cipher, payload []byte, session *UDPSession) (*buf.Buffer, error) {
+	if session == nil {
+		return nil, newError("UDP session is required by Shadowsocks 2022")
+	}
+
+	sessionID, packetID, clientID, err := session.nextPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := buf.New()
+	nonceSize := cipher.packetNonceSize()
+	common.Must(buffer.Reset(func(b []byte) (int, error) {
+		b = b[:nonceSize+packetHeaderSize2022+1+8]
+		binary.BigEndian.PutUint64(b[nonceSize:], sessionID)
+		binary.BigEndian.PutUint64(b[nonceSize+8:], packetID)
+		if session.server {
+			b[nonceSize+16] = headerTypeServer2022
+		} else {
+			b[nonceSize+16] = headerTypeClient2022
+		}
+		binary.BigEndian.PutUint64(b[nonceSize+17:], uint64(timeNow().Unix()))
+		return len(b), nil
+	}))
+	if session.server {
+		common.Must(buffer.AppendSupplier(func(b []byte) (int, error) {
+			binary.BigEndian.PutUint64(b, clientID)
+			return 8, nil
+		}))
+	}
+	// No padding.
+	common.Must(buffer.AppendSupplier(serial.WriteUint16(0)))
+
+	if err := addrParser.WriteAddressPort(buffer, request.Address, request.Port); err != nil {
+		buffer.Release()
+		return nil, newError("failed to write address").Base(err)
+	}
+
+	if int32(len(payload)) > buf.Size-buffer.Len()-int32(cipher.AEADAuthCreator(account.Key).Overhead()) {
+		buffer.Release()
+		return nil, newError("UDP payload too large: ", len(payload))
+	}
+	buffer.Write(payload)
+
+	if err := cipher.EncodePacket(account.Key, buffer); err != nil {
+		buffer.Release()
+		return nil, newError("failed to encrypt UDP payload").Base(err)
+	}
+	return buffer, nil
+}
+
+func decodeUDPPacket2022(user *protocol.User, account *MemoryAccount, cipher *AEAD2022Cipher, payload *buf.Buffer, session *UDPSession) (*protocol.RequestHeader, *buf.Buffer, error) {
+	if session == nil {
+		return nil, nil, newError("UDP session is required by Shadowsocks 2022")
+	}
+
+	if err := cipher.DecodePacket(account.Key, payload); err != nil {
+		return nil, nil, newError("failed to decrypt UDP payload").Base(err)
+	}
+
+	headerLen := int32(packetHeaderSize2022 + 1 + 8 + 2)
+	if !session.server {
+		headerLen += 8
+	}
+	if payload.Len() < headerLen {
+		return nil, nil, newError("insufficient data: ", payload.Len())
+	}
+
+	sessionID := binary.BigEndian.Uint64(payload.BytesTo(8))
+	packetID := binary.BigEndian.Uint64(payload.BytesRange(8, 16))
+	expectedType := byte(headerTypeServer2022)
+	if session.server {
+		expectedType = headerTypeClient2022
+	}
+	if payload.Byte(16) != expectedType {
+		return nil, nil, newError("invalid header type: ", payload.Byte(16))
+	}
+	if err := checkTimestamp2022(binary.BigEndian.Uint64(payload.BytesRange(17, 25))); err != nil {
+		return nil, nil, err
+	}
+	if !session.server && binary.BigEndian.Uint64(payload.BytesRange(25, 33)) != session.id {
+		return nil, nil, newError("mismatched client session ID")
+	}
+	payload.Advance(headerLen - 2)
+
+	paddingLen := int32(binary.BigEndian.Uint16(payload.BytesTo(2)))
+	if payload.Len() < 2+paddingLen {
+		return nil, nil, newError("invalid padding length: ", paddingLen)
+	}
+	payload.Advance(2 + paddingLen)
+
+	addr, port, err := addrParser.ReadAddressPort(nil, payload)
+	if err != nil {
+		return nil, nil, newError("failed to parse address").Base(err)
+	}
+
+	if !session.accept(sessionID, packetID) {
+		return nil, nil, newError("replayed packet: ", packetID)
+	}
+
+	return &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandUDP,
+		Address: addr,
+		Port:    port,
+	}, payload, nil
+}
+
 type UDPReader struct {
 	Reader io.Reader
 	User   *protocol.User
+	// Session is required by Shadowsocks 2022 ciphers.
+	Session *UDPSession
 }
 
 func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
@@ -324,7 +998,7 @@ func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
 		buffer.Release()
 		return nil, err
 	}
-	_, payload, err := DecodeUDPPacket(v.User, buffer)
+	_, payload, err := DecodeUDPPacket(v.User, buffer, v.Session, nil)
 	if err != nil {
 		buffer.Release()
 		return nil, err
@@ -335,11 +1009,13 @@ func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
 type UDPWriter struct {
 	Writer  io.Writer
 	Request *protocol.RequestHeader
+	// Session is required by Shadowsocks 2022 ciphers.
+	Session *UDPSession
 }
 
 // Write implements io.Writer.
 func (w *UDPWriter) Write(payload []byte) (int, error) {
-	packet, err := EncodeUDPPacket(w.Request, payload)
+	packet, err := EncodeUDPPacket(w.Request, payload, w.Session)
 	if err != nil {
 		return 0, err
 	}
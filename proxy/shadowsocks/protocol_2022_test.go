@@ -0,0 +1,437 @@
+package shadowsocks_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"lukechampine.com/blake3"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/core/proxy/shadowsocks"
+	. "v2ray.com/ext/assert"
+)
+
+// The helpers below build and parse messages following SIP022 independently of the implementation.
+
+func sessionKey2022(psk []byte, salt []byte) []byte {
+	material := append(append([]byte(nil), psk...), salt...)
+	key := make([]byte, len(psk))
+	blake3.DeriveKey(key, "shadowsocks 2022 session subkey", material)
+	return key
+}
+
+func newAesGcm(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	common.Must(err)
+	aead, err := cipher.NewGCM(block)
+	common.Must(err)
+	return aead
+}
+
+// streamAEAD seals and opens chunks with the little endian counter nonce of Shadowsocks AEAD streams.
+type streamAEAD struct {
+	aead  cipher.AEAD
+	nonce [12]byte
+}
+
+func (s *streamAEAD) next() []byte {
+	n := append([]byte(nil), s.nonce[:]...)
+	for i := range s.nonce {
+		s.nonce[i]++
+		if s.nonce[i] != 0 {
+			break
+		}
+	}
+	return n
+}
+
+func (s *streamAEAD) seal(b []byte) []byte {
+	return s.aead.Seal(nil, s.next(), b, nil)
+}
+
+func (s *streamAEAD) open(b []byte) ([]byte, error) {
+	return s.aead.Open(nil, s.next(), b, nil)
+}
+
+func timestamp2022(offset time.Duration) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Add(offset).Unix()))
+	return b
+}
+
+func user2022(cipherType CipherType, psk []byte) *protocol.User {
+	return &protocol.User{
+		Email: "love@v2ray.com",
+		Account: serial.ToTypedMessage(&Account{
+			Password:   base64.StdEncoding.EncodeToString(psk),
+			CipherType: cipherType,
+		}),
+	}
+}
+
+func buildRequest2022(psk []byte, salt []byte, offset time.Duration, payload []byte, chunk []byte) []byte {
+	s := &streamAEAD{aead: newAesGcm(sessionKey2022(psk, salt))}
+
+	// ATYP IPv4, 127.0.0.1:443, no padding, initial payload.
+	header := []byte{0x01, 127, 0, 0, 1, 0x01, 0xbb, 0x00, 0x00}
+	header = append(header, payload...)
+
+	fixed := []byte{0x00}
+	fixed = append(fixed, timestamp2022(offset)...)
+	fixed = append(fixed, byte(len(header)>>8), byte(len(header)))
+
+	request := append([]byte(nil), salt...)
+	request = append(request, s.seal(fixed)...)
+	request = append(request, s.seal(header)...)
+	request = append(request, s.seal([]byte{byte(len(chunk) >> 8), byte(len(chunk))})...)
+	request = append(request, s.seal(chunk)...)
+	return request
+}
+
+func TestTCPRequest2022Interop(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef")
+	salt := []byte("fedcba9876543210")
+	user := user2022(CipherType_BLAKE3_AES_128_GCM, psk)
+
+	request, requestIV, reader, err := ReadTCPSession(user, bytes.NewReader(buildRequest2022(psk, salt, 0, []byte("hello"), []byte("world"))), nil, NewSaltPool())
+	assert(err, IsNil)
+	assert(request.Address, Equals, net.LocalHostIP)
+	assert(request.Port, Equals, net.Port(443))
+	assert(requestIV, Equals, salt)
+
+	mb, err := reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "hello")
+	mb, err = reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "world")
+
+	response := buf.New()
+	defer response.Release()
+	writer, err := WriteTCPResponse(request, requestIV, response)
+	assert(err, IsNil)
+	payload := buf.New()
+	payload.Write([]byte("response"))
+	assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+	b := response.Bytes()
+	responseSalt := b[:16]
+	s := &streamAEAD{aead: newAesGcm(sessionKey2022(psk, responseSalt))}
+	fixed, err := s.open(b[16 : 16+1+8+16+2+16])
+	assert(err, IsNil)
+	assert(fixed[0], Equals, byte(1))
+	assert(fixed[9:25], Equals, salt)
+	length := int(binary.BigEndian.Uint16(fixed[25:]))
+	assert(length, Equals, len("response"))
+	body, err := s.open(b[16+1+8+16+2+16:])
+	assert(err, IsNil)
+	assert(string(body), Equals, "response")
+}
+
+func TestTCPRequest2022Timestamp(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef")
+	salt := []byte("fedcba9876543210")
+	user := user2022(CipherType_BLAKE3_AES_128_GCM, psk)
+
+	_, _, _, err := ReadTCPSession(user, bytes.NewReader(buildRequest2022(psk, salt, -time.Minute, []byte("hello"), []byte("world"))), nil, NewSaltPool())
+	assert(err, IsNotNil)
+
+	_, _, _, err = ReadTCPSession(user, bytes.NewReader(buildRequest2022(psk, salt, time.Minute, []byte("hello"), []byte("world"))), nil, NewSaltPool())
+	assert(err, IsNotNil)
+}
+
+func TestTCPSession2022(t *testing.T) {
+	assert := With(t)
+
+	cases := []struct {
+		cipherType CipherType
+		psk        []byte
+	}{
+		{CipherType_BLAKE3_AES_128_GCM, []byte("0123456789abcdef")},
+		{CipherType_BLAKE3_AES_256_GCM, []byte("0123456789abcdef0123456789abcdef")},
+		{CipherType_BLAKE3_CHACHA20_POLY1305, []byte("0123456789abcdef0123456789abcdef")},
+	}
+
+	for _, c := range cases {
+		user := user2022(c.cipherType, c.psk)
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("v2ray.com"),
+			Port:    80,
+			User:    user,
+		}
+
+		cache := buf.New()
+		writer, requestIV, err := WriteTCPRequest(request, cache)
+		assert(err, IsNil)
+		payload := buf.New()
+		payload.Write([]byte("request"))
+		assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+		decodedRequest, decodedIV, reader, err := ReadTCPSession(user, cache, nil, NewSaltPool())
+		assert(err, IsNil)
+		assert(decodedRequest.Address, Equals, request.Address)
+		assert(decodedRequest.Port, Equals, request.Port)
+		assert(decodedIV, Equals, requestIV)
+		mb, err := reader.ReadMultiBuffer()
+		assert(err, IsNil)
+		assert(mb.String(), Equals, "request")
+
+		cache.Clear()
+		responseWriter, err := WriteTCPResponse(decodedRequest, decodedIV, cache)
+		assert(err, IsNil)
+		payload = buf.New()
+		payload.Write([]byte("response"))
+		assert(responseWriter.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+		responseReader, err := ReadTCPResponse(user, requestIV, cache)
+		assert(err, IsNil)
+		mb, err = responseReader.ReadMultiBuffer()
+		assert(err, IsNil)
+		assert(mb.String(), Equals, "response")
+		cache.Release()
+	}
+}
+
+func TestTCPResponse2022MismatchedSalt(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef")
+	user := user2022(CipherType_BLAKE3_AES_128_GCM, psk)
+	request := &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandTCP,
+		Address: net.LocalHostIP,
+		Port:    80,
+		User:    user,
+	}
+
+	cache := buf.New()
+	defer cache.Release()
+	writer, err := WriteTCPResponse(request, []byte("fedcba9876543210"), cache)
+	assert(err, IsNil)
+	assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(buf.New())), IsNil)
+
+	_, err = ReadTCPResponse(user, []byte("0000000000000000"), cache)
+	assert(err, IsNotNil)
+}
+
+// sealUDPPacket2022 builds a client packet of AES ciphers to 8.8.8.8:53. header is the plain separate header of session ID and packet ID,
+// and encryptedHeader is the same header encrypted by the PSK, as it is sent.
+func sealUDPPacket2022(psk []byte, header []byte, encryptedHeader []byte, payload []byte) []byte {
+	body := []byte{0x00}
+	body = append(body, timestamp2022(0)...)
+	body = append(body, 0x00, 0x00)
+	body = append(body, 0x01, 8, 8, 8, 8, 0x00, 0x35)
+	body = append(body, payload...)
+
+	packet := append([]byte(nil), encryptedHeader...)
+	return append(packet, newAesGcm(sessionKey2022(psk, header[:8])).Seal(nil, header[4:16], body, nil)...)
+}
+
+func encryptHeader2022(psk []byte, header []byte) []byte {
+	block, err := aes.NewCipher(psk)
+	common.Must(err)
+	encryptedHeader := make([]byte, 16)
+	block.Encrypt(encryptedHeader, header)
+	return encryptedHeader
+}
+
+func TestUDPPacket2022SeparateHeaderInterop(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef")
+	user := user2022(CipherType_BLAKE3_AES_128_GCM, psk)
+	block, err := aes.NewCipher(psk)
+	common.Must(err)
+
+	// Client session ID 1, packet ID 0.
+	header := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}
+	packet := sealUDPPacket2022(psk, header, encryptHeader2022(psk, header), []byte("query"))
+
+	session := NewServerUDPSession(NewUDPSessionTable())
+	b := buf.New()
+	b.Write(packet)
+	request, data, err := DecodeUDPPacket(user, b, session, nil)
+	assert(err, IsNil)
+	assert(request.Address, Equals, net.ParseAddress("8.8.8.8"))
+	assert(request.Port, Equals, net.Port(53))
+	assert(data.String(), Equals, "query")
+
+	replay := buf.New()
+	replay.Write(packet)
+	_, _, err = DecodeUDPPacket(user, replay, session, nil)
+	assert(err, IsNotNil)
+
+	response, err := EncodeUDPPacket(request, []byte("answer"), session)
+	assert(err, IsNil)
+	r := response.Bytes()
+	serverHeader := make([]byte, 16)
+	block.Decrypt(serverHeader, r[:16])
+	plain, err := newAesGcm(sessionKey2022(psk, serverHeader[:8])).Open(nil, serverHeader[4:16], r[16:], nil)
+	assert(err, IsNil)
+	assert(plain[0], Equals, byte(1))
+	assert(binary.BigEndian.Uint64(plain[9:17]), Equals, uint64(1))
+	assert(plain[17:19], Equals, []byte{0, 0})
+	assert(plain[19:26], Equals, []byte{0x01, 8, 8, 8, 8, 0x00, 0x35})
+	assert(string(plain[26:]), Equals, "answer")
+}
+
+func TestUDPPacket2022SeparateHeaderVectors(t *testing.T) {
+	assert := With(t)
+
+	// Known answers of AES from FIPS-197 appendix C. The plaintext is taken as session ID 0x0011223344556677 and
+	// packet ID 0x8899aabbccddeeff, so the ciphertext is the separate header sent with the key as PSK.
+	header := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	cases := []struct {
+		cipherType      CipherType
+		psk             []byte
+		encryptedHeader []byte
+	}{
+		{
+			cipherType:      CipherType_BLAKE3_AES_128_GCM,
+			psk:             []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f},
+			encryptedHeader: []byte{0x69, 0xc4, 0xe0, 0xd8, 0x6a, 0x7b, 0x04, 0x30, 0xd8, 0xcd, 0xb7, 0x80, 0x70, 0xb4, 0xc5, 0x5a},
+		},
+		{
+			cipherType: CipherType_BLAKE3_AES_256_GCM,
+			psk: []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+				0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f},
+			encryptedHeader: []byte{0x8e, 0xa2, 0xb7, 0xca, 0x51, 0x67, 0x45, 0xbf, 0xea, 0xfc, 0x49, 0x90, 0x4b, 0x49, 0x60, 0x89},
+		},
+	}
+
+	for _, c := range cases {
+		user := user2022(c.cipherType, c.psk)
+		session := NewServerUDPSession(NewUDPSessionTable())
+
+		b := buf.New()
+		b.Write(sealUDPPacket2022(c.psk, header, c.encryptedHeader, []byte("query")))
+		request, data, err := DecodeUDPPacket(user, b, session, nil)
+		assert(err, IsNil)
+		assert(request.Address, Equals, net.ParseAddress("8.8.8.8"))
+		assert(data.String(), Equals, "query")
+
+		// The response is sent to the client session in the header.
+		response, err := EncodeUDPPacket(request, []byte("answer"), session)
+		assert(err, IsNil)
+		r := response.Bytes()
+		block, err := aes.NewCipher(c.psk)
+		common.Must(err)
+		serverHeader := make([]byte, 16)
+		block.Decrypt(serverHeader, r[:16])
+		plain, err := newAesGcm(sessionKey2022(c.psk, serverHeader[:8])).Open(nil, serverHeader[4:16], r[16:], nil)
+		assert(err, IsNil)
+		assert(plain[9:17], Equals, header[:8])
+	}
+}
+
+func TestUDPPacket2022ReplayAcrossSessions(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef")
+	user := user2022(CipherType_BLAKE3_AES_128_GCM, psk)
+	table := NewUDPSessionTable()
+	session := NewServerUDPSession(table)
+
+	decode := func(session *UDPSession, packet []byte) error {
+		b := buf.New()
+		b.Write(packet)
+		_, _, err := DecodeUDPPacket(user, b, session, nil)
+		return err
+	}
+
+	// Packet 0 of client sessions 1 and 2.
+	header1 := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}
+	header2 := []byte{0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0}
+	packet1 := sealUDPPacket2022(psk, header1, encryptHeader2022(psk, header1), []byte("query"))
+	packet2 := sealUDPPacket2022(psk, header2, encryptHeader2022(psk, header2), []byte("query"))
+
+	assert(decode(session, packet1), IsNil)
+	assert(decode(session, packet2), IsNil)
+
+	// Switching between client sessions doesn't reset their windows.
+	assert(decode(session, packet1), IsNotNil)
+	assert(decode(session, packet2), IsNotNil)
+
+	// Nor does sending the packets in another association of the same server.
+	assert(decode(NewServerUDPSession(table), packet1), IsNotNil)
+	assert(decode(NewServerUDPSession(NewUDPSessionTable()), packet1), IsNil)
+}
+
+func TestUDPPacket2022XChaChaInterop(t *testing.T) {
+	assert := With(t)
+
+	psk := []byte("0123456789abcdef0123456789abcdef")
+	user := user2022(CipherType_BLAKE3_CHACHA20_POLY1305, psk)
+	request := &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandUDP,
+		Address: net.ParseAddress("8.8.8.8"),
+		Port:    53,
+		User:    user,
+	}
+
+	clientSession := NewClientUDPSession()
+	packet, err := EncodeUDPPacket(request, []byte("query"), clientSession)
+	assert(err, IsNil)
+
+	aead, err := chacha20poly1305.NewX(psk)
+	common.Must(err)
+	p := packet.Bytes()
+	plain, err := aead.Open(nil, p[:24], p[24:], nil)
+	assert(err, IsNil)
+	assert(binary.BigEndian.Uint64(plain[8:16]), Equals, uint64(0))
+	assert(plain[16], Equals, byte(0))
+	assert(plain[25:27], Equals, []byte{0, 0})
+	assert(plain[27:34], Equals, []byte{0x01, 8, 8, 8, 8, 0x00, 0x35})
+	assert(string(plain[34:]), Equals, "query")
+
+	serverSession := NewServerUDPSession(NewUDPSessionTable())
+	decodedRequest, data, err := DecodeUDPPacket(user, packet, serverSession, nil)
+	assert(err, IsNil)
+	assert(decodedRequest.Address, Equals, request.Address)
+	assert(data.String(), Equals, "query")
+
+	response, err := EncodeUDPPacket(decodedRequest, []byte("answer"), serverSession)
+	assert(err, IsNil)
+	_, data, err = DecodeUDPPacket(user, response, clientSession, nil)
+	assert(err, IsNil)
+	assert(data.String(), Equals, "answer")
+
+	// Packets of another client session are rejected by the client.
+	response, err = EncodeUDPPacket(decodedRequest, []byte("answer"), serverSession)
+	assert(err, IsNil)
+	_, _, err = DecodeUDPPacket(user, response, NewClientUDPSession(), nil)
+	assert(err, IsNotNil)
+}
+
+func TestAccount2022InvalidKey(t *testing.T) {
+	assert := With(t)
+
+	account := &Account{
+		Password:   base64.StdEncoding.EncodeToString([]byte("short")),
+		CipherType: CipherType_BLAKE3_AES_128_GCM,
+	}
+	_, err := account.AsAccount()
+	assert(err, IsNotNil)
+
+	account.Password = "not base64"
+	_, err = account.AsAccount()
+	assert(err, IsNotNil)
+}
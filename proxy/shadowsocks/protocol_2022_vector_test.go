@@ -0,0 +1,165 @@
+package shadowsocks
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/ext/assert"
+)
+
+// The vectors below are 2022-blake3-aes-128-gcm messages produced by a standalone implementation of SIP022, whose AES,
+// GCM and BLAKE3 primitives were checked against the FIPS-197, NIST GCM and official BLAKE3 test vectors. It shares no
+// code with this package. All vectors use the key 000102..0f and the timestamp 1700000000.
+const (
+	vectorKey2022       = "AAECAwQFBgcICQoLDA0ODw=="
+	vectorTimestamp2022 = 1700000000
+
+	// Salt 101112..1f, address example.com:443, no padding, initial payload "GET / HTTP/1.1\r\n\r\n", followed by
+	// the chunk "more".
+	vectorTCPRequest2022 = "101112131415161718191a1b1c1d1e1f" +
+		"f62b42ac395d4aead07a5076ae261fd1d4082be96c7968100f4b5ae19e0c3d" +
+		"c0253175322489f04f22537d6f7bc5813bd29ae35227a4d97b65f1bcad93da7086dc2a28309fc44b756f7bce3ec52abed1b205" +
+		"b1527aea5ec9551d5dcfa926451faf39204c12b4e7fcc2343b6d593b53234a8acd5a"
+	// Salt 202122..2f, replying the request above with the payload "HTTP/1.1 200 OK\r\n\r\n", followed by the
+	// chunk "bye".
+	vectorTCPResponse2022 = "202122232425262728292a2b2c2d2e2f" +
+		"cfd439ccd5cb580032874d1521cc11d623310888444d4ba6dd1aa882e4892b129c48ba7b35a491381984195689c6b845" +
+		"d372bb5ad2b72484fa6c40ad2e19a6227ba435684a0db76dc95a057aff28bd2bdb525bc14f" +
+		"d421a64dd7f3834d506c1401553a497891bcd77f83136561e803c279d227"
+	// Client session 0102..08, packet 0, no padding, address 8.8.8.8:53, payload "query".
+	vectorUDPRequest2022 = "18ba69bb4661fee5a7cc9ec1a731e278" +
+		"0f887748f59fbaddc74035aaf8f0984819108a6aef6d33ed4cd6b26d72e390c3cefa74ac8a03e3"
+	// Server session 1112..18, packet 0, replying the client session above with no padding, address 8.8.8.8:53,
+	// payload "answer".
+	vectorUDPResponse2022 = "f5b62a6fb8eef776abf657056c2aa5a9" +
+		"5aba22e25f5ebfbc60c0d767f7702020126d373413dfffc24e2fbcc6cd3b457dff3cac0eaec2e9ad5ac7bb08fb403cf5"
+	vectorClientSessionID2022 = 0x0102030405060708
+)
+
+// setTimeNow makes timeNow return the given time, until the returned function is called.
+func setTimeNow(now time.Time) func() {
+	timeNow = func() time.Time {
+		return now
+	}
+	return func() {
+		timeNow = time.Now
+	}
+}
+
+func vectorUser2022() *protocol.User {
+	return &protocol.User{
+		Email: "love@v2ray.com",
+		Account: serial.ToTypedMessage(&Account{
+			Password:   vectorKey2022,
+			CipherType: CipherType_BLAKE3_AES_128_GCM,
+		}),
+	}
+}
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	common.Must(err)
+	return b
+}
+
+func TestTCPVector2022(t *testing.T) {
+	assert := With(t)
+	defer setTimeNow(time.Unix(vectorTimestamp2022, 0))()
+
+	user := vectorUser2022()
+	request, requestIV, reader, err := ReadTCPSession(user, bytes.NewReader(decodeHex(vectorTCPRequest2022)), nil, NewSaltPool())
+	assert(err, IsNil)
+	assert(request.Command, Equals, protocol.RequestCommandTCP)
+	assert(request.Address, Equals, net.DomainAddress("example.com"))
+	assert(request.Port, Equals, net.Port(443))
+	assert(requestIV, Equals, decodeHex("101112131415161718191a1b1c1d1e1f"))
+
+	mb, err := reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "GET / HTTP/1.1\r\n\r\n")
+	mb, err = reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "more")
+	_, err = reader.ReadMultiBuffer()
+	assert(err, Equals, io.EOF)
+
+	reader, err = ReadTCPResponse(user, requestIV, bytes.NewReader(decodeHex(vectorTCPResponse2022)))
+	assert(err, IsNil)
+	mb, err = reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "HTTP/1.1 200 OK\r\n\r\n")
+	mb, err = reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.String(), Equals, "bye")
+	_, err = reader.ReadMultiBuffer()
+	assert(err, Equals, io.EOF)
+}
+
+func TestUDPVector2022(t *testing.T) {
+	assert := With(t)
+	defer setTimeNow(time.Unix(vectorTimestamp2022, 0))()
+
+	user := vectorUser2022()
+	client := &UDPSession{
+		id:      vectorClientSessionID2022,
+		remotes: NewUDPSessionTable(),
+	}
+	server := NewServerUDPSession(NewUDPSessionTable())
+
+	packet, err := EncodeUDPPacket(&protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandUDP,
+		Address: net.IPAddress([]byte{8, 8, 8, 8}),
+		Port:    53,
+		User:    user,
+	}, []byte("query"), client)
+	assert(err, IsNil)
+	assert(packet.Bytes(), Equals, decodeHex(vectorUDPRequest2022))
+
+	request, data, err := DecodeUDPPacket(user, packet, server, nil)
+	assert(err, IsNil)
+	assert(request.Address, Equals, net.IPAddress([]byte{8, 8, 8, 8}))
+	assert(request.Port, Equals, net.Port(53))
+	assert(data.String(), Equals, "query")
+
+	response := buf.New()
+	common.Must2(response.Write(decodeHex(vectorUDPResponse2022)))
+	request, data, err = DecodeUDPPacket(user, response, client, nil)
+	assert(err, IsNil)
+	assert(request.Address, Equals, net.IPAddress([]byte{8, 8, 8, 8}))
+	assert(request.Port, Equals, net.Port(53))
+	assert(data.String(), Equals, "answer")
+}
+
+func TestTCPSaltPool2022(t *testing.T) {
+	assert := With(t)
+	now := time.Unix(vectorTimestamp2022, 0)
+	defer setTimeNow(now)()
+
+	salts := NewSaltPool()
+	_, _, _, err := ReadTCPSession(vectorUser2022(), bytes.NewReader(decodeHex(vectorTCPRequest2022)), nil, salts)
+	assert(err, IsNil)
+	_, _, _, err = ReadTCPSession(vectorUser2022(), bytes.NewReader(decodeHex(vectorTCPRequest2022)), nil, salts)
+	assert(err, IsNotNil)
+
+	_, _, _, err = ReadTCPSession(vectorUser2022(), bytes.NewReader(decodeHex(vectorTCPRequest2022)), nil, nil)
+	assert(err, IsNotNil)
+
+	salt := decodeHex("101112131415161718191a1b1c1d1e1f")
+	setTimeNow(now.Add(saltTimeout2022 - time.Second))
+	assert(salts.Check(salt), IsFalse)
+
+	setTimeNow(now.Add(saltTimeout2022 + time.Second))
+	assert(salts.Check([]byte("another salt")), IsTrue)
+	assert(len(salts.salts), Equals, 1)
+	assert(salts.Check(salt), IsTrue)
+	assert(salts.Check(salt), IsFalse)
+}
@@ -31,10 +31,10 @@ func TestUDPEncoding(t *testing.T) {
 
 	data := buf.NewSize(256)
 	data.AppendSupplier(serial.WriteString("test string"))
-	encodedData, err := EncodeUDPPacket(request, data.Bytes())
+	encodedData, err := EncodeUDPPacket(request, data.Bytes(), nil)
 	assert(err, IsNil)
 
-	decodedRequest, decodedData, err := DecodeUDPPacket(request.User, encodedData)
+	decodedRequest, decodedData, err := DecodeUDPPacket(request.User, encodedData, nil, nil)
 	assert(err, IsNil)
 	assert(decodedData.Bytes(), Equals, data.Bytes())
 	assert(decodedRequest.Address, Equals, request.Address)
@@ -110,12 +110,12 @@ func TestTCPRequest(t *testing.T) {
 		cache := buf.New()
 		defer cache.Release()
 
-		writer, err := WriteTCPRequest(request, cache)
+		writer, _, err := WriteTCPRequest(request, cache)
 		assert(err, IsNil)
 
 		assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(data)), IsNil)
 
-		decodedRequest, reader, err := ReadTCPSession(request.User, cache)
+		decodedRequest, _, reader, err := ReadTCPSession(request.User, cache, nil, nil)
 		assert(err, IsNil)
 		assert(decodedRequest.Address, Equals, request.Address)
 		assert(decodedRequest.Port, Equals, request.Port)
@@ -176,3 +176,73 @@ func TestUDPReaderWriter(t *testing.T) {
 	assert(err, IsNil)
 	assert(payload[0].String(), Equals, "test payload 2")
 }
+
+func TestTCPReplay(t *testing.T) {
+	assert := With(t)
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandTCP,
+		Address: net.LocalHostIP,
+		Port:    1234,
+		User: &protocol.User{
+			Email: "love@v2ray.com",
+			Account: serial.ToTypedMessage(&Account{
+				Password:   "password",
+				CipherType: CipherType_AES_256_GCM,
+			}),
+		},
+	}
+
+	filter := (&ReplayFilter{}).Build()
+
+	cache := buf.New()
+	defer cache.Release()
+	writer, _, err := WriteTCPRequest(request, cache)
+	assert(err, IsNil)
+	data := buf.New()
+	data.Write([]byte("test string"))
+	assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(data)), IsNil)
+	captured := append([]byte(nil), cache.Bytes()...)
+
+	_, _, _, err = ReadTCPSession(request.User, cache, filter, nil)
+	assert(err, IsNil)
+
+	replay := buf.New()
+	defer replay.Release()
+	replay.Write(captured)
+	_, _, _, err = ReadTCPSession(request.User, replay, filter, nil)
+	assert(err, IsNotNil)
+}
+
+func TestUDPReplay(t *testing.T) {
+	assert := With(t)
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandUDP,
+		Address: net.LocalHostIP,
+		Port:    1234,
+		User: &protocol.User{
+			Email: "love@v2ray.com",
+			Account: serial.ToTypedMessage(&Account{
+				Password:   "password",
+				CipherType: CipherType_CHACHA20_POLY1305,
+			}),
+		},
+	}
+
+	filter := (&ReplayFilter{Capacity: 16}).Build()
+
+	packet, err := EncodeUDPPacket(request, []byte("test string"), nil)
+	assert(err, IsNil)
+	replay := buf.New()
+	replay.Write(packet.Bytes())
+
+	_, data, err := DecodeUDPPacket(request.User, packet, nil, filter)
+	assert(err, IsNil)
+	assert(data.String(), Equals, "test string")
+
+	_, _, err = DecodeUDPPacket(request.User, replay, nil, filter)
+	assert(err, IsNotNil)
+}
@@ -2,11 +2,14 @@ package shadowsocks
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
@@ -18,34 +21,59 @@ import (
 )
 
 type Server struct {
-	config  ServerConfig
-	user    *protocol.User
-	account *MemoryAccount
-	v       *core.Instance
+	config       ServerConfig
+	validator    *Validator
+	replayFilter *antireplay.BloomRing
+	salts        *SaltPool
+	udpSessions  *UDPSessionTable
+	v            *core.Instance
 }
 
 // NewServer create a new Shadowsocks server.
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
-	if config.GetUser() == nil {
+	users := config.Users
+	if config.User != nil {
+		users = append([]*protocol.User{config.User}, users...)
+	}
+	if len(users) == 0 {
 		return nil, newError("user is not specified")
 	}
 
-	rawAccount, err := config.User.GetTypedAccount()
-	if err != nil {
-		return nil, newError("failed to get user account").Base(err)
+	validator := NewValidator()
+	for _, user := range users {
+		if err := validator.Add(user); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
 	}
-	account := rawAccount.(*MemoryAccount)
 
 	s := &Server{
-		config:  *config,
-		user:    config.GetUser(),
-		account: account,
-		v:       core.MustFromContext(ctx),
+		config:       *config,
+		validator:    validator,
+		replayFilter: config.ReplayFilter.Build(),
+		salts:        NewSaltPool(),
+		udpSessions:  NewUDPSessionTable(),
+		v:            core.MustFromContext(ctx),
 	}
 
 	return s, nil
 }
 
+// AddUser implements proxy.UserManager.AddUser().
+func (s *Server) AddUser(ctx context.Context, user *protocol.User) error {
+	return s.validator.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.RemoveUser().
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	if len(email) == 0 {
+		return newError("Email must not be empty.")
+	}
+	if !s.validator.Remove(email) {
+		return newError("User ", email, " not found.")
+	}
+	return nil
+}
+
 func (s *Server) Network() net.NetworkList {
 	list := net.NetworkList{
 		Network: s.config.Network,
@@ -72,6 +100,20 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 
 func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection, dispatcher core.Dispatcher) error {
 	udpServer := udp.NewDispatcher(dispatcher)
+	session := NewServerUDPSession(s.udpSessions)
+
+	// The full-cone dispatcher is created with the first valid packet, as responses are encoded for its user.
+	var fullConeServer *udp.FullConeDispatcher
+	defer func() {
+		if fullConeServer != nil {
+			fullConeServer.Close()
+		}
+	}()
+
+	var sourceAddr net.Address
+	if source, ok := proxy.SourceFromContext(ctx); ok {
+		sourceAddr = source.Address
+	}
 
 	reader := buf.NewReader(conn)
 	for {
@@ -81,7 +123,7 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		}
 
 		for _, payload := range mpayload {
-			request, data, err := DecodeUDPPacket(s.user, payload)
+			request, data, err := s.validator.DecodeUDPPacket(sourceAddr, payload, session, s.replayFilter)
 			if err != nil {
 				if source, ok := proxy.SourceFromContext(ctx); ok {
 					newError("dropping invalid UDP packet from: ", source).Base(err).WithContext(ctx).WriteToLog()
@@ -92,18 +134,7 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 						Reason: err,
 					})
 				}
-				payload.Release()
-				continue
-			}
-
-			if request.Option.Has(RequestOptionOneTimeAuth) && s.account.OneTimeAuth == Account_Disabled {
-				newError("client payload enables OTA but server doesn't allow it").WithContext(ctx).WriteToLog()
-				payload.Release()
-				continue
-			}
-
-			if !request.Option.Has(RequestOptionOneTimeAuth) && s.account.OneTimeAuth == Account_Enabled {
-				newError("client payload disables OTA but server forces it").WithContext(ctx).WriteToLog()
+				proxy.ReportAuthFailure(ctx)
 				payload.Release()
 				continue
 			}
@@ -120,8 +151,15 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 			newError("tunnelling request to ", dest).WithContext(ctx).WriteToLog()
 
 			ctx = protocol.ContextWithUser(ctx, request.User)
+			if s.config.UdpFullCone {
+				if fullConeServer == nil {
+					fullConeServer = s.newFullConeDispatcher(ctx, dispatcher, conn, request, session)
+				}
+				fullConeServer.Dispatch(ctx, dest, data)
+				continue
+			}
 			udpServer.Dispatch(ctx, dest, data, func(payload *buf.Buffer) {
-				data, err := EncodeUDPPacket(request, payload.Bytes())
+				data, err := EncodeUDPPacket(request, payload.Bytes(), session)
 				payload.Release()
 				if err != nil {
 					newError("failed to encode UDP packet").Base(err).AtWarning().WithContext(ctx).WriteToLog()
@@ -137,11 +175,41 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 	return nil
 }
 
+// newFullConeDispatcher returns a FullConeDispatcher that writes responses from any source back to conn.
+func (s *Server) newFullConeDispatcher(ctx context.Context, dispatcher core.Dispatcher, conn internet.Connection, request *protocol.RequestHeader, session *UDPSession) *udp.FullConeDispatcher {
+	return udp.NewFullConeDispatcher(dispatcher, func(source net.Destination, payload *buf.Buffer) {
+		response := *request
+		response.Address = source.Address
+		response.Port = source.Port
+		data, err := EncodeUDPPacket(&response, payload.Bytes(), session)
+		payload.Release()
+		if err != nil {
+			newError("failed to encode UDP packet").Base(err).AtWarning().WithContext(ctx).WriteToLog()
+			return
+		}
+		defer data.Release()
+
+		conn.Write(data.Bytes())
+	})
+}
+
 func (s *Server) handleConnection(ctx context.Context, conn internet.Connection, dispatcher core.Dispatcher) error {
-	sessionPolicy := s.v.PolicyManager().ForLevel(s.user.Level)
-	conn.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake))
+	conn = s.config.Obfs.WrapServer(conn)
+	conn.SetReadDeadline(time.Now().Add(s.v.PolicyManager().ForLevel(0).Timeouts.Handshake))
 	bufferedReader := buf.BufferedReader{Reader: buf.NewReader(conn)}
-	request, bodyReader, err := ReadTCPSession(s.user, &bufferedReader)
+
+	var sourceAddr net.Address
+	if source, ok := proxy.SourceFromContext(ctx); ok {
+		sourceAddr = source.Address
+	}
+
+	var request *protocol.RequestHeader
+	var requestIV []byte
+	var bodyReader buf.Reader
+	user, reader, err := s.validator.GetTCP(sourceAddr, &bufferedReader)
+	if err == nil {
+		request, requestIV, bodyReader, err = ReadTCPSession(user, reader, s.replayFilter, s.salts)
+	}
 	if err != nil {
 		log.Record(&log.AccessMessage{
 			From:   conn.RemoteAddr(),
@@ -149,11 +217,15 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 			Status: log.AccessRejected,
 			Reason: err,
 		})
+		if errors.Cause(err) != io.EOF {
+			proxy.ReportAuthFailure(ctx)
+		}
 		return newError("failed to create request from: ", conn.RemoteAddr()).Base(err)
 	}
 	conn.SetReadDeadline(time.Time{})
 
 	bufferedReader.Direct = true
+	sessionPolicy := s.v.PolicyManager().ForLevel(user.Level)
 
 	dest := request.Destination()
 	log.Record(&log.AccessMessage{
@@ -177,7 +249,7 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		responseWriter, err := WriteTCPResponse(request, bufferedWriter)
+		responseWriter, err := WriteTCPResponse(request, requestIV, bufferedWriter)
 		if err != nil {
 			return newError("failed to write response").Base(err)
 		}
@@ -0,0 +1,224 @@
+package shadowsocks
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/antireplay"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+)
+
+// maxSourceCache is the max number of source IPs whose last matched users are remembered.
+const maxSourceCache = 4096
+
+type validatorUser struct {
+	user    *protocol.User
+	account *MemoryAccount
+}
+
+// Validator holds the users of a Shadowsocks server, and identifies the user of incoming requests.
+// When there are more than one users, all of them must use AEAD ciphers, so that users can be identified by trying their keys.
+type Validator struct {
+	sync.RWMutex
+	users []*validatorUser
+
+	// lastMatched is the user that a source IP authenticated as last time, which is tried first.
+	cacheAccess sync.Mutex
+	lastMatched map[string]*validatorUser
+}
+
+// NewValidator creates a new empty Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		lastMatched: make(map[string]*validatorUser),
+	}
+}
+
+// headerMatcher is implemented by ciphers whose users can be identified by the beginning of TCP streams.
+type headerMatcher interface {
+	Cipher
+	chunkHeaderSize() int32
+	matchChunkHeader(key []byte, b []byte) bool
+}
+
+func isIdentifiable(account *MemoryAccount) bool {
+	_, ok := account.Cipher.(headerMatcher)
+	return ok
+}
+
+// Add adds a user.
+func (v *Validator) Add(u *protocol.User) error {
+	rawAccount, err := u.GetTypedAccount()
+	if err != nil {
+		return newError("failed to get user account").Base(err)
+	}
+	account, ok := rawAccount.(*MemoryAccount)
+	if !ok {
+		return newError("not a Shadowsocks account")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if len(v.users) > 0 {
+		if !isIdentifiable(account) || !isIdentifiable(v.users[0].account) {
+			return newError("multiple users are only supported with AEAD ciphers")
+		}
+		for _, existing := range v.users {
+			if len(u.Email) > 0 && strings.EqualFold(existing.user.Email, u.Email) {
+				return newError("user ", u.Email, " already exists")
+			}
+		}
+	}
+
+	v.users = append(v.users, &validatorUser{
+		user:    u,
+		account: account,
+	})
+	return nil
+}
+
+// Remove removes the user with the given email. It returns false if the user is not found.
+func (v *Validator) Remove(email string) bool {
+	v.Lock()
+	defer v.Unlock()
+
+	idx := -1
+	for i, u := range v.users {
+		if strings.EqualFold(u.user.Email, email) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return false
+	}
+
+	removed := v.users[idx]
+	users := make([]*validatorUser, 0, len(v.users)-1)
+	users = append(users, v.users[:idx]...)
+	users = append(users, v.users[idx+1:]...)
+	v.users = users
+
+	v.cacheAccess.Lock()
+	for source, u := range v.lastMatched {
+		if u == removed {
+			delete(v.lastMatched, source)
+		}
+	}
+	v.cacheAccess.Unlock()
+
+	return true
+}
+
+// Count returns the number of users.
+func (v *Validator) Count() int {
+	v.RLock()
+	defer v.RUnlock()
+	return len(v.users)
+}
+
+// candidates returns the users to try for the given source, with the one it matched last time first.
+func (v *Validator) candidates(source net.Address) []*validatorUser {
+	v.RLock()
+	users := v.users
+	v.RUnlock()
+
+	if source == nil || len(users) <= 1 {
+		return users
+	}
+
+	v.cacheAccess.Lock()
+	last, found := v.lastMatched[source.String()]
+	v.cacheAccess.Unlock()
+	if !found {
+		return users
+	}
+
+	ordered := make([]*validatorUser, 0, len(users))
+	ordered = append(ordered, last)
+	for _, u := range users {
+		if u != last {
+			ordered = append(ordered, u)
+		}
+	}
+	return ordered
+}
+
+func (v *Validator) remember(source net.Address, u *validatorUser) {
+	if source == nil {
+		return
+	}
+	v.cacheAccess.Lock()
+	defer v.cacheAccess.Unlock()
+
+	if len(v.lastMatched) >= maxSourceCache {
+		v.lastMatched = make(map[string]*validatorUser)
+	}
+	v.lastMatched[source.String()] = u
+}
+
+// GetTCP identifies the user of a TCP connection from the given source. It returns the user and a reader that
+// replays the bytes consumed for identification.
+func (v *Validator) GetTCP(source net.Address, reader io.Reader) (*protocol.User, io.Reader, error) {
+	users := v.candidates(source)
+	switch len(users) {
+	case 0:
+		return nil, nil, newError("no user")
+	case 1:
+		return users[0].user, reader, nil
+	}
+
+	// Salt and the first encrypted chunk of fixed length.
+	var headerLen int32
+	for _, u := range users {
+		c := u.account.Cipher.(headerMatcher)
+		if l := c.IVSize() + c.chunkHeaderSize(); l > headerLen {
+			headerLen = l
+		}
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, newError("failed to read request header").Base(err)
+	}
+	replay := io.MultiReader(bytes.NewReader(header), reader)
+
+	for _, u := range users {
+		c := u.account.Cipher.(headerMatcher)
+		if c.matchChunkHeader(u.account.Key, header) {
+			v.remember(source, u)
+			return u.user, replay, nil
+		}
+	}
+	return nil, nil, newError("no matching user")
+}
+
+// DecodeUDPPacket identifies the user of a UDP packet from the given source, and decodes the packet.
+func (v *Validator) DecodeUDPPacket(source net.Address, payload *buf.Buffer, session *UDPSession, filter *antireplay.BloomRing) (*protocol.RequestHeader, *buf.Buffer, error) {
+	users := v.candidates(source)
+	switch len(users) {
+	case 0:
+		return nil, nil, newError("no user")
+	case 1:
+		return DecodeUDPPacket(users[0].user, payload, session, filter)
+	}
+
+	for _, u := range users {
+		// Decoding happens in place, so each user works on a copy of the packet.
+		b := buf.New()
+		b.Write(payload.Bytes())
+		request, data, err := DecodeUDPPacket(u.user, b, session, filter)
+		if err != nil {
+			b.Release()
+			continue
+		}
+		v.remember(source, u)
+		payload.Release()
+		return request, data, nil
+	}
+	return nil, nil, newError("no matching user")
+}
@@ -0,0 +1,109 @@
+package shadowsocks_test
+
+import (
+	"bytes"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/core/proxy/shadowsocks"
+	. "v2ray.com/ext/assert"
+)
+
+func newUser(email string, password string, cipherType CipherType) *protocol.User {
+	return &protocol.User{
+		Email: email,
+		Account: serial.ToTypedMessage(&Account{
+			Password:   password,
+			CipherType: cipherType,
+		}),
+	}
+}
+
+func TestValidatorTCP(t *testing.T) {
+	assert := With(t)
+
+	users := []*protocol.User{
+		newUser("a@v2ray.com", "a", CipherType_AES_256_GCM),
+		newUser("b@v2ray.com", "b", CipherType_CHACHA20_POLY1305),
+		newUser("c@v2ray.com", "c", CipherType_AES_128_GCM),
+	}
+	validator := NewValidator()
+	for _, user := range users {
+		assert(validator.Add(user), IsNil)
+	}
+	assert(validator.Add(users[0]), IsNotNil)
+	assert(validator.Add(newUser("d@v2ray.com", "d", CipherType_AES_128_CFB)), IsNotNil)
+
+	source := net.ParseAddress("10.0.0.1")
+	for i := 0; i < 2; i++ {
+		for _, user := range users {
+			request := &protocol.RequestHeader{
+				Version: Version,
+				Command: protocol.RequestCommandTCP,
+				Address: net.DomainAddress("v2ray.com"),
+				Port:    443,
+				User:    user,
+			}
+
+			cache := buf.New()
+			writer, _, err := WriteTCPRequest(request, cache)
+			assert(err, IsNil)
+			payload := buf.New()
+			common.Must2(payload.Write([]byte("payload")))
+			assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(payload)), IsNil)
+
+			matched, reader, err := validator.GetTCP(source, bytes.NewReader(cache.Bytes()))
+			assert(err, IsNil)
+			assert(matched.Email, Equals, user.Email)
+
+			decodedRequest, _, bodyReader, err := ReadTCPSession(matched, reader, nil, nil)
+			assert(err, IsNil)
+			assert(decodedRequest.Address, Equals, request.Address)
+			assert(decodedRequest.Port, Equals, request.Port)
+
+			mb, err := bodyReader.ReadMultiBuffer()
+			assert(err, IsNil)
+			assert(mb.String(), Equals, "payload")
+		}
+	}
+
+	assert(validator.Remove("b@v2ray.com"), IsTrue)
+	assert(validator.Remove("b@v2ray.com"), IsFalse)
+	assert(validator.Count(), Equals, 2)
+}
+
+func TestValidatorUDP(t *testing.T) {
+	assert := With(t)
+
+	users := []*protocol.User{
+		newUser("a@v2ray.com", "a", CipherType_AES_128_GCM),
+		newUser("b@v2ray.com", "b", CipherType_AES_128_GCM),
+	}
+	validator := NewValidator()
+	for _, user := range users {
+		assert(validator.Add(user), IsNil)
+	}
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandUDP,
+		Address: net.LocalHostIP,
+		Port:    53,
+		User:    users[1],
+	}
+	packet, err := EncodeUDPPacket(request, []byte("query"), nil)
+	assert(err, IsNil)
+
+	decodedRequest, data, err := validator.DecodeUDPPacket(net.ParseAddress("10.0.0.1"), packet, nil, nil)
+	assert(err, IsNil)
+	assert(decodedRequest.User.Email, Equals, "b@v2ray.com")
+	assert(decodedRequest.Port, Equals, request.Port)
+	assert(data.String(), Equals, "query")
+
+	_, _, err = validator.DecodeUDPPacket(net.ParseAddress("10.0.0.1"), buf.New(), nil, nil)
+	assert(err, IsNotNil)
+}
@@ -0,0 +1,61 @@
+package socks
+
+import (
+	"sync"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal"
+)
+
+// udpAssociation is a UDP relay established by a UDP ASSOCIATE request. It lives as long as its TCP control connection.
+type udpAssociation struct {
+	// client is the address where UDP packets are expected from. Packets from any port are accepted if Port is 0.
+	client net.Destination
+	timer  signal.ActivityUpdater
+	done   *signal.Done
+}
+
+func (a *udpAssociation) accepts(source net.Destination) bool {
+	if a.client.Address != source.Address {
+		return false
+	}
+	return a.client.Port == 0 || a.client.Port == source.Port
+}
+
+type udpAssociationManager struct {
+	sync.RWMutex
+	associations map[*udpAssociation]bool
+}
+
+func newUDPAssociationManager() *udpAssociationManager {
+	return &udpAssociationManager{
+		associations: make(map[*udpAssociation]bool),
+	}
+}
+
+func (m *udpAssociationManager) add(a *udpAssociation) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.associations[a] = true
+}
+
+func (m *udpAssociationManager) remove(a *udpAssociation) {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.associations, a)
+}
+
+// find returns the association that accepts UDP packets from the given source, or nil if none.
+func (m *udpAssociationManager) find(source net.Destination) *udpAssociation {
+	m.RLock()
+	defer m.RUnlock()
+
+	for a := range m.associations {
+		if a.accepts(source) {
+			return a
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package socks
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
@@ -136,6 +137,85 @@ func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dial
 	return nil
 }
 
+// Bind implements proxy.Binder. It asks the SOCKS server to listen for an incoming connection from peer.
+func (c *Client) Bind(ctx context.Context, peer net.Destination, dialer proxy.Dialer) (proxy.BindListener, error) {
+	server := c.serverPicker.PickServer()
+	conn, err := dialer.Dial(ctx, server.Destination())
+	if err != nil {
+		return nil, newError("failed to dial server ", server.Destination()).Base(err)
+	}
+
+	request := &protocol.RequestHeader{
+		Version: socks5Version,
+		Command: requestCommandBind,
+		Address: peer.Address,
+		Port:    peer.Port,
+	}
+
+	p := c.policyManager.ForLevel(0)
+	if user := server.PickUser(); user != nil {
+		request.User = user
+		p = c.policyManager.ForLevel(user.Level)
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(p.Timeouts.Handshake)); err != nil {
+		newError("failed to set deadline for handshake").Base(err).WithContext(ctx).WriteToLog()
+	}
+	response, err := ClientHandshake(request, conn, conn)
+	if err != nil {
+		conn.Close()
+		return nil, newError("failed to bind on server").AtWarning().Base(err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		newError("failed to clear deadline after handshake").Base(err).WithContext(ctx).WriteToLog()
+	}
+
+	return &clientBindListener{
+		conn: conn,
+		addr: net.TCPDestination(response.Address, response.Port),
+	}, nil
+}
+
+type clientBindListener struct {
+	access   sync.Mutex
+	conn     internet.Connection
+	addr     net.Destination
+	accepted bool
+	closed   bool
+}
+
+func (l *clientBindListener) Addr() net.Destination {
+	return l.addr
+}
+
+// Accept waits for the second reply of the server. The connection to the server is then relayed to the peer.
+func (l *clientBindListener) Accept() (internet.Connection, net.Destination, error) {
+	address, port, err := readSocks5Response(l.conn)
+	if err != nil {
+		return nil, net.Destination{}, newError("failed to read bind reply").Base(err)
+	}
+
+	l.access.Lock()
+	defer l.access.Unlock()
+	if l.closed {
+		return nil, net.Destination{}, newError("bind listener closed")
+	}
+	l.accepted = true
+	return l.conn, net.TCPDestination(address, port), nil
+}
+
+// Close closes the connection to the server, unless it has been returned by Accept().
+func (l *clientBindListener) Close() error {
+	l.access.Lock()
+	defer l.access.Unlock()
+
+	if l.accepted || l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.conn.Close()
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*ClientConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return NewClient(ctx, config.(*ClientConfig))
@@ -69,6 +69,9 @@ type ServerConfig struct {
 	UdpEnabled bool                              `protobuf:"varint,4,opt,name=udp_enabled,json=udpEnabled" json:"udp_enabled,omitempty"`
 	Timeout    uint32                            `protobuf:"varint,5,opt,name=timeout" json:"timeout,omitempty"`
 	UserLevel  uint32                            `protobuf:"varint,6,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
+	// Sends UDP packets of each client through a single full-cone link, so that responses from any source are passed
+	// back. Outbounds without full-cone support only send packets to the first destination.
+	UdpFullCone bool `protobuf:"varint,7,opt,name=udp_full_cone,json=udpFullCone" json:"udp_full_cone,omitempty"`
 }
 
 func (m *ServerConfig) Reset()                    { *m = ServerConfig{} }
@@ -118,6 +121,13 @@ func (m *ServerConfig) GetUserLevel() uint32 {
 	return 0
 }
 
+func (m *ServerConfig) GetUdpFullCone() bool {
+	if m != nil {
+		return m.UdpFullCone
+	}
+	return false
+}
+
 type ClientConfig struct {
 	Server []*v2ray_core_common_protocol1.ServerEndpoint `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
 }
@@ -144,35 +154,36 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/proxy/socks/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 470 bytes of a gzipped FileDescriptorProto
+	// 494 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x5d, 0x8b, 0xd3, 0x40,
-	0x14, 0x75, 0xb2, 0xb6, 0x4d, 0x6f, 0xbb, 0x52, 0x06, 0x59, 0x42, 0x51, 0x8c, 0x05, 0xb1, 0xec,
-	0xc3, 0x44, 0xe2, 0x8b, 0xb8, 0x28, 0xb4, 0xdd, 0x82, 0x82, 0x6c, 0xcb, 0x74, 0x55, 0xf0, 0x25,
-	0xcc, 0x4e, 0x46, 0x37, 0x6c, 0x32, 0x13, 0x66, 0x26, 0xd5, 0xfc, 0x25, 0xff, 0x9f, 0xef, 0x92,
-	0xaf, 0x65, 0x95, 0xee, 0xdb, 0xfd, 0x38, 0xf7, 0xcc, 0x3d, 0xe7, 0x0e, 0xbc, 0xdc, 0x87, 0x9a,
-	0x95, 0x84, 0xab, 0x2c, 0xe0, 0x4a, 0x8b, 0x20, 0xd7, 0xea, 0x57, 0x19, 0x18, 0xc5, 0x6f, 0x4c,
-	0xc0, 0x95, 0xfc, 0x9e, 0xfc, 0x20, 0xb9, 0x56, 0x56, 0xe1, 0x93, 0x0e, 0xa8, 0x05, 0xa9, 0x41,
-	0xa4, 0x06, 0x4d, 0xff, 0x27, 0xe0, 0x2a, 0xcb, 0x94, 0x0c, 0xa4, 0xb0, 0x01, 0x8b, 0x63, 0x2d,
-	0x8c, 0x69, 0x08, 0xa6, 0xaf, 0x0e, 0x03, 0xeb, 0x26, 0x57, 0x69, 0x60, 0x84, 0xde, 0x0b, 0x1d,
-	0x99, 0x5c, 0xf0, 0x66, 0x62, 0xb6, 0x80, 0xc1, 0x82, 0x73, 0x55, 0x48, 0x8b, 0xa7, 0xe0, 0x16,
-	0x46, 0x68, 0xc9, 0x32, 0xe1, 0x21, 0x1f, 0xcd, 0x87, 0xf4, 0x36, 0xaf, 0x7a, 0x39, 0x33, 0xe6,
-	0xa7, 0xd2, 0xb1, 0xe7, 0x34, 0xbd, 0x2e, 0x9f, 0xfd, 0x71, 0x60, 0xbc, 0xab, 0x89, 0x57, 0xb5,
-	0x18, 0xfc, 0x0e, 0x86, 0xac, 0xb0, 0xd7, 0x91, 0x2d, 0xf3, 0x86, 0xe9, 0x51, 0xe8, 0x93, 0xc3,
-	0xd2, 0xc8, 0xa2, 0xb0, 0xd7, 0x97, 0x65, 0x2e, 0xa8, 0xcb, 0xda, 0x08, 0x5f, 0x80, 0xcb, 0x9a,
-	0x95, 0x8c, 0xe7, 0xf8, 0x47, 0xf3, 0x51, 0x18, 0xde, 0x37, 0x7d, 0xf7, 0x59, 0xd2, 0xea, 0x30,
-	0x6b, 0x69, 0x75, 0x49, 0x6f, 0x39, 0xf0, 0x19, 0x0c, 0x5a, 0x97, 0xbc, 0x23, 0x1f, 0xcd, 0x47,
-	0xe1, 0xf3, 0xbb, 0x74, 0x8d, 0x45, 0x44, 0x0a, 0x4b, 0x3e, 0x6e, 0x37, 0xfa, 0x5c, 0x65, 0x2c,
-	0x91, 0xb4, 0x9b, 0xc0, 0xcf, 0x60, 0x54, 0xc4, 0x79, 0x24, 0x24, 0xbb, 0x4a, 0x45, 0xec, 0x3d,
-	0xf4, 0xd1, 0xdc, 0xa5, 0x50, 0xc4, 0xf9, 0xba, 0xa9, 0xe0, 0x27, 0x30, 0xb0, 0x49, 0x26, 0x54,
-	0x61, 0xbd, 0x9e, 0x8f, 0xe6, 0xc7, 0x4b, 0xc7, 0x43, 0xb4, 0x2b, 0xe1, 0xa7, 0x00, 0x95, 0x87,
-	0x51, 0x2a, 0xf6, 0x22, 0xf5, 0xfa, 0x15, 0x80, 0x0e, 0xab, 0xca, 0xa7, 0xaa, 0x30, 0x3d, 0x83,
-	0xe3, 0x7f, 0xb6, 0xc6, 0x13, 0x38, 0xba, 0x11, 0x65, 0x6b, 0x7f, 0x15, 0xe2, 0xc7, 0xd0, 0xdb,
-	0xb3, 0xb4, 0x10, 0xad, 0xed, 0x4d, 0xf2, 0xd6, 0x79, 0x83, 0x66, 0x14, 0xc6, 0xab, 0x34, 0x11,
-	0xd2, 0xb6, 0xb6, 0x2f, 0xa1, 0xdf, 0xdc, 0xd7, 0x43, 0xb5, 0x6b, 0xa7, 0x07, 0x64, 0x76, 0x3f,
-	0xa1, 0x75, 0x6e, 0x2d, 0xe3, 0x5c, 0x25, 0xd2, 0xd2, 0x76, 0xf2, 0xf4, 0x05, 0xb8, 0xdd, 0x45,
-	0xf0, 0x08, 0x06, 0x17, 0x9b, 0x68, 0xf1, 0xf9, 0xf2, 0xc3, 0xe4, 0x01, 0x1e, 0x83, 0xbb, 0x5d,
-	0xec, 0x76, 0x5f, 0x37, 0xf4, 0x7c, 0x82, 0x96, 0xef, 0x61, 0xca, 0x55, 0x76, 0xcf, 0x55, 0xb6,
-	0xe8, 0x5b, 0xaf, 0x0e, 0x7e, 0x3b, 0x27, 0x5f, 0x42, 0xca, 0x4a, 0xb2, 0xaa, 0x10, 0xdb, 0x1a,
-	0xb1, 0xab, 0x1a, 0x57, 0xfd, 0x7a, 0x8f, 0xd7, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x23, 0xac,
-	0x72, 0x71, 0x1a, 0x03, 0x00, 0x00,
+	0x14, 0x35, 0xad, 0xdb, 0xa6, 0xb7, 0xad, 0x94, 0x41, 0x96, 0x50, 0x14, 0x63, 0x41, 0x2c, 0xfb,
+	0x90, 0x48, 0x7c, 0x11, 0x17, 0x85, 0xb6, 0x5b, 0x51, 0x90, 0x6d, 0x99, 0xae, 0x0a, 0xbe, 0x84,
+	0xd9, 0xc9, 0x5d, 0x37, 0x6c, 0x32, 0x13, 0x66, 0x26, 0xd5, 0xfc, 0x14, 0xff, 0x82, 0xbf, 0x52,
+	0xf2, 0xb5, 0xac, 0xd2, 0x7d, 0xbb, 0x1f, 0xe7, 0x9e, 0x99, 0x7b, 0xce, 0x85, 0x97, 0xfb, 0x40,
+	0xb1, 0xc2, 0xe3, 0x32, 0xf5, 0xb9, 0x54, 0xe8, 0x67, 0x4a, 0xfe, 0x2a, 0x7c, 0x2d, 0xf9, 0x8d,
+	0xf6, 0xb9, 0x14, 0x57, 0xf1, 0x0f, 0x2f, 0x53, 0xd2, 0x48, 0x72, 0xdc, 0x02, 0x15, 0x7a, 0x15,
+	0xc8, 0xab, 0x40, 0xd3, 0xff, 0x09, 0xb8, 0x4c, 0x53, 0x29, 0x7c, 0x81, 0xc6, 0x67, 0x51, 0xa4,
+	0x50, 0xeb, 0x9a, 0x60, 0xfa, 0xea, 0x30, 0xb0, 0x6a, 0x72, 0x99, 0xf8, 0x1a, 0xd5, 0x1e, 0x55,
+	0xa8, 0x33, 0xe4, 0xf5, 0xc4, 0x6c, 0x01, 0xfd, 0x05, 0xe7, 0x32, 0x17, 0x86, 0x4c, 0xc1, 0xce,
+	0x35, 0x2a, 0xc1, 0x52, 0x74, 0x2c, 0xd7, 0x9a, 0x0f, 0xe8, 0x6d, 0x5e, 0xf6, 0x32, 0xa6, 0xf5,
+	0x4f, 0xa9, 0x22, 0xa7, 0x53, 0xf7, 0xda, 0x7c, 0xf6, 0xbb, 0x0b, 0xa3, 0x5d, 0x45, 0xbc, 0xaa,
+	0x96, 0x21, 0xef, 0x60, 0xc0, 0x72, 0x73, 0x1d, 0x9a, 0x22, 0xab, 0x99, 0x1e, 0x05, 0xae, 0x77,
+	0x78, 0x35, 0x6f, 0x91, 0x9b, 0xeb, 0x8b, 0x22, 0x43, 0x6a, 0xb3, 0x26, 0x22, 0xe7, 0x60, 0xb3,
+	0xfa, 0x4b, 0xda, 0xe9, 0xb8, 0xdd, 0xf9, 0x30, 0x08, 0xee, 0x9b, 0xbe, 0xfb, 0xac, 0xd7, 0xec,
+	0xa1, 0xd7, 0xc2, 0xa8, 0x82, 0xde, 0x72, 0x90, 0x53, 0xe8, 0x37, 0x2a, 0x39, 0x5d, 0xd7, 0x9a,
+	0x0f, 0x83, 0xe7, 0x77, 0xe9, 0x6a, 0x89, 0x3c, 0x81, 0xc6, 0xfb, 0xb4, 0xdd, 0xa8, 0x33, 0x99,
+	0xb2, 0x58, 0xd0, 0x76, 0x82, 0x3c, 0x83, 0x61, 0x1e, 0x65, 0x21, 0x0a, 0x76, 0x99, 0x60, 0xe4,
+	0x3c, 0x74, 0xad, 0xb9, 0x4d, 0x21, 0x8f, 0xb2, 0x75, 0x5d, 0x21, 0x4f, 0xa0, 0x6f, 0xe2, 0x14,
+	0x65, 0x6e, 0x9c, 0x23, 0xd7, 0x9a, 0x8f, 0x97, 0x1d, 0xc7, 0xa2, 0x6d, 0x89, 0x3c, 0x05, 0x28,
+	0x35, 0x0c, 0x13, 0xdc, 0x63, 0xe2, 0xf4, 0x4a, 0x00, 0x1d, 0x94, 0x95, 0xcf, 0x65, 0x81, 0xcc,
+	0x60, 0x5c, 0xb2, 0x5f, 0xe5, 0x49, 0x12, 0x72, 0x29, 0xd0, 0xe9, 0x57, 0xfc, 0xe5, 0x93, 0x1f,
+	0xf2, 0x24, 0x59, 0x49, 0x81, 0xd3, 0x53, 0x18, 0xff, 0xb3, 0x19, 0x99, 0x40, 0xf7, 0x06, 0x8b,
+	0xc6, 0xa2, 0x32, 0x24, 0x8f, 0xe1, 0x68, 0xcf, 0x92, 0x1c, 0x1b, 0x6b, 0xea, 0xe4, 0x6d, 0xe7,
+	0x8d, 0x35, 0xa3, 0x30, 0x5a, 0x25, 0x31, 0x0a, 0xd3, 0x58, 0xb3, 0x84, 0x5e, 0x7d, 0x03, 0x8e,
+	0x55, 0x29, 0x7b, 0x72, 0x40, 0x8a, 0xf6, 0x5a, 0x1a, 0x75, 0xd7, 0x22, 0xca, 0x64, 0x2c, 0x0c,
+	0x6d, 0x26, 0x4f, 0x5e, 0x80, 0xdd, 0xba, 0x46, 0x86, 0xd0, 0x3f, 0xdf, 0x84, 0x8b, 0x2f, 0x17,
+	0x1f, 0x27, 0x0f, 0xc8, 0x08, 0xec, 0xed, 0x62, 0xb7, 0xfb, 0xb6, 0xa1, 0x67, 0x13, 0x6b, 0xf9,
+	0x1e, 0xa6, 0x5c, 0xa6, 0xf7, 0x38, 0xb7, 0xb5, 0xbe, 0x1f, 0x55, 0xc1, 0x9f, 0xce, 0xf1, 0xd7,
+	0x80, 0xb2, 0xc2, 0x5b, 0x95, 0x88, 0x6d, 0x85, 0xd8, 0x95, 0x8d, 0xcb, 0x5e, 0xf5, 0x8f, 0xd7,
+	0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x5c, 0xa9, 0x2b, 0xc1, 0x3e, 0x03, 0x00, 0x00,
 }
@@ -26,10 +26,16 @@ const (
 	authPassword         = 0x02
 	authNoMatchingMethod = 0xFF
 
-	statusSuccess       = 0x00
-	statusCmdNotSupport = 0x07
+	statusSuccess        = 0x00
+	statusGeneralFailure = 0x01
+	statusCmdNotSupport  = 0x07
 )
 
+// requestCommandBind is the command of BIND requests in RequestHeader.
+const requestCommandBind = protocol.RequestCommand(0x10)
+
+var errInvalidAccount = newError("invalid username or password")
+
 var addrParser = protocol.NewAddressParser(
 	protocol.AddressFamilyByte(0x01, net.AddressFamilyIPv4),
 	protocol.AddressFamilyByte(0x04, net.AddressFamilyIPv6),
@@ -38,7 +44,6 @@ var addrParser = protocol.NewAddressParser(
 
 type ServerSession struct {
 	config *ServerConfig
-	port   net.Port
 }
 
 func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
@@ -119,7 +124,7 @@ func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol
 
 			if !s.config.HasAccount(username, password) {
 				writeSocks5AuthenticationResponse(writer, 0x01, 0xFF)
-				return nil, newError("invalid username or password")
+				return nil, errInvalidAccount
 			}
 
 			if err := writeSocks5AuthenticationResponse(writer, 0x01, 0x00); err != nil {
@@ -141,8 +146,7 @@ func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol
 			}
 			request.Command = protocol.RequestCommandUDP
 		case cmdTCPBind:
-			writeSocks5Response(writer, statusCmdNotSupport, net.AnyIP, net.Port(0))
-			return nil, newError("TCP bind is not supported.")
+			request.Command = requestCommandBind
 		default:
 			writeSocks5Response(writer, statusCmdNotSupport, net.AnyIP, net.Port(0))
 			return nil, newError("unknown command ", cmd)
@@ -159,17 +163,12 @@ func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol
 		request.Address = addr
 		request.Port = port
 
-		responseAddress := net.AnyIP
-		responsePort := net.Port(1717)
-		if request.Command == protocol.RequestCommandUDP {
-			addr := s.config.Address.AsAddress()
-			if addr == nil {
-				addr = net.LocalHostIP
-			}
-			responseAddress = addr
-			responsePort = s.port
+		// Replies of UDP and BIND requests are written by the caller, after the association or the binding is ready.
+		if request.Command != protocol.RequestCommandTCP {
+			return request, nil
 		}
-		if err := writeSocks5Response(writer, statusSuccess, responseAddress, responsePort); err != nil {
+
+		if err := writeSocks5Response(writer, statusSuccess, net.AnyIP, net.Port(1717)); err != nil {
 			return nil, err
 		}
 
@@ -390,44 +389,59 @@ func ClientHandshake(request *protocol.RequestHeader, reader io.Reader, writer i
 	b.Clear()
 
 	command := byte(cmdTCPConnect)
-	if request.Command == protocol.RequestCommandUDP {
+	switch request.Command {
+	case protocol.RequestCommandUDP:
 		command = byte(cmdUDPPort)
+	case requestCommandBind:
+		command = byte(cmdTCPBind)
 	}
 	b.AppendBytes(socks5Version, command, 0x00 /* reserved */)
-	if err := addrParser.WriteAddressPort(b, request.Address, request.Port); err != nil {
-		return nil, err
+	address, port := request.Address, request.Port
+	if request.Command == protocol.RequestCommandUDP {
+		// The address where UDP packets will be sent from is not known yet. Server binds the association to the
+		// address of this connection.
+		address, port = net.AnyIP, net.Port(0)
 	}
-
-	if _, err := writer.Write(b.Bytes()); err != nil {
+	if err := addrParser.WriteAddressPort(b, address, port); err != nil {
 		return nil, err
 	}
 
-	b.Clear()
-	if err := b.AppendSupplier(buf.ReadFullFrom(reader, 3)); err != nil {
+	if _, err := writer.Write(b.Bytes()); err != nil {
 		return nil, err
 	}
 
-	resp := b.Byte(1)
-	if resp != 0x00 {
-		return nil, newError("server rejects request: ", resp)
-	}
-
-	b.Clear()
-
-	address, port, err := addrParser.ReadAddressPort(b, reader)
+	address, port, err := readSocks5Response(reader)
 	if err != nil {
 		return nil, err
 	}
 
-	if request.Command == protocol.RequestCommandUDP {
-		udpRequest := &protocol.RequestHeader{
+	if request.Command == protocol.RequestCommandUDP || request.Command == requestCommandBind {
+		response := &protocol.RequestHeader{
 			Version: socks5Version,
-			Command: protocol.RequestCommandUDP,
+			Command: request.Command,
 			Address: address,
 			Port:    port,
 		}
-		return udpRequest, nil
+		return response, nil
 	}
 
 	return nil, nil
 }
+
+// readSocks5Response reads a reply from server, and returns the address in it.
+func readSocks5Response(reader io.Reader) (net.Address, net.Port, error) {
+	b := buf.New()
+	defer b.Release()
+
+	if err := b.AppendSupplier(buf.ReadFullFrom(reader, 3)); err != nil {
+		return nil, 0, err
+	}
+
+	resp := b.Byte(1)
+	if resp != statusSuccess {
+		return nil, 0, newError("server rejects request: ", resp)
+	}
+
+	b.Clear()
+	return addrParser.ReadAddressPort(b, reader)
+}
@@ -8,6 +8,7 @@ import (
 	"v2ray.com/core"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
@@ -20,15 +21,17 @@ import (
 
 // Server is a SOCKS 5 proxy server
 type Server struct {
-	config *ServerConfig
-	v      *core.Instance
+	config       *ServerConfig
+	v            *core.Instance
+	associations *udpAssociationManager
 }
 
 // NewServer creates a new Server object.
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	s := &Server{
-		config: config,
-		v:      core.MustFromContext(ctx),
+		config:       config,
+		v:            core.MustFromContext(ctx),
+		associations: newUDPAssociationManager(),
 	}
 	return s, nil
 }
@@ -78,7 +81,6 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 	}
 	session := &ServerSession{
 		config: s.config,
-		port:   inboundDest.Port,
 	}
 
 	request, err := session.Handshake(reader, conn)
@@ -91,6 +93,9 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 				Reason: err,
 			})
 		}
+		if errors.Cause(err) == errInvalidAccount {
+			proxy.ReportAuthFailure(ctx)
+		}
 		return newError("failed to read request").Base(err)
 	}
 
@@ -114,16 +119,151 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 	}
 
 	if request.Command == protocol.RequestCommandUDP {
-		return s.handleUDP(conn)
+		return s.handleUDP(ctx, request, inboundDest.Port, conn)
+	}
+
+	if request.Command == requestCommandBind {
+		return s.handleBind(ctx, request.Destination(), reader, conn, dispatcher)
 	}
 
 	return nil
 }
 
-func (*Server) handleUDP(c io.Reader) error {
-	// The TCP connection closes after this method returns. We need to wait until
-	// the client closes it.
-	return common.Error2(io.Copy(buf.DiscardBytes, c))
+// binder returns the Binder of the outbound handler that the given peer is routed to.
+func (s *Server) binder(ctx context.Context, peer net.Destination) (proxy.Binder, proxy.Dialer, error) {
+	ohm := s.v.OutboundHandlerManager()
+	handler := ohm.GetDefaultHandler()
+	if tag, err := s.v.Router().PickRoute(proxy.ContextWithTarget(ctx, peer)); err == nil {
+		if h := ohm.GetHandler(tag); h != nil {
+			handler = h
+		}
+	}
+	if handler == nil {
+		return nil, nil, newError("no outbound handler for ", peer)
+	}
+
+	if getter, ok := handler.(proxy.GetOutbound); ok {
+		binder, isBinder := getter.GetOutbound().(proxy.Binder)
+		dialer, isDialer := handler.(proxy.Dialer)
+		if isBinder && isDialer {
+			return binder, dialer, nil
+		}
+	}
+	return nil, nil, newError("outbound handler [", handler.Tag(), "] doesn't support TCP bind")
+}
+
+// handleBind waits for the peer of a BIND request. Traffic with the peer then goes through the dispatcher, so that
+// it is limited and accounted as traffic of a CONNECT request.
+func (s *Server) handleBind(ctx context.Context, peer net.Destination, reader *buf.BufferedReader, conn internet.Connection, dispatcher core.Dispatcher) error {
+	newError("TCP Bind request for ", peer).WithContext(ctx).WriteToLog()
+
+	binder, dialer, err := s.binder(ctx, peer)
+	if err != nil {
+		writeSocks5Response(conn, statusCmdNotSupport, net.AnyIP, net.Port(0))
+		return err
+	}
+
+	bindCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	signal.CancelAfterInactivity(bindCtx, cancel, s.policy().Timeouts.ConnectionIdle)
+
+	listener, err := binder.Bind(bindCtx, peer, dialer)
+	if err != nil {
+		writeSocks5Response(conn, statusGeneralFailure, net.AnyIP, net.Port(0))
+		return newError("failed to bind for ", peer).Base(err)
+	}
+	go func() {
+		<-bindCtx.Done()
+		listener.Close()
+	}()
+
+	bound := listener.Addr()
+	if bound.Address.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) && bound.Address.IP().IsUnspecified() {
+		if addr := s.config.Address.AsAddress(); addr != nil {
+			bound.Address = addr
+		} else {
+			bound.Address = net.DestinationFromAddr(conn.LocalAddr()).Address
+		}
+	}
+	if err := writeSocks5Response(conn, statusSuccess, bound.Address, bound.Port); err != nil {
+		return err
+	}
+
+	// The control connection is read from now on, so that the bind is cancelled if the client closes it while waiting
+	// for the peer. Data from the client is kept for the peer.
+	controlReader, controlWriter := pipe.New()
+	defer pipe.CloseError(controlReader)
+	go func() {
+		if err := buf.Copy(reader, controlWriter); err != nil {
+			controlWriter.CloseError()
+		} else {
+			controlWriter.Close()
+		}
+		cancel()
+	}()
+
+	peerConn, peerAddr, err := listener.Accept()
+	cancel()
+	if err != nil {
+		writeSocks5Response(conn, statusGeneralFailure, net.AnyIP, net.Port(0))
+		return newError("failed to accept peer ", peer).Base(err)
+	}
+	// The dispatcher closes the connection once the link ends, or it is closed here if the link can't be created.
+	defer peerConn.Close()
+
+	if source, ok := proxy.SourceFromContext(ctx); ok {
+		log.Record(&log.AccessMessage{
+			From:   source,
+			To:     peerAddr,
+			Status: log.AccessAccepted,
+			Reason: "",
+		})
+	}
+	if err := writeSocks5Response(conn, statusSuccess, peerAddr.Address, peerAddr.Port); err != nil {
+		return err
+	}
+
+	return s.transport(proxy.ContextWithBindConnection(ctx, peerConn), &buf.BufferedReader{Reader: controlReader}, conn, peerAddr, dispatcher)
+}
+
+func (s *Server) handleUDP(ctx context.Context, request *protocol.RequestHeader, port net.Port, conn internet.Connection) error {
+	// UDP packets are accepted only from the client that sends this request. The client may declare the address
+	// it sends packets from, or leave it unspecified.
+	client := net.UDPDestination(request.Address, request.Port)
+	if !request.Address.Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) || request.Address.IP().IsUnspecified() {
+		client.Address = net.DestinationFromAddr(conn.RemoteAddr()).Address
+	}
+	newError("UDP associate request from ", client).WithContext(ctx).WriteToLog()
+
+	ctx, cancel := context.WithCancel(ctx)
+	association := &udpAssociation{
+		client: client,
+		timer:  signal.CancelAfterInactivity(ctx, cancel, s.policy().Timeouts.ConnectionIdle),
+		done:   signal.NewDone(),
+	}
+	s.associations.add(association)
+	defer func() {
+		cancel()
+		s.associations.remove(association)
+		association.done.Close()
+	}()
+
+	addr := s.config.Address.AsAddress()
+	if addr == nil {
+		addr = net.LocalHostIP
+	}
+	if err := writeSocks5Response(conn, statusSuccess, addr, port); err != nil {
+		return err
+	}
+
+	// The association ends when the client closes the TCP connection, or when it has been idle for too long.
+	go func() {
+		io.Copy(buf.DiscardBytes, conn)
+		cancel()
+	}()
+	<-ctx.Done()
+
+	return nil
 }
 
 func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writer, dest net.Destination, dispatcher core.Dispatcher) error {
@@ -168,10 +308,59 @@ func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 }
 
 func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection, dispatcher core.Dispatcher) error {
-	udpServer := udp.NewDispatcher(dispatcher)
+	source, ok := proxy.SourceFromContext(ctx)
+	if !ok {
+		return newError("UDP source not specified")
+	}
+	association := s.associations.find(source)
+	if association == nil {
+		log.Record(&log.AccessMessage{
+			From:   source,
+			To:     "",
+			Status: log.AccessRejected,
+			Reason: "no UDP association",
+		})
+		return newError("rejecting UDP packets from ", source, " without association")
+	}
+	newError("client UDP connection from ", source).WithContext(ctx).WriteToLog()
 
-	if source, ok := proxy.SourceFromContext(ctx); ok {
-		newError("client UDP connection from ", source).WithContext(ctx).WriteToLog()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-association.done.Wait():
+			conn.Close()
+		case <-ctx.Done():
+		}
+	}()
+
+	// writeBack writes a response packet from the given address back to the client.
+	writeBack := func(from *protocol.RequestHeader, payload *buf.Buffer) {
+		if association.done.Done() {
+			payload.Release()
+			return
+		}
+		association.timer.Update()
+		newError("writing back UDP response with ", payload.Len(), " bytes").AtDebug().WithContext(ctx).WriteToLog()
+
+		udpMessage, err := EncodeUDPPacket(from, payload.Bytes())
+		payload.Release()
+		if err != nil {
+			newError("failed to write UDP response").AtWarning().Base(err).WithContext(ctx).WriteToLog()
+			return
+		}
+		defer udpMessage.Release()
+
+		conn.Write(udpMessage.Bytes())
+	}
+
+	udpServer := udp.NewDispatcher(dispatcher)
+	var fullConeServer *udp.FullConeDispatcher
+	if s.config.UdpFullCone {
+		fullConeServer = udp.NewFullConeDispatcher(dispatcher, func(source net.Destination, payload *buf.Buffer) {
+			writeBack(&protocol.RequestHeader{Address: source.Address, Port: source.Port}, payload)
+		})
+		defer fullConeServer.Close()
 	}
 
 	reader := buf.NewReader(conn)
@@ -195,6 +384,7 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 				continue
 			}
 
+			association.timer.Update()
 			newError("send packet to ", request.Destination(), " with ", payload.Len(), " bytes").AtDebug().WithContext(ctx).WriteToLog()
 			if source, ok := proxy.SourceFromContext(ctx); ok {
 				log.Record(&log.AccessMessage{
@@ -205,18 +395,12 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 				})
 			}
 
+			if fullConeServer != nil {
+				fullConeServer.Dispatch(ctx, request.Destination(), payload)
+				continue
+			}
 			udpServer.Dispatch(ctx, request.Destination(), payload, func(payload *buf.Buffer) {
-				newError("writing back UDP response with ", payload.Len(), " bytes").AtDebug().WithContext(ctx).WriteToLog()
-
-				udpMessage, err := EncodeUDPPacket(request, payload.Bytes())
-				payload.Release()
-
-				defer udpMessage.Release()
-				if err != nil {
-					newError("failed to write UDP response").AtWarning().Base(err).WithContext(ctx).WriteToLog()
-				}
-
-				conn.Write(udpMessage.Bytes())
+				writeBack(request, payload)
 			})
 		}
 	}
@@ -0,0 +1,139 @@
+package trojan
+
+import (
+	"context"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/retry"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/pipe"
+)
+
+// Client is an outbound handler for Trojan protocol.
+type Client struct {
+	serverPicker protocol.ServerPicker
+	v            *core.Instance
+}
+
+// NewClient creates a new Trojan client.
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	serverList := protocol.NewServerList()
+	for _, rec := range config.Server {
+		serverList.AddServer(protocol.NewServerSpecFromPB(*rec))
+	}
+	if serverList.Size() == 0 {
+		return nil, newError("0 server")
+	}
+	return &Client{
+		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
+		v:            core.MustFromContext(ctx),
+	}, nil
+}
+
+// Process implements proxy.Outbound.Process().
+func (c *Client) Process(ctx context.Context, link *core.Link, dialer proxy.Dialer) error {
+	destination, ok := proxy.TargetFromContext(ctx)
+	if !ok {
+		return newError("target not specified")
+	}
+
+	var server *protocol.ServerSpec
+	var conn internet.Connection
+
+	err := retry.ExponentialBackoff(5, 100).On(func() error {
+		server = c.serverPicker.PickServer()
+		rawConn, err := dialer.Dial(ctx, server.Destination())
+		if err != nil {
+			return err
+		}
+		conn = rawConn
+
+		return nil
+	})
+	if err != nil {
+		return newError("failed to find an available destination").AtWarning().Base(err)
+	}
+	newError("tunneling request to ", destination, " via ", server.Destination()).WithContext(ctx).WriteToLog()
+
+	defer conn.Close()
+
+	user := server.PickUser()
+	request := &protocol.RequestHeader{
+		User:    user,
+		Command: protocol.RequestCommandTCP,
+		Address: destination.Address,
+		Port:    destination.Port,
+	}
+	if destination.Network == net.Network_UDP {
+		request.Command = protocol.RequestCommandUDP
+	}
+
+	sessionPolicy := c.v.PolicyManager().ForLevel(user.Level)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
+		if err := WriteHeader(bufferedWriter, request); err != nil {
+			return newError("failed to write request").Base(err)
+		}
+
+		var bodyWriter buf.Writer = bufferedWriter
+		if request.Command == protocol.RequestCommandUDP {
+			bodyWriter = &PacketWriter{
+				Writer: bufferedWriter,
+				Target: destination,
+			}
+		}
+
+		// Send the header together with the first payload if it comes soon.
+		if reader, ok := link.Reader.(*pipe.Reader); ok {
+			firstPayload, err := reader.ReadMultiBufferWithTimeout(time.Millisecond * 100)
+			if err != nil && err != buf.ErrReadTimeout {
+				return newError("failed to get first payload").Base(err)
+			}
+			if !firstPayload.IsEmpty() {
+				if err := bodyWriter.WriteMultiBuffer(firstPayload); err != nil {
+					return newError("failed to write first payload").Base(err)
+				}
+			}
+		}
+
+		if err := bufferedWriter.SetBuffered(false); err != nil {
+			return err
+		}
+
+		return buf.Copy(link.Reader, bodyWriter, buf.UpdateActivity(timer))
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		var reader buf.Reader = buf.NewReader(conn)
+		if request.Command == protocol.RequestCommandUDP {
+			reader = &PacketReader{Reader: conn}
+		}
+		return buf.Copy(reader, link.Writer, buf.UpdateActivity(timer))
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestDone, responseDone); err != nil {
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ClientConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewClient(ctx, config.(*ClientConfig))
+	}))
+}
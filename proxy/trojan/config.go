@@ -0,0 +1,42 @@
+package trojan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"v2ray.com/core/common/protocol"
+)
+
+// MemoryAccount is an account type converted from Account.
+type MemoryAccount struct {
+	Password string
+	// Key is the hex encoded SHA224 hash of the password, which is sent at the beginning of requests.
+	Key []byte
+}
+
+// Equals implements protocol.Account.Equals().
+func (a *MemoryAccount) Equals(another protocol.Account) bool {
+	if account, ok := another.(*MemoryAccount); ok {
+		return bytes.Equal(a.Key, account.Key)
+	}
+	return false
+}
+
+// AsAccount implements protocol.AsAccount.
+func (a *Account) AsAccount() (protocol.Account, error) {
+	if len(a.Password) == 0 {
+		return nil, newError("password is not specified")
+	}
+	return &MemoryAccount{
+		Password: a.Password,
+		Key:      hexSHA224(a.Password),
+	}, nil
+}
+
+func hexSHA224(password string) []byte {
+	hash := sha256.Sum224([]byte(password))
+	key := make([]byte, hex.EncodedLen(len(hash)))
+	hex.Encode(key, hash[:])
+	return key
+}
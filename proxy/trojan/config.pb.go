@@ -0,0 +1,95 @@
+package trojan
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_protocol "v2ray.com/core/common/protocol"
+import v2ray_core_common_protocol1 "v2ray.com/core/common/protocol"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Account struct {
+	Password string `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *Account) Reset()                    { *m = Account{} }
+func (m *Account) String() string            { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()               {}
+func (*Account) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Account) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type ServerConfig struct {
+	Users []*v2ray_core_common_protocol.User `protobuf:"bytes,1,rep,name=users" json:"users,omitempty"`
+}
+
+func (m *ServerConfig) Reset()                    { *m = ServerConfig{} }
+func (m *ServerConfig) String() string            { return proto.CompactTextString(m) }
+func (*ServerConfig) ProtoMessage()               {}
+func (*ServerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ServerConfig) GetUsers() []*v2ray_core_common_protocol.User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+type ClientConfig struct {
+	Server []*v2ray_core_common_protocol1.ServerEndpoint `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
+}
+
+func (m *ClientConfig) Reset()                    { *m = ClientConfig{} }
+func (m *ClientConfig) String() string            { return proto.CompactTextString(m) }
+func (*ClientConfig) ProtoMessage()               {}
+func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ClientConfig) GetServer() []*v2ray_core_common_protocol1.ServerEndpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.trojan.Account")
+	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.trojan.ServerConfig")
+	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.trojan.ClientConfig")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/proxy/trojan/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 260 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0xa9, 0x62, 0xd5, 0xb8, 0x53, 0x2f, 0x1b, 0xf5, 0x52, 0x0a, 0x42, 0xf5, 0xf0, 0x46,
+	0x2a, 0x78, 0xdf, 0x8a, 0x9e, 0x47, 0xfd, 0x73, 0xf0, 0x22, 0xf5, 0x5d, 0x94, 0xca, 0x9a, 0x37,
+	0xbc, 0xc9, 0xa6, 0xfd, 0x4a, 0x7e, 0x4a, 0x59, 0xd2, 0x89, 0x08, 0xea, 0x2d, 0x21, 0xbf, 0xe7,
+	0xf7, 0x3c, 0x44, 0x14, 0xeb, 0x92, 0x9b, 0x1e, 0x90, 0x3a, 0x89, 0xc4, 0x4a, 0x1a, 0xa6, 0xf7,
+	0x5e, 0x3a, 0xa6, 0xd7, 0x46, 0x4b, 0x24, 0xfd, 0xdc, 0xbe, 0x80, 0x61, 0x72, 0x94, 0x8c, 0xb7,
+	0x24, 0x2b, 0xf0, 0x14, 0x04, 0x2a, 0x3d, 0xfd, 0xa1, 0x40, 0xea, 0x3a, 0xd2, 0xd2, 0xa7, 0x90,
+	0x96, 0x72, 0x65, 0x15, 0x07, 0x47, 0x7a, 0xfe, 0x0f, 0x6a, 0x15, 0xaf, 0x15, 0x3f, 0x5a, 0xa3,
+	0x30, 0x24, 0xf2, 0x13, 0xb1, 0x3f, 0x45, 0xa4, 0x95, 0x76, 0x49, 0x2a, 0x0e, 0x4c, 0x63, 0xed,
+	0x1b, 0xf1, 0x62, 0x12, 0x65, 0x51, 0x71, 0x58, 0x7f, 0xdd, 0xf3, 0x6b, 0x31, 0xba, 0xf1, 0xd9,
+	0xca, 0x4f, 0x4e, 0x2e, 0xc5, 0xde, 0xa6, 0xd6, 0x4e, 0xa2, 0x6c, 0xb7, 0x38, 0x2a, 0x33, 0xf8,
+	0x36, 0x3e, 0x94, 0xc2, 0xb6, 0x14, 0xee, 0xac, 0xe2, 0x3a, 0xe0, 0x79, 0x2d, 0x46, 0xd5, 0xb2,
+	0x55, 0xda, 0x0d, 0x9e, 0x99, 0x88, 0xc3, 0xa6, 0x41, 0x74, 0xf6, 0x97, 0x28, 0x2c, 0xb8, 0xd2,
+	0x0b, 0x43, 0xad, 0x76, 0xf5, 0x90, 0x9c, 0x4d, 0xc5, 0x31, 0x52, 0x07, 0xbf, 0x7c, 0xdf, 0x3c,
+	0x7a, 0x88, 0xc3, 0xe9, 0x63, 0x67, 0x7c, 0x5f, 0xd6, 0x4d, 0x0f, 0xd5, 0x86, 0x99, 0x7b, 0xe6,
+	0xd6, 0xbf, 0x3c, 0xc5, 0xbe, 0xe3, 0xe2, 0x33, 0x00, 0x00, 0xff, 0xff, 0x10, 0x95, 0x12, 0x7c,
+	0xae, 0x01, 0x00, 0x00,
+}
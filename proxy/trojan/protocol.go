@@ -0,0 +1,209 @@
+package trojan
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+)
+
+const (
+	commandTCP byte = 1
+	commandUDP byte = 3
+
+	keySize = 56
+
+	// maxPacketSize is the max size of UDP payload in a packet.
+	maxPacketSize = 8192
+)
+
+var (
+	crlf = []byte{'\r', '\n'}
+
+	addrParser = protocol.NewAddressParser(
+		protocol.AddressFamilyByte(0x01, net.AddressFamilyIPv4),
+		protocol.AddressFamilyByte(0x04, net.AddressFamilyIPv6),
+		protocol.AddressFamilyByte(0x03, net.AddressFamilyDomain),
+	)
+)
+
+// WriteHeader writes the header of the given request, which is the key of the user, the command and the
+// destination.
+func WriteHeader(writer io.Writer, request *protocol.RequestHeader) error {
+	rawAccount, err := request.User.GetTypedAccount()
+	if err != nil {
+		return newError("failed to get user account").Base(err)
+	}
+	account := rawAccount.(*MemoryAccount)
+
+	command := commandTCP
+	if request.Command == protocol.RequestCommandUDP {
+		command = commandUDP
+	}
+
+	buffer := buf.New()
+	defer buffer.Release()
+
+	common.Must2(buffer.Write(account.Key))
+	common.Must2(buffer.Write(crlf))
+	common.Must2(buffer.AppendBytes(command))
+	if err := addrParser.WriteAddressPort(buffer, request.Address, request.Port); err != nil {
+		return newError("failed to write address and port").Base(err)
+	}
+	common.Must2(buffer.Write(crlf))
+
+	return common.Error2(writer.Write(buffer.Bytes()))
+}
+
+// ReadHeader reads a request header, and identifies its user by the given validator.
+func ReadHeader(validator *Validator, reader io.Reader) (*protocol.RequestHeader, error) {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	if err := buffer.AppendSupplier(buf.ReadFullFrom(reader, keySize+2)); err != nil {
+		return nil, newError("failed to read user key").Base(err)
+	}
+
+	user := validator.Get(buffer.BytesTo(keySize))
+	if user == nil {
+		return nil, newError("invalid user")
+	}
+	if buffer.Byte(keySize) != '\r' || buffer.Byte(keySize+1) != '\n' {
+		return nil, newError("invalid header")
+	}
+
+	if err := buffer.Reset(buf.ReadFullFrom(reader, 1)); err != nil {
+		return nil, newError("failed to read command").Base(err)
+	}
+
+	request := &protocol.RequestHeader{
+		Version: 0,
+		User:    user,
+	}
+	switch buffer.Byte(0) {
+	case commandTCP:
+		request.Command = protocol.RequestCommandTCP
+	case commandUDP:
+		request.Command = protocol.RequestCommandUDP
+	default:
+		return nil, newError("unknown command: ", buffer.Byte(0))
+	}
+
+	buffer.Clear()
+	addr, port, err := addrParser.ReadAddressPort(buffer, reader)
+	if err != nil {
+		return nil, newError("failed to read address and port").Base(err)
+	}
+	request.Address = addr
+	request.Port = port
+
+	if err := buffer.Reset(buf.ReadFullFrom(reader, 2)); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+	if buffer.Byte(0) != '\r' || buffer.Byte(1) != '\n' {
+		return nil, newError("invalid header")
+	}
+
+	return request, nil
+}
+
+// PacketWriter writes UDP packets of a UDP associate request.
+type PacketWriter struct {
+	sync.Mutex
+	Writer io.Writer
+	// Target is the destination of packets written by WriteMultiBuffer().
+	Target net.Destination
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer mb.Release()
+
+	for _, b := range mb {
+		if err := w.WritePacket(b.Bytes(), w.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePacket writes a packet with the given payload and destination.
+func (w *PacketWriter) WritePacket(payload []byte, dest net.Destination) error {
+	buffer := buf.NewSize(int32(len(payload)) + 512)
+	defer buffer.Release()
+
+	for len(payload) > 0 {
+		n := len(payload)
+		if n > maxPacketSize {
+			n = maxPacketSize
+		}
+
+		buffer.Clear()
+		if err := addrParser.WriteAddressPort(buffer, dest.Address, dest.Port); err != nil {
+			return newError("failed to write address and port").Base(err)
+		}
+		common.Must(buffer.AppendSupplier(func(b []byte) (int, error) {
+			binary.BigEndian.PutUint16(b, uint16(n))
+			return 2, nil
+		}))
+		common.Must2(buffer.Write(crlf))
+		common.Must2(buffer.Write(payload[:n]))
+		payload = payload[n:]
+
+		w.Lock()
+		_, err := w.Writer.Write(buffer.Bytes())
+		w.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PacketReader reads UDP packets of a UDP associate request.
+type PacketReader struct {
+	Reader io.Reader
+}
+
+// ReadMultiBuffer implements buf.Reader. Destinations of packets are dropped.
+func (r *PacketReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	_, payload, err := r.ReadPacket()
+	if err != nil {
+		return nil, err
+	}
+	return buf.NewMultiBufferValue(payload), nil
+}
+
+// ReadPacket reads a packet, and returns its destination and payload.
+func (r *PacketReader) ReadPacket() (net.Destination, *buf.Buffer, error) {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	addr, port, err := addrParser.ReadAddressPort(buffer, r.Reader)
+	if err != nil {
+		return net.Destination{}, nil, newError("failed to read address and port").Base(err)
+	}
+
+	if err := buffer.Reset(buf.ReadFullFrom(r.Reader, 4)); err != nil {
+		return net.Destination{}, nil, newError("failed to read packet length").Base(err)
+	}
+	length := int32(binary.BigEndian.Uint16(buffer.BytesTo(2)))
+	if buffer.Byte(2) != '\r' || buffer.Byte(3) != '\n' {
+		return net.Destination{}, nil, newError("invalid packet")
+	}
+	if length > maxPacketSize {
+		return net.Destination{}, nil, newError("packet too large: ", length)
+	}
+
+	payload := buf.NewSize(length)
+	if err := payload.AppendSupplier(buf.ReadFullFrom(r.Reader, length)); err != nil {
+		payload.Release()
+		return net.Destination{}, nil, newError("failed to read payload").Base(err)
+	}
+
+	return net.UDPDestination(addr, port), payload, nil
+}
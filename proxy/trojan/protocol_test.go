@@ -0,0 +1,134 @@
+package trojan_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/core/proxy/trojan"
+	. "v2ray.com/ext/assert"
+)
+
+func newUser(email string, password string) *protocol.User {
+	return &protocol.User{
+		Email: email,
+		Account: serial.ToTypedMessage(&Account{
+			Password: password,
+		}),
+	}
+}
+
+func TestHeader(t *testing.T) {
+	assert := With(t)
+
+	user := newUser("love@v2ray.com", "password")
+	validator := NewValidator()
+	common.Must(validator.Add(user))
+
+	cases := []*protocol.RequestHeader{
+		{
+			User:    user,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("v2ray.com"),
+			Port:    443,
+		},
+		{
+			User:    user,
+			Command: protocol.RequestCommandUDP,
+			Address: net.LocalHostIPv6,
+			Port:    53,
+		},
+	}
+
+	for _, request := range cases {
+		buffer := buf.New()
+		common.Must(WriteHeader(buffer, request))
+
+		hash := sha256.Sum224([]byte("password"))
+		assert(buffer.BytesTo(56), Equals, []byte(hex.EncodeToString(hash[:])))
+		assert(buffer.BytesRange(56, 58), Equals, []byte("\r\n"))
+
+		decoded, err := ReadHeader(validator, buffer)
+		assert(err, IsNil)
+		assert(decoded.User.Email, Equals, user.Email)
+		assert(decoded.Command, Equals, request.Command)
+		assert(decoded.Address, Equals, request.Address)
+		assert(decoded.Port, Equals, request.Port)
+		assert(buffer.IsEmpty(), IsTrue)
+		buffer.Release()
+	}
+}
+
+func TestInvalidPassword(t *testing.T) {
+	assert := With(t)
+
+	validator := NewValidator()
+	common.Must(validator.Add(newUser("love@v2ray.com", "password")))
+
+	buffer := buf.New()
+	defer buffer.Release()
+	common.Must(WriteHeader(buffer, &protocol.RequestHeader{
+		User:    newUser("love@v2ray.com", "wrong-password"),
+		Command: protocol.RequestCommandTCP,
+		Address: net.LocalHostIP,
+		Port:    80,
+	}))
+
+	_, err := ReadHeader(validator, buffer)
+	assert(err, IsNotNil)
+}
+
+func TestPacket(t *testing.T) {
+	assert := With(t)
+
+	cache := buf.NewSize(32 * 1024)
+	defer cache.Release()
+
+	writer := &PacketWriter{
+		Writer: cache,
+		Target: net.UDPDestination(net.DomainAddress("v2ray.com"), 53),
+	}
+	reader := &PacketReader{Reader: cache}
+
+	b := buf.New()
+	common.Must2(b.Write([]byte("test payload")))
+	assert(writer.WriteMultiBuffer(buf.NewMultiBufferValue(b)), IsNil)
+
+	dest := net.UDPDestination(net.LocalHostIP, 1234)
+	large := make([]byte, 10000)
+	assert(writer.WritePacket(large, dest), IsNil)
+
+	decodedDest, payload, err := reader.ReadPacket()
+	assert(err, IsNil)
+	assert(decodedDest, Equals, writer.Target)
+	assert(payload.String(), Equals, "test payload")
+
+	// Payload larger than 8192 bytes is split into multiple packets.
+	decodedDest, payload, err = reader.ReadPacket()
+	assert(err, IsNil)
+	assert(decodedDest, Equals, dest)
+	assert(payload.Len(), Equals, int32(8192))
+
+	mb, err := reader.ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(mb.Len(), Equals, int32(10000-8192))
+}
+
+func TestValidator(t *testing.T) {
+	assert := With(t)
+
+	validator := NewValidator()
+	common.Must(validator.Add(newUser("a@v2ray.com", "a")))
+	assert(validator.Add(newUser("b@v2ray.com", "a")), IsNotNil)
+	assert(validator.Add(newUser("A@v2ray.com", "b")), IsNotNil)
+	common.Must(validator.Add(newUser("b@v2ray.com", "b")))
+
+	assert(validator.Remove("a@v2ray.com"), IsTrue)
+	assert(validator.Remove("a@v2ray.com"), IsFalse)
+	assert(validator.Remove("B@v2ray.com"), IsTrue)
+}
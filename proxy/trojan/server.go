@@ -0,0 +1,182 @@
+package trojan
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
+	"v2ray.com/core/transport/pipe"
+)
+
+// Server is an inbound handler for Trojan protocol.
+type Server struct {
+	validator *Validator
+	v         *core.Instance
+}
+
+// NewServer creates a new Trojan server.
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	validator := NewValidator()
+	for _, user := range config.Users {
+		if err := validator.Add(user); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
+	}
+
+	return &Server{
+		validator: validator,
+		v:         core.MustFromContext(ctx),
+	}, nil
+}
+
+// AddUser implements proxy.UserManager.AddUser().
+func (s *Server) AddUser(ctx context.Context, user *protocol.User) error {
+	return s.validator.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.RemoveUser().
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	if len(email) == 0 {
+		return newError("Email must not be empty.")
+	}
+	if !s.validator.Remove(email) {
+		return newError("User ", email, " not found.")
+	}
+	return nil
+}
+
+// Network implements proxy.Inbound.Network().
+func (s *Server) Network() net.NetworkList {
+	return net.NetworkList{
+		Network: []net.Network{net.Network_TCP},
+	}
+}
+
+// Process implements proxy.Inbound.Process().
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher core.Dispatcher) error {
+	if err := conn.SetReadDeadline(time.Now().Add(s.v.PolicyManager().ForLevel(0).Timeouts.Handshake)); err != nil {
+		return newError("unable to set read deadline").Base(err).AtWarning()
+	}
+
+	reader := &buf.BufferedReader{Reader: buf.NewReader(conn)}
+	request, err := ReadHeader(s.validator, reader)
+	if err != nil {
+		if errors.Cause(err) != io.EOF {
+			log.Record(&log.AccessMessage{
+				From:   conn.RemoteAddr(),
+				To:     "",
+				Status: log.AccessRejected,
+				Reason: err,
+			})
+			proxy.ReportAuthFailure(ctx)
+		}
+		return newError("failed to read request from: ", conn.RemoteAddr()).Base(err)
+	}
+
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		newError("unable to set back read deadline").Base(err).WithContext(ctx).WriteToLog()
+	}
+
+	ctx = protocol.ContextWithUser(ctx, request.User)
+
+	if request.Command == protocol.RequestCommandUDP {
+		return s.handleUDPAssociate(ctx, request, reader, conn, dispatcher)
+	}
+	return s.handleConnection(ctx, request, reader, conn, dispatcher)
+}
+
+func (s *Server) handleConnection(ctx context.Context, request *protocol.RequestHeader, reader *buf.BufferedReader, conn internet.Connection, dispatcher core.Dispatcher) error {
+	dest := request.Destination()
+	log.Record(&log.AccessMessage{
+		From:   conn.RemoteAddr(),
+		To:     dest,
+		Status: log.AccessAccepted,
+		Reason: "",
+	})
+	newError("tunnelling request to ", dest).WithContext(ctx).WriteToLog()
+
+	sessionPolicy := s.v.PolicyManager().ForLevel(request.User.Level)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+	link, err := dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		return newError("failed to dispatch request to ", dest).Base(err)
+	}
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+		defer common.Close(link.Writer)
+
+		reader.Direct = true
+		if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		if err := buf.Copy(link.Reader, buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP response").Base(err)
+		}
+		return nil
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestDone, responseDone); err != nil {
+		pipe.CloseError(link.Reader)
+		pipe.CloseError(link.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+func (s *Server) handleUDPAssociate(ctx context.Context, request *protocol.RequestHeader, reader *buf.BufferedReader, conn internet.Connection, dispatcher core.Dispatcher) error {
+	log.Record(&log.AccessMessage{
+		From:   conn.RemoteAddr(),
+		To:     request.Destination(),
+		Status: log.AccessAccepted,
+		Reason: "",
+	})
+
+	udpServer := udp.NewDispatcher(dispatcher)
+	writer := &PacketWriter{Writer: conn}
+	packetReader := &PacketReader{Reader: reader}
+
+	for {
+		dest, payload, err := packetReader.ReadPacket()
+		if err != nil {
+			if errors.Cause(err) != io.EOF {
+				return newError("failed to read UDP packet").Base(err)
+			}
+			return nil
+		}
+
+		newError("tunnelling UDP packet to ", dest).WithContext(ctx).WriteToLog()
+		udpServer.Dispatch(ctx, dest, payload, func(payload *buf.Buffer) {
+			defer payload.Release()
+
+			if err := writer.WritePacket(payload.Bytes(), dest); err != nil {
+				newError("failed to write UDP response").Base(err).AtWarning().WithContext(ctx).WriteToLog()
+			}
+		})
+	}
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewServer(ctx, config.(*ServerConfig))
+	}))
+}
@@ -0,0 +1,7 @@
+// Package trojan implements the Trojan protocol.
+//
+// Trojan client and server are implemented as outbound and inbound respectively in V2Ray's term. Trojan relies on
+// the transport, usually TLS, for encryption. Users are identified by the SHA224 hash of their passwords.
+package trojan
+
+//go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg trojan -path Proxy,Trojan
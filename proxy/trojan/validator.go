@@ -0,0 +1,71 @@
+package trojan
+
+import (
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/protocol"
+)
+
+// Validator holds the users of a Trojan server, and identifies users by their keys.
+type Validator struct {
+	sync.RWMutex
+	users map[string]*protocol.User
+}
+
+// NewValidator creates a new empty Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		users: make(map[string]*protocol.User),
+	}
+}
+
+// Add adds a user.
+func (v *Validator) Add(u *protocol.User) error {
+	rawAccount, err := u.GetTypedAccount()
+	if err != nil {
+		return newError("failed to get user account").Base(err)
+	}
+	account, ok := rawAccount.(*MemoryAccount)
+	if !ok {
+		return newError("not a Trojan account")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if _, found := v.users[string(account.Key)]; found {
+		return newError("user with the same password already exists")
+	}
+	if len(u.Email) > 0 {
+		for _, existing := range v.users {
+			if strings.EqualFold(existing.Email, u.Email) {
+				return newError("user ", u.Email, " already exists")
+			}
+		}
+	}
+	v.users[string(account.Key)] = u
+	return nil
+}
+
+// Get returns the user with the given key, or nil if not found.
+func (v *Validator) Get(key []byte) *protocol.User {
+	v.RLock()
+	defer v.RUnlock()
+
+	return v.users[string(key)]
+}
+
+// Remove removes the user with the given email.
+func (v *Validator) Remove(email string) bool {
+	v.Lock()
+	defer v.Unlock()
+
+	for key, u := range v.users {
+		if strings.EqualFold(u.Email, email) {
+			delete(v.users, key)
+			return true
+		}
+	}
+	return false
+}
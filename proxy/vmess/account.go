@@ -7,9 +7,10 @@ import (
 )
 
 type InternalAccount struct {
-	ID       *protocol.ID
-	AlterIDs []*protocol.ID
-	Security protocol.SecurityType
+	ID         *protocol.ID
+	AlterIDs   []*protocol.ID
+	Security   protocol.SecurityType
+	AEADHeader bool
 }
 
 func (a *InternalAccount) AnyValidID() *protocol.ID {
@@ -35,8 +36,9 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	}
 	protoID := protocol.NewID(id)
 	return &InternalAccount{
-		ID:       protoID,
-		AlterIDs: protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
-		Security: a.SecuritySettings.GetSecurityType(),
+		ID:         protoID,
+		AlterIDs:   protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
+		Security:   a.SecuritySettings.GetSecurityType(),
+		AEADHeader: a.AeadHeader,
 	}, nil
 }
@@ -0,0 +1,76 @@
+package aead_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/proxy/vmess/aead"
+	. "v2ray.com/ext/assert"
+)
+
+func TestAuthID(t *testing.T) {
+	assert := With(t)
+
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+
+	authID := CreateAuthID(key, 1234567890)
+	ts, ok := DecodeAuthID(NewAuthIDCipher(key), authID[:])
+	assert(ok, IsTrue)
+	assert(ts, Equals, int64(1234567890))
+
+	otherKey := make([]byte, 16)
+	common.Must2(rand.Read(otherKey))
+	_, ok = DecodeAuthID(NewAuthIDCipher(otherKey), authID[:])
+	assert(ok, IsFalse)
+}
+
+func TestKDF(t *testing.T) {
+	assert := With(t)
+
+	key := []byte("key")
+	assert(len(KDF(key, []byte("a"), []byte("b"))), Equals, 32)
+	assert(KDF16(key, []byte("a")), Equals, KDF(key, []byte("a"))[:16])
+	assert(bytes.Equal(KDF(key, []byte("a")), KDF(key, []byte("b"))), IsFalse)
+	assert(bytes.Equal(KDF(key, []byte("a"), []byte("b")), KDF(key, []byte("ab"))), IsFalse)
+}
+
+func TestHeaderSealAndOpen(t *testing.T) {
+	assert := With(t)
+
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+	header := []byte("test header")
+
+	authID := CreateAuthID(key, 1234567890)
+	sealed := SealHeader(key, authID, header)
+	assert(sealed[:16], Equals, authID[:])
+
+	opened, err := OpenHeader(key, authID, bytes.NewReader(sealed[16:]))
+	assert(err, IsNil)
+	assert(opened, Equals, header)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = OpenHeader(key, authID, bytes.NewReader(sealed[16:]))
+	assert(err, IsNotNil)
+}
+
+func TestResponseHeaderSealAndOpen(t *testing.T) {
+	assert := With(t)
+
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+	iv := make([]byte, 16)
+	common.Must2(rand.Read(iv))
+	header := []byte{1, 0, 0, 0}
+
+	sealed := SealResponseHeader(key, iv, header)
+	opened, err := OpenResponseHeader(key, iv, bytes.NewReader(sealed))
+	assert(err, IsNil)
+	assert(opened, Equals, header)
+
+	_, err = OpenResponseHeader(iv, key, bytes.NewReader(sealed))
+	assert(err, IsNotNil)
+}
@@ -0,0 +1,40 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"hash/crc32"
+
+	"v2ray.com/core/common"
+)
+
+// NewAuthIDCipher creates the AES block to encrypt and decrypt auth IDs of the user with the given command key.
+func NewAuthIDCipher(cmdKey []byte) cipher.Block {
+	block, err := aes.NewCipher(KDF16(cmdKey, []byte(kdfSaltConstAuthIDEncryptionKey)))
+	common.Must(err)
+	return block
+}
+
+// CreateAuthID returns an auth ID for the given time. It is the encryption of the time, 4 random bytes and a CRC32
+// checksum of both.
+func CreateAuthID(cmdKey []byte, time int64) [16]byte {
+	var authID [16]byte
+	binary.BigEndian.PutUint64(authID[:8], uint64(time))
+	common.Must2(rand.Read(authID[8:12]))
+	binary.BigEndian.PutUint32(authID[12:], crc32.ChecksumIEEE(authID[:12]))
+	block := NewAuthIDCipher(cmdKey)
+	block.Encrypt(authID[:], authID[:])
+	return authID
+}
+
+// DecodeAuthID decrypts the given auth ID with the block of a user, and returns its time if the checksum matches.
+func DecodeAuthID(block cipher.Block, authID []byte) (int64, bool) {
+	var plain [16]byte
+	block.Decrypt(plain[:], authID)
+	if binary.BigEndian.Uint32(plain[12:]) != crc32.ChecksumIEEE(plain[:12]) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(plain[:8])), true
+}
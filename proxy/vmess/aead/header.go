@@ -0,0 +1,127 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"v2ray.com/core/common"
+)
+
+const (
+	lengthSize = 2
+	nonceSize  = 8
+	gcmNonce   = 12
+	gcmTagSize = 16
+)
+
+func newGCM(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	common.Must(err)
+	aead, err := cipher.NewGCM(block)
+	common.Must(err)
+	return aead
+}
+
+// SealHeader seals a request header with the command key of a user. The result is the auth ID, the sealed length of
+// the header, a random connection nonce and the sealed header, in order.
+func SealHeader(cmdKey []byte, authID [16]byte, header []byte) []byte {
+	var connectionNonce [nonceSize]byte
+	common.Must2(rand.Read(connectionNonce[:]))
+
+	var length [lengthSize]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(header)))
+
+	lengthKey := KDF16(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadLengthAEADKey), authID[:], connectionNonce[:])
+	lengthNonce := KDF(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadLengthAEADIV), authID[:], connectionNonce[:])[:gcmNonce]
+	payloadKey := KDF16(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadAEADKey), authID[:], connectionNonce[:])
+	payloadNonce := KDF(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadAEADIV), authID[:], connectionNonce[:])[:gcmNonce]
+
+	output := make([]byte, 0, 16+lengthSize+gcmTagSize+nonceSize+len(header)+gcmTagSize)
+	output = append(output, authID[:]...)
+	output = newGCM(lengthKey).Seal(output, lengthNonce, length[:], authID[:])
+	output = append(output, connectionNonce[:]...)
+	output = newGCM(payloadKey).Seal(output, payloadNonce, header, authID[:])
+	return output
+}
+
+// OpenHeader reads a request header sealed by SealHeader from the given reader. The auth ID must have been read
+// from the reader already.
+func OpenHeader(cmdKey []byte, authID [16]byte, reader io.Reader) ([]byte, error) {
+	var sealedLength [lengthSize + gcmTagSize]byte
+	if _, err := io.ReadFull(reader, sealedLength[:]); err != nil {
+		return nil, newError("failed to read header length").Base(err)
+	}
+
+	var connectionNonce [nonceSize]byte
+	if _, err := io.ReadFull(reader, connectionNonce[:]); err != nil {
+		return nil, newError("failed to read connection nonce").Base(err)
+	}
+
+	lengthKey := KDF16(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadLengthAEADKey), authID[:], connectionNonce[:])
+	lengthNonce := KDF(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadLengthAEADIV), authID[:], connectionNonce[:])[:gcmNonce]
+	length, err := newGCM(lengthKey).Open(nil, lengthNonce, sealedLength[:], authID[:])
+	if err != nil {
+		return nil, newError("failed to open header length").Base(err)
+	}
+
+	sealedHeader := make([]byte, int(binary.BigEndian.Uint16(length))+gcmTagSize)
+	if _, err := io.ReadFull(reader, sealedHeader); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+
+	payloadKey := KDF16(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadAEADKey), authID[:], connectionNonce[:])
+	payloadNonce := KDF(cmdKey, []byte(kdfSaltConstVMessHeaderPayloadAEADIV), authID[:], connectionNonce[:])[:gcmNonce]
+	header, err := newGCM(payloadKey).Open(sealedHeader[:0], payloadNonce, sealedHeader, authID[:])
+	if err != nil {
+		return nil, newError("failed to open header").Base(err)
+	}
+	return header, nil
+}
+
+// SealResponseHeader seals a response header with the response body key and IV. The result is the sealed length of
+// the header followed by the sealed header.
+func SealResponseHeader(key []byte, iv []byte, header []byte) []byte {
+	var length [lengthSize]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(header)))
+
+	lengthKey := KDF16(key, []byte(kdfSaltConstAEADRespHeaderLenKey))
+	lengthNonce := KDF(iv, []byte(kdfSaltConstAEADRespHeaderLenIV))[:gcmNonce]
+	payloadKey := KDF16(key, []byte(kdfSaltConstAEADRespHeaderPayloadKey))
+	payloadNonce := KDF(iv, []byte(kdfSaltConstAEADRespHeaderPayloadIV))[:gcmNonce]
+
+	output := make([]byte, 0, lengthSize+gcmTagSize+len(header)+gcmTagSize)
+	output = newGCM(lengthKey).Seal(output, lengthNonce, length[:], nil)
+	output = newGCM(payloadKey).Seal(output, payloadNonce, header, nil)
+	return output
+}
+
+// OpenResponseHeader reads a response header sealed by SealResponseHeader from the given reader.
+func OpenResponseHeader(key []byte, iv []byte, reader io.Reader) ([]byte, error) {
+	var sealedLength [lengthSize + gcmTagSize]byte
+	if _, err := io.ReadFull(reader, sealedLength[:]); err != nil {
+		return nil, newError("failed to read response header length").Base(err)
+	}
+
+	lengthKey := KDF16(key, []byte(kdfSaltConstAEADRespHeaderLenKey))
+	lengthNonce := KDF(iv, []byte(kdfSaltConstAEADRespHeaderLenIV))[:gcmNonce]
+	length, err := newGCM(lengthKey).Open(nil, lengthNonce, sealedLength[:], nil)
+	if err != nil {
+		return nil, newError("failed to open response header length").Base(err)
+	}
+
+	sealedHeader := make([]byte, int(binary.BigEndian.Uint16(length))+gcmTagSize)
+	if _, err := io.ReadFull(reader, sealedHeader); err != nil {
+		return nil, newError("failed to read response header").Base(err)
+	}
+
+	payloadKey := KDF16(key, []byte(kdfSaltConstAEADRespHeaderPayloadKey))
+	payloadNonce := KDF(iv, []byte(kdfSaltConstAEADRespHeaderPayloadIV))[:gcmNonce]
+	header, err := newGCM(payloadKey).Open(sealedHeader[:0], payloadNonce, sealedHeader, nil)
+	if err != nil {
+		return nil, newError("failed to open response header").Base(err)
+	}
+	return header, nil
+}
@@ -0,0 +1,38 @@
+package aead
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+
+	"v2ray.com/core/common"
+)
+
+// hmacCreator creates nested HMACs. Each level uses the HMAC of its parent as the underlying hash function.
+type hmacCreator struct {
+	parent *hmacCreator
+	value  []byte
+}
+
+func (h *hmacCreator) Create() hash.Hash {
+	if h.parent == nil {
+		return hmac.New(sha256.New, h.value)
+	}
+	return hmac.New(h.parent.Create, h.value)
+}
+
+// KDF derives a 32-byte key from the given key along the given path.
+func KDF(key []byte, path ...[]byte) []byte {
+	creator := &hmacCreator{value: []byte(kdfSaltConstVMessAEADKDF)}
+	for _, v := range path {
+		creator = &hmacCreator{parent: creator, value: v}
+	}
+	h := creator.Create()
+	common.Must2(h.Write(key))
+	return h.Sum(nil)
+}
+
+// KDF16 derives a 16-byte key from the given key along the given path.
+func KDF16(key []byte, path ...[]byte) []byte {
+	return KDF(key, path...)[:16]
+}
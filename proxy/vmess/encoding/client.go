@@ -1,10 +1,12 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
 	"hash/fnv"
 	"io"
 
@@ -18,6 +20,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 func hashTimestamp(t protocol.Timestamp) []byte {
@@ -31,6 +34,7 @@ func hashTimestamp(t protocol.Timestamp) []byte {
 
 // ClientSession stores connection session info for VMess client.
 type ClientSession struct {
+	isAEAD          bool
 	idHash          protocol.IDHash
 	requestBodyKey  [16]byte
 	requestBodyIV   [16]byte
@@ -40,17 +44,27 @@ type ClientSession struct {
 	responseHeader  byte
 }
 
-// NewClientSession creates a new ClientSession.
-func NewClientSession(idHash protocol.IDHash) *ClientSession {
+// NewClientSession creates a new ClientSession. If isAEAD is true, the session sends the AEAD request header
+// instead of the legacy one.
+func NewClientSession(isAEAD bool, idHash protocol.IDHash) *ClientSession {
 	randomBytes := make([]byte, 33) // 16 + 16 + 1
 	common.Must2(rand.Read(randomBytes))
 
-	session := &ClientSession{}
+	session := &ClientSession{
+		isAEAD: isAEAD,
+	}
 	copy(session.requestBodyKey[:], randomBytes[:16])
 	copy(session.requestBodyIV[:], randomBytes[16:32])
 	session.responseHeader = randomBytes[32]
-	session.responseBodyKey = md5.Sum(session.requestBodyKey[:])
-	session.responseBodyIV = md5.Sum(session.requestBodyIV[:])
+	if isAEAD {
+		responseBodyKey := sha256.Sum256(session.requestBodyKey[:])
+		copy(session.responseBodyKey[:], responseBodyKey[:16])
+		responseBodyIV := sha256.Sum256(session.requestBodyIV[:])
+		copy(session.responseBodyIV[:], responseBodyIV[:16])
+	} else {
+		session.responseBodyKey = md5.Sum(session.requestBodyKey[:])
+		session.responseBodyIV = md5.Sum(session.requestBodyIV[:])
+	}
 	session.idHash = idHash
 
 	return session
@@ -62,9 +76,11 @@ func (c *ClientSession) EncodeRequestHeader(header *protocol.RequestHeader, writ
 	if err != nil {
 		return newError("failed to get user account: ", err).AtError()
 	}
-	idHash := c.idHash(account.(*vmess.InternalAccount).AnyValidID().Bytes())
-	common.Must2(idHash.Write(timestamp.Bytes(nil)))
-	common.Must2(writer.Write(idHash.Sum(nil)))
+	if !c.isAEAD {
+		idHash := c.idHash(account.(*vmess.InternalAccount).AnyValidID().Bytes())
+		common.Must2(idHash.Write(timestamp.Bytes(nil)))
+		common.Must2(writer.Write(idHash.Sum(nil)))
+	}
 
 	buffer := buf.New()
 	defer buffer.Release()
@@ -96,10 +112,17 @@ func (c *ClientSession) EncodeRequestHeader(header *protocol.RequestHeader, writ
 		return fnv1a.Size(), nil
 	}))
 
+	cmdKey := account.(*vmess.InternalAccount).ID.CmdKey()
+	if c.isAEAD {
+		authID := aead.CreateAuthID(cmdKey, int64(protocol.NowTime()))
+		common.Must2(writer.Write(aead.SealHeader(cmdKey, authID, buffer.Bytes())))
+		return nil
+	}
+
 	timestampHash := md5.New()
 	common.Must2(timestampHash.Write(hashTimestamp(timestamp)))
 	iv := timestampHash.Sum(nil)
-	aesStream := crypto.NewAesEncryptionStream(account.(*vmess.InternalAccount).ID.CmdKey(), iv)
+	aesStream := crypto.NewAesEncryptionStream(cmdKey, iv)
 	aesStream.XORKeyStream(buffer.Bytes(), buffer.Bytes())
 	common.Must2(writer.Write(buffer.Bytes()))
 	return nil
@@ -166,10 +189,19 @@ func (c *ClientSession) DecodeResponseHeader(reader io.Reader) (*protocol.Respon
 	aesStream := crypto.NewAesDecryptionStream(c.responseBodyKey[:], c.responseBodyIV[:])
 	c.responseReader = crypto.NewCryptionReader(aesStream, reader)
 
+	headerReader := c.responseReader
+	if c.isAEAD {
+		header, err := aead.OpenResponseHeader(c.responseBodyKey[:], c.responseBodyIV[:], reader)
+		if err != nil {
+			return nil, newError("failed to open AEAD response header").Base(err)
+		}
+		headerReader = bytes.NewReader(header)
+	}
+
 	buffer := buf.New()
 	defer buffer.Release()
 
-	if err := buffer.AppendSupplier(buf.ReadFullFrom(c.responseReader, 4)); err != nil {
+	if err := buffer.AppendSupplier(buf.ReadFullFrom(headerReader, 4)); err != nil {
 		return nil, newError("failed to read response header").Base(err)
 	}
 
@@ -185,7 +217,7 @@ func (c *ClientSession) DecodeResponseHeader(reader io.Reader) (*protocol.Respon
 		cmdID := buffer.Byte(2)
 		dataLen := int32(buffer.Byte(3))
 
-		if err := buffer.Reset(buf.ReadFullFrom(c.responseReader, dataLen)); err != nil {
+		if err := buffer.Reset(buf.ReadFullFrom(headerReader, dataLen)); err != nil {
 			return nil, newError("failed to read response command").Base(err)
 		}
 		command, err := UnmarshalCommand(cmdID, buffer.Bytes())
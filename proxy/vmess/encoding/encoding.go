@@ -9,6 +9,9 @@ import (
 
 const (
 	Version = byte(1)
+
+	// aeadTimeDiff is the maximum difference in seconds between the timestamp in AEAD request header and local time.
+	aeadTimeDiff = 120
 )
 
 var addrParser = protocol.NewAddressParser(
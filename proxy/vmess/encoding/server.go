@@ -1,9 +1,11 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/md5"
+	"crypto/sha256"
 	"hash/fnv"
 	"io"
 	"sync"
@@ -21,6 +23,7 @@ import (
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 type sessionId struct {
@@ -96,6 +99,8 @@ type ServerSession struct {
 	responseBodyIV  [16]byte
 	responseWriter  io.Writer
 	responseHeader  byte
+	isAEADRequest   bool
+	aeadHeaderOnly  bool
 }
 
 // NewServerSession creates a new ServerSession, using the given UserValidator.
@@ -107,6 +112,11 @@ func NewServerSession(validator *vmess.TimedUserValidator, sessionHistory *Sessi
 	}
 }
 
+// SetAEADHeaderOnly sets whether the session rejects requests with the legacy request header.
+func (s *ServerSession) SetAEADHeaderOnly(aeadHeaderOnly bool) {
+	s.aeadHeaderOnly = aeadHeaderOnly
+}
+
 func parseSecurityType(b byte) protocol.SecurityType {
 	if _, f := protocol.SecurityType_name[int32(b)]; f {
 		st := protocol.SecurityType(b)
@@ -128,22 +138,45 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 		return nil, newError("failed to read request header").Base(err)
 	}
 
-	user, timestamp, valid := s.userValidator.Get(buffer.Bytes())
+	var user *protocol.User
+	var timestamp protocol.Timestamp
+	valid := false
+	if !s.aeadHeaderOnly {
+		user, timestamp, valid = s.userValidator.Get(buffer.Bytes())
+	}
 	if !valid {
-		return nil, newError("invalid user")
+		user, timestamp, valid = s.userValidator.GetAEAD(buffer.Bytes())
+		if !valid {
+			return nil, newError("invalid user")
+		}
+		if diff := timestamp - protocol.NowTime(); diff > aeadTimeDiff || diff < -aeadTimeDiff {
+			return nil, newError("invalid timestamp in AEAD header: ", timestamp)
+		}
+		s.isAEADRequest = true
 	}
 
-	timestampHash := md5.New()
-	common.Must2(timestampHash.Write(hashTimestamp(timestamp)))
-	iv := timestampHash.Sum(nil)
 	account, err := user.GetTypedAccount()
 	if err != nil {
 		return nil, newError("failed to get user account").Base(err)
 	}
 	vmessAccount := account.(*vmess.InternalAccount)
 
-	aesStream := crypto.NewAesDecryptionStream(vmessAccount.ID.CmdKey(), iv)
-	decryptor := crypto.NewCryptionReader(aesStream, reader)
+	var decryptor io.Reader
+	if s.isAEADRequest {
+		var authID [16]byte
+		copy(authID[:], buffer.Bytes())
+		header, err := aead.OpenHeader(vmessAccount.ID.CmdKey(), authID, reader)
+		if err != nil {
+			return nil, newError("failed to open AEAD header").Base(err)
+		}
+		decryptor = bytes.NewReader(header)
+	} else {
+		timestampHash := md5.New()
+		common.Must2(timestampHash.Write(hashTimestamp(timestamp)))
+		iv := timestampHash.Sum(nil)
+		aesStream := crypto.NewAesDecryptionStream(vmessAccount.ID.CmdKey(), iv)
+		decryptor = crypto.NewCryptionReader(aesStream, reader)
+	}
 
 	if err := buffer.Reset(buf.ReadFullFrom(decryptor, 38)); err != nil {
 		return nil, newError("failed to read request header").Base(err)
@@ -289,17 +322,34 @@ func (s *ServerSession) DecodeRequestBody(request *protocol.RequestHeader, reade
 
 // EncodeResponseHeader writes encoded response header into the given writer.
 func (s *ServerSession) EncodeResponseHeader(header *protocol.ResponseHeader, writer io.Writer) {
-	s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
-	s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	if s.isAEADRequest {
+		responseBodyKey := sha256.Sum256(s.requestBodyKey[:])
+		copy(s.responseBodyKey[:], responseBodyKey[:16])
+		responseBodyIV := sha256.Sum256(s.requestBodyIV[:])
+		copy(s.responseBodyIV[:], responseBodyIV[:16])
+	} else {
+		s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
+		s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	}
 
 	aesStream := crypto.NewAesEncryptionStream(s.responseBodyKey[:], s.responseBodyIV[:])
 	encryptionWriter := crypto.NewCryptionWriter(aesStream, writer)
 	s.responseWriter = encryptionWriter
 
-	common.Must2(encryptionWriter.Write([]byte{s.responseHeader, byte(header.Option)}))
-	err := MarshalCommand(header.Command, encryptionWriter)
+	var headerWriter io.Writer = encryptionWriter
+	var aeadHeader bytes.Buffer
+	if s.isAEADRequest {
+		headerWriter = &aeadHeader
+	}
+
+	common.Must2(headerWriter.Write([]byte{s.responseHeader, byte(header.Option)}))
+	err := MarshalCommand(header.Command, headerWriter)
 	if err != nil {
-		common.Must2(encryptionWriter.Write([]byte{0x00, 0x00}))
+		common.Must2(headerWriter.Write([]byte{0x00, 0x00}))
+	}
+
+	if s.isAEADRequest {
+		common.Must2(writer.Write(aead.SealResponseHeader(s.responseBodyKey[:], s.responseBodyIV[:], aeadHeader.Bytes())))
 	}
 }
 
@@ -1,5 +1,9 @@
 package inbound
 
+import (
+	"v2ray.com/core/common/net"
+)
+
 // GetDefaultValue returns default settings of DefaultConfig.
 func (c *Config) GetDefaultValue() *DefaultConfig {
 	if c.GetDefault() == nil {
@@ -10,3 +14,8 @@ func (c *Config) GetDefaultValue() *DefaultConfig {
 	}
 	return c.Default
 }
+
+// AsDestination returns the TCP destination of the fallback.
+func (c *FallbackConfig) AsDestination() net.Destination {
+	return net.TCPDestination(c.Address.AsAddress(), net.Port(c.Port))
+}
@@ -3,6 +3,7 @@ package inbound
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_protocol "v2ray.com/core/common/protocol"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -56,17 +57,45 @@ func (m *DefaultConfig) GetLevel() uint32 {
 	return 0
 }
 
+// FallbackConfig is the destination where connections that fail authentication are forwarded to.
+type FallbackConfig struct {
+	Address *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Port    uint32                            `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+}
+
+func (m *FallbackConfig) Reset()                    { *m = FallbackConfig{} }
+func (m *FallbackConfig) String() string            { return proto.CompactTextString(m) }
+func (*FallbackConfig) ProtoMessage()               {}
+func (*FallbackConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *FallbackConfig) GetAddress() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *FallbackConfig) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
 type Config struct {
 	User                 []*v2ray_core_common_protocol.User `protobuf:"bytes,1,rep,name=user" json:"user,omitempty"`
 	Default              *DefaultConfig                     `protobuf:"bytes,2,opt,name=default" json:"default,omitempty"`
 	Detour               *DetourConfig                      `protobuf:"bytes,3,opt,name=detour" json:"detour,omitempty"`
 	SecureEncryptionOnly bool                               `protobuf:"varint,4,opt,name=secure_encryption_only,json=secureEncryptionOnly" json:"secure_encryption_only,omitempty"`
+	// Rejects requests with the legacy request header, and accepts AEAD request header only.
+	AeadHeaderOnly bool            `protobuf:"varint,5,opt,name=aead_header_only,json=aeadHeaderOnly" json:"aead_header_only,omitempty"`
+	Fallback       *FallbackConfig `protobuf:"bytes,6,opt,name=fallback" json:"fallback,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Config) GetUser() []*v2ray_core_common_protocol.User {
 	if m != nil {
@@ -96,35 +125,57 @@ func (m *Config) GetSecureEncryptionOnly() bool {
 	return false
 }
 
+func (m *Config) GetAeadHeaderOnly() bool {
+	if m != nil {
+		return m.AeadHeaderOnly
+	}
+	return false
+}
+
+func (m *Config) GetFallback() *FallbackConfig {
+	if m != nil {
+		return m.Fallback
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DetourConfig)(nil), "v2ray.core.proxy.vmess.inbound.DetourConfig")
 	proto.RegisterType((*DefaultConfig)(nil), "v2ray.core.proxy.vmess.inbound.DefaultConfig")
+	proto.RegisterType((*FallbackConfig)(nil), "v2ray.core.proxy.vmess.inbound.FallbackConfig")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.vmess.inbound.Config")
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/proxy/vmess/inbound/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0x4f, 0x4f, 0xf2, 0x40,
-	0x10, 0xc6, 0xd3, 0xc2, 0x0b, 0xbc, 0x8b, 0x78, 0x68, 0x88, 0xa9, 0x1e, 0x48, 0xd3, 0x13, 0x26,
-	0xba, 0x9b, 0x54, 0x3e, 0x80, 0x11, 0x8c, 0xe1, 0x24, 0x69, 0x22, 0x07, 0x2f, 0xa4, 0x6c, 0x07,
-	0xd3, 0x64, 0xbb, 0x43, 0xb6, 0x5b, 0x62, 0xcf, 0x7e, 0x1b, 0x3f, 0xa5, 0x61, 0x5a, 0xfc, 0x77,
-	0x90, 0xdb, 0xce, 0xce, 0xef, 0x79, 0xe6, 0x99, 0x61, 0x62, 0x17, 0x99, 0xa4, 0xe2, 0x12, 0x73,
-	0x21, 0xd1, 0x80, 0xd8, 0x1a, 0x7c, 0xad, 0xc4, 0x2e, 0x87, 0xa2, 0x10, 0x99, 0x5e, 0x63, 0xa9,
-	0x53, 0x21, 0x51, 0x6f, 0xb2, 0x17, 0xbe, 0x35, 0x68, 0xd1, 0x1b, 0x1d, 0x04, 0x06, 0x38, 0xc1,
-	0x9c, 0x60, 0xde, 0xc0, 0x17, 0x97, 0xbf, 0x0c, 0x25, 0xe6, 0x39, 0x6a, 0x41, 0x62, 0x89, 0x4a,
-	0x94, 0x05, 0x98, 0xda, 0x2a, 0x1c, 0xb1, 0x93, 0x19, 0x58, 0x2c, 0xcd, 0x94, 0x06, 0x78, 0xa7,
-	0xcc, 0xb5, 0xe8, 0x3b, 0x81, 0x33, 0xfe, 0x1f, 0xbb, 0x16, 0xc3, 0x5b, 0x36, 0x98, 0xc1, 0x26,
-	0x29, 0x95, 0x6d, 0x80, 0x73, 0xd6, 0x4b, 0x94, 0x05, 0xb3, 0xca, 0x52, 0xc2, 0x06, 0x71, 0x97,
-	0xea, 0x79, 0xea, 0x0d, 0xd9, 0x3f, 0x05, 0x3b, 0x50, 0xbe, 0x4b, 0xff, 0x75, 0x11, 0xbe, 0xb9,
-	0xac, 0xd3, 0x68, 0x27, 0xac, 0xbd, 0x1f, 0xed, 0x3b, 0x41, 0x6b, 0xdc, 0x8f, 0x02, 0xfe, 0x6d,
-	0x8d, 0x3a, 0x22, 0x3f, 0x44, 0xe4, 0x4f, 0x05, 0x98, 0x98, 0x68, 0xef, 0x81, 0x75, 0xd3, 0x3a,
-	0x02, 0x19, 0xf7, 0xa3, 0x6b, 0xfe, 0xf7, 0xfe, 0xfc, 0x47, 0xe2, 0xf8, 0xa0, 0xf6, 0x66, 0xac,
-	0x93, 0xd2, 0xae, 0x7e, 0x8b, 0x7c, 0xae, 0x8e, 0xfb, 0x7c, 0x5d, 0x26, 0x6e, 0xb4, 0xde, 0x84,
-	0x9d, 0x15, 0x20, 0x4b, 0x03, 0x2b, 0xd0, 0xd2, 0x54, 0x5b, 0x9b, 0xa1, 0x5e, 0xa1, 0x56, 0x95,
-	0xdf, 0x0e, 0x9c, 0x71, 0x2f, 0x1e, 0xd6, 0xdd, 0xfb, 0xcf, 0xe6, 0xa3, 0x56, 0xd5, 0xdd, 0x82,
-	0x85, 0x12, 0xf3, 0x23, 0x03, 0x17, 0xce, 0x73, 0xb7, 0x79, 0xbe, 0xbb, 0xa3, 0x65, 0x14, 0x27,
-	0x15, 0x9f, 0xee, 0xd9, 0x05, 0xb1, 0x4b, 0x62, 0xe7, 0x35, 0xb0, 0xee, 0xd0, 0xad, 0x6e, 0x3e,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x49, 0x84, 0xe1, 0x3e, 0x02, 0x00, 0x00,
+	// 433 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x4f, 0x6f, 0xd3, 0x40,
+	0x10, 0xc5, 0x65, 0x37, 0x4d, 0xc2, 0x86, 0x46, 0xc8, 0xaa, 0x90, 0xe9, 0x21, 0x0a, 0xbe, 0x10,
+	0x24, 0x58, 0x4b, 0xa6, 0x37, 0x2e, 0x88, 0x86, 0x3f, 0xe1, 0xd2, 0x68, 0x25, 0x7a, 0xe0, 0x12,
+	0x6d, 0x76, 0x27, 0x60, 0xb1, 0xde, 0x89, 0xd6, 0xeb, 0x08, 0x7f, 0x25, 0xbe, 0x21, 0x37, 0x94,
+	0xf1, 0xa6, 0x50, 0x54, 0x91, 0x9b, 0x77, 0xe6, 0xf7, 0x9e, 0xdf, 0xcc, 0xb0, 0x7c, 0x57, 0x38,
+	0xd9, 0x72, 0x85, 0x55, 0xae, 0xd0, 0x41, 0xbe, 0x75, 0xf8, 0xa3, 0xcd, 0x77, 0x15, 0xd4, 0x75,
+	0x5e, 0xda, 0x35, 0x36, 0x56, 0xe7, 0x0a, 0xed, 0xa6, 0xfc, 0xca, 0xb7, 0x0e, 0x3d, 0x26, 0x93,
+	0x83, 0xc0, 0x01, 0x27, 0x98, 0x13, 0xcc, 0x03, 0x7c, 0xf1, 0xec, 0x1f, 0x43, 0x85, 0x55, 0x85,
+	0x36, 0xb7, 0xe0, 0x73, 0xa9, 0xb5, 0xdb, 0xa3, 0x64, 0x74, 0xf1, 0xfc, 0x7e, 0x90, 0x9a, 0x0a,
+	0x4d, 0xde, 0xd4, 0xe0, 0x3a, 0x34, 0x9b, 0xb0, 0x87, 0x73, 0xf0, 0xd8, 0xb8, 0x2b, 0x4a, 0x92,
+	0x8c, 0x59, 0xec, 0x31, 0x8d, 0xa6, 0xd1, 0xec, 0x81, 0x88, 0x3d, 0x66, 0x6f, 0xd8, 0xd9, 0x1c,
+	0x36, 0xb2, 0x31, 0x3e, 0x00, 0x4f, 0xd8, 0x50, 0x1a, 0x0f, 0x6e, 0x55, 0x6a, 0xc2, 0xce, 0xc4,
+	0x80, 0xde, 0x0b, 0x9d, 0x9c, 0xb3, 0x53, 0x03, 0x3b, 0x30, 0x69, 0x4c, 0xf5, 0xee, 0x91, 0x49,
+	0x36, 0x7e, 0x2f, 0x8d, 0x59, 0x4b, 0xf5, 0x3d, 0x58, 0xbc, 0x66, 0x83, 0x90, 0x97, 0x1c, 0x46,
+	0xc5, 0x53, 0xfe, 0xd7, 0xe4, 0x5d, 0x58, 0x6e, 0xc1, 0xf3, 0xc5, 0xf2, 0xda, 0xcd, 0xb1, 0x92,
+	0xa5, 0x15, 0x07, 0x45, 0x92, 0xb0, 0xde, 0x16, 0x9d, 0x0f, 0xff, 0xa0, 0xef, 0xec, 0x57, 0xcc,
+	0xfa, 0xc1, 0xfb, 0x92, 0xf5, 0xf6, 0xd3, 0xa5, 0xd1, 0xf4, 0x64, 0x36, 0x2a, 0xa6, 0xf7, 0x18,
+	0x1f, 0xb6, 0xc0, 0x3f, 0xd7, 0xe0, 0x04, 0xd1, 0xc9, 0x07, 0x36, 0xd0, 0xdd, 0x94, 0xe4, 0x3b,
+	0x2a, 0x5e, 0xf2, 0xff, 0xdf, 0x82, 0xdf, 0x59, 0x8a, 0x38, 0xa8, 0x93, 0x39, 0xeb, 0x6b, 0x5a,
+	0x67, 0x7a, 0x42, 0x3e, 0x2f, 0x8e, 0xfb, 0xfc, 0x59, 0xbe, 0x08, 0xda, 0xe4, 0x92, 0x3d, 0xae,
+	0x41, 0x35, 0x0e, 0x56, 0x60, 0x95, 0x6b, 0xb7, 0xbe, 0x44, 0xbb, 0x42, 0x6b, 0xda, 0xb4, 0x37,
+	0x8d, 0x66, 0x43, 0x71, 0xde, 0x75, 0xdf, 0xdd, 0x36, 0xaf, 0xad, 0x69, 0x93, 0x19, 0x7b, 0x24,
+	0x41, 0xea, 0xd5, 0x37, 0x90, 0x1a, 0x5c, 0xc7, 0x9f, 0x12, 0x3f, 0xde, 0xd7, 0x3f, 0x52, 0x99,
+	0xc8, 0x4f, 0x6c, 0xb8, 0x09, 0x27, 0x49, 0xfb, 0x94, 0x93, 0x1f, 0xcb, 0x79, 0xf7, 0x84, 0xe2,
+	0x56, 0xff, 0x76, 0xc9, 0x32, 0x85, 0xd5, 0x11, 0xf9, 0x32, 0xfa, 0x32, 0x08, 0x9f, 0x3f, 0xe3,
+	0xc9, 0x4d, 0x21, 0x64, 0xcb, 0xaf, 0xf6, 0xec, 0x92, 0xd8, 0x1b, 0x62, 0x17, 0x1d, 0xb0, 0xee,
+	0xd3, 0x85, 0x5e, 0xfd, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x67, 0x0b, 0xf1, 0x22, 0x40, 0x03, 0x00,
+	0x00,
 }
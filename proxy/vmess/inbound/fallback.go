@@ -0,0 +1,75 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/transport/internet"
+)
+
+// recordReader keeps a copy of the bytes read from the underlying reader, until it is stopped.
+type recordReader struct {
+	reader io.Reader
+	record *bytes.Buffer
+}
+
+func newRecordReader(reader io.Reader) *recordReader {
+	return &recordReader{
+		reader: reader,
+		record: new(bytes.Buffer),
+	}
+}
+
+func (r *recordReader) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	if r.record != nil && n > 0 {
+		r.record.Write(b[:n])
+	}
+	return n, err
+}
+
+// Stop stops recording and releases the recorded bytes.
+func (r *recordReader) Stop() {
+	r.record = nil
+}
+
+// handleFallback forwards the connection to the fallback destination. The bytes already read from the connection are
+// sent first.
+func (h *Handler) handleFallback(ctx context.Context, connection internet.Connection, header []byte) error {
+	dest := h.fallback.AsDestination()
+	newError("fallback to ", dest, " for connection from ", connection.RemoteAddr()).AtInfo().WithContext(ctx).WriteToLog()
+
+	conn, err := internet.DialSystem(ctx, nil, dest)
+	if err != nil {
+		return newError("failed to dial fallback ", dest).Base(err)
+	}
+	defer conn.Close()
+
+	sessionPolicy := h.policyManager.ForLevel(0)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		if _, err := conn.Write(header); err != nil {
+			return newError("failed to write header to fallback").Base(err)
+		}
+		return buf.Copy(buf.NewReader(connection), buf.NewWriter(conn), buf.UpdateActivity(timer))
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		return buf.Copy(buf.NewReader(conn), buf.NewWriter(connection), buf.UpdateActivity(timer))
+	}
+
+	if err := signal.ExecuteParallel(ctx, requestDone, responseDone); err != nil {
+		return newError("fallback ends").Base(err)
+	}
+
+	return nil
+}
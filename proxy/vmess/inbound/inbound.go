@@ -19,6 +19,7 @@ import (
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/uuid"
+	"v2ray.com/core/proxy"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/encoding"
 	"v2ray.com/core/transport/internet"
@@ -102,6 +103,8 @@ type Handler struct {
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
 	secure                bool
+	aeadHeaderOnly        bool
+	fallback              *FallbackConfig
 }
 
 // New creates a new VMess inbound handler.
@@ -115,6 +118,8 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		usersByEmail:          newUserByEmail(config.GetDefaultValue()),
 		sessionHistory:        encoding.NewSessionHistory(),
 		secure:                config.SecureEncryptionOnly,
+		aeadHeaderOnly:        config.AeadHeaderOnly,
+		fallback:              config.Fallback,
 	}
 
 	for _, user := range config.User {
@@ -224,12 +229,27 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		return newError("unable to set read deadline").Base(err).AtWarning()
 	}
 
-	reader := &buf.BufferedReader{Reader: buf.NewReader(connection)}
+	var input io.Reader = connection
+	var recorder *recordReader
+	if h.fallback != nil {
+		recorder = newRecordReader(connection)
+		input = recorder
+	}
+	reader := &buf.BufferedReader{Reader: buf.NewReader(input)}
 
 	session := encoding.NewServerSession(h.clients, h.sessionHistory)
+	session.SetAEADHeaderOnly(h.aeadHeaderOnly)
 	request, err := session.DecodeRequestHeader(reader)
 
 	if err != nil {
+		// Visitors of the fallback are not rejected, nor reported as authentication failures.
+		if recorder != nil && recorder.record.Len() > 0 {
+			newError("forwarding unauthenticated connection from ", connection.RemoteAddr(), " to fallback").Base(err).WithContext(ctx).WriteToLog()
+			if err := connection.SetReadDeadline(time.Time{}); err != nil {
+				newError("unable to set back read deadline").Base(err).WithContext(ctx).WriteToLog()
+			}
+			return h.handleFallback(ctx, connection, recorder.record.Bytes())
+		}
 		if errors.Cause(err) != io.EOF {
 			log.Record(&log.AccessMessage{
 				From:   connection.RemoteAddr(),
@@ -237,11 +257,16 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 				Status: log.AccessRejected,
 				Reason: err,
 			})
+			proxy.ReportAuthFailure(ctx)
 			err = newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
 		}
 		return err
 	}
 
+	if recorder != nil {
+		recorder.Stop()
+	}
+
 	if h.secure && isInecureEncryption(request.Security) {
 		log.Record(&log.AccessMessage{
 			From:   connection.RemoteAddr(),
@@ -8,6 +8,7 @@ package vmess
 //go:generate go run $GOPATH/src/v2ray.com/core/common/errors/errorgen/main.go -pkg vmess -path Proxy,VMess
 
 import (
+	"crypto/cipher"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 const (
@@ -23,9 +25,10 @@ const (
 )
 
 type user struct {
-	user    *protocol.User
-	account *InternalAccount
-	lastSec protocol.Timestamp
+	user         *protocol.User
+	account      *InternalAccount
+	lastSec      protocol.Timestamp
+	authIDCipher cipher.Block
 }
 
 type TimedUserValidator struct {
@@ -124,9 +127,10 @@ func (v *TimedUserValidator) Add(u *protocol.User) error {
 	nowSec := time.Now().Unix()
 
 	uu := &user{
-		user:    u,
-		account: account,
-		lastSec: protocol.Timestamp(nowSec - cacheDurationSec),
+		user:         u,
+		account:      account,
+		lastSec:      protocol.Timestamp(nowSec - cacheDurationSec),
+		authIDCipher: aead.NewAuthIDCipher(account.ID.CmdKey()),
 	}
 	v.users = append(v.users, uu)
 	v.generateNewHashes(protocol.Timestamp(nowSec+cacheDurationSec), uu)
@@ -147,6 +151,19 @@ func (v *TimedUserValidator) Get(userHash []byte) (*protocol.User, protocol.Time
 	return nil, 0, false
 }
 
+// GetAEAD returns the user and the timestamp of the given auth ID in AEAD request header.
+func (v *TimedUserValidator) GetAEAD(authID []byte) (*protocol.User, protocol.Timestamp, bool) {
+	defer v.RUnlock()
+	v.RLock()
+
+	for _, u := range v.users {
+		if ts, ok := aead.DecodeAuthID(u.authIDCipher, authID); ok {
+			return u.user, protocol.Timestamp(ts), true
+		}
+	}
+	return nil, 0, false
+}
+
 func (v *TimedUserValidator) Remove(email string) bool {
 	v.Lock()
 	defer v.Unlock()
@@ -10,6 +10,7 @@ import (
 
 	"v2ray.com/core/common/protocol"
 	. "v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 	. "v2ray.com/ext/assert"
 )
 
@@ -53,6 +54,23 @@ func TestUserValidator(t *testing.T) {
 		assert(euser, IsNil)
 	}
 
+	{
+		ts := protocol.Timestamp(time.Now().Unix())
+		authID := aead.CreateAuthID(protocol.NewID(id).CmdKey(), int64(ts))
+
+		euser, ets, found := v.GetAEAD(authID[:])
+		assert(found, IsTrue)
+		assert(euser.Email, Equals, user.Email)
+		assert(int64(ets), Equals, int64(ts))
+	}
+
 	assert(v.Remove(user.Email), IsTrue)
 	assert(v.Remove(user.Email), IsFalse)
+
+	{
+		authID := aead.CreateAuthID(protocol.NewID(id).CmdKey(), time.Now().Unix())
+		euser, _, found := v.GetAEAD(authID[:])
+		assert(found, IsFalse)
+		assert(euser, IsNil)
+	}
 }
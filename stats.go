@@ -15,6 +15,8 @@ type StatManager interface {
 
 	RegisterCounter(string) (StatCounter, error)
 	GetCounter(string) StatCounter
+	// Visit calls the visitor on each registered counter, until the visitor returns false.
+	Visit(visitor func(string, StatCounter) bool)
 }
 
 // GetOrRegisterStatCounter tries to get the StatCounter first. If not exist, it then tries to create a new counter.
@@ -73,6 +75,16 @@ func (s *syncStatManager) GetCounter(name string) StatCounter {
 	return s.StatManager.GetCounter(name)
 }
 
+func (s *syncStatManager) Visit(visitor func(string, StatCounter) bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.StatManager == nil {
+		return
+	}
+	s.StatManager.Visit(visitor)
+}
+
 func (s *syncStatManager) Set(m StatManager) {
 	if m == nil {
 		return
@@ -1,12 +1,15 @@
 package scenarios
 
 import (
+	"io"
 	"testing"
+	"time"
 
 	xproxy "golang.org/x/net/proxy"
 	socks4 "h12.me/socks"
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
@@ -337,3 +340,435 @@ func TestSocksConformance(t *testing.T) {
 
 	CloseAllServers(servers)
 }
+
+func TestSocksBind(t *testing.T) {
+	assert := With(t)
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType: socks.AuthType_NO_AUTH,
+					Address:  net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	assert(err, IsNil)
+	defer CloseAllServers(servers)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+	defer conn.Close()
+
+	readReply := func() (net.Address, net.Port) {
+		header := make([]byte, 4)
+		_, err := io.ReadFull(conn, header)
+		assert(err, IsNil)
+		assert(header[1], Equals, byte(0x00))
+		addrLen := 4
+		if header[3] == 0x04 {
+			addrLen = 16
+		}
+		addr := make([]byte, addrLen+2)
+		_, err = io.ReadFull(conn, addr)
+		assert(err, IsNil)
+		return net.IPAddress(addr[:addrLen]), net.PortFromBytes(addr[addrLen:])
+	}
+
+	_, err = conn.Write([]byte{0x05, 0x01, 0x00})
+	assert(err, IsNil)
+	method := make([]byte, 2)
+	_, err = io.ReadFull(conn, method)
+	assert(err, IsNil)
+	assert(method, Equals, []byte{0x05, 0x00})
+
+	_, err = conn.Write([]byte{0x05, 0x02, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+	assert(err, IsNil)
+	boundAddr, boundPort := readReply()
+	assert(boundAddr.String(), Equals, "127.0.0.1")
+
+	peer, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(boundPort),
+	})
+	assert(err, IsNil)
+	defer peer.Close()
+
+	_, peerPort := readReply()
+	assert(peerPort, Equals, net.Port(peer.LocalAddr().(*net.TCPAddr).Port))
+
+	payload := []byte("test payload")
+	response := make([]byte, len(payload))
+
+	_, err = peer.Write(payload)
+	assert(err, IsNil)
+	_, err = io.ReadFull(conn, response)
+	assert(err, IsNil)
+	assert(response, Equals, payload)
+
+	_, err = conn.Write(xor(payload))
+	assert(err, IsNil)
+	_, err = io.ReadFull(peer, response)
+	assert(err, IsNil)
+	assert(response, Equals, xor(payload))
+}
+
+func TestSocksBindControlClose(t *testing.T) {
+	assert := With(t)
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType: socks.AuthType_NO_AUTH,
+					Address:  net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	assert(err, IsNil)
+	defer CloseAllServers(servers)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+
+	_, err = conn.Write([]byte{0x05, 0x01, 0x00})
+	assert(err, IsNil)
+	method := make([]byte, 2)
+	_, err = io.ReadFull(conn, method)
+	assert(err, IsNil)
+
+	// Connections from other hosts are rejected, so that the probes below don't end the bind.
+	_, err = conn.Write([]byte{0x05, 0x02, 0x00, 0x01, 127, 0, 0, 2, 0, 0})
+	assert(err, IsNil)
+	reply := make([]byte, 10)
+	_, err = io.ReadFull(conn, reply)
+	assert(err, IsNil)
+	assert(reply[1], Equals, byte(0x00))
+	assert(reply[3], Equals, byte(0x01))
+	boundPort := net.PortFromBytes(reply[8:])
+
+	// The pending bind is cancelled once the client goes away, long before it times out.
+	assert(conn.Close(), IsNil)
+	closed := false
+	for i := 0; i < 50 && !closed; i++ {
+		time.Sleep(100 * time.Millisecond)
+		peer, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+			IP:   []byte{127, 0, 0, 1},
+			Port: int(boundPort),
+		})
+		if err != nil {
+			closed = true
+			continue
+		}
+		peer.Close()
+	}
+	assert(closed, IsTrue)
+}
+
+func TestSocksClientBindClose(t *testing.T) {
+	assert := With(t)
+
+	// A SOCKS server that accepts the BIND request, and then waits for the client to go away.
+	upstream, err := net.ListenTCP("tcp", &net.TCPAddr{IP: []byte{127, 0, 0, 1}})
+	assert(err, IsNil)
+	defer upstream.Close()
+
+	upstreamDone := make(chan error, 1)
+	go func() {
+		conn, err := upstream.Accept()
+		if err != nil {
+			upstreamDone <- err
+			return
+		}
+		defer conn.Close()
+
+		b := make([]byte, 10)
+		io.ReadFull(conn, b[:3])
+		conn.Write([]byte{0x05, 0x00})
+		io.ReadFull(conn, b)
+		conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0x10, 0x00})
+
+		conn.SetReadDeadline(time.Now().Add(time.Second * 10))
+		_, err = conn.Read(b)
+		upstreamDone <- err
+	}()
+
+	clientPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(clientPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType: socks.AuthType_NO_AUTH,
+					Address:  net.NewIPOrDomain(net.LocalHostIP),
+					Timeout:  1,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&socks.ClientConfig{
+					Server: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(upstream.Addr().(*net.TCPAddr).Port),
+						},
+					},
+				}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(clientConfig)
+	assert(err, IsNil)
+	defer CloseAllServers(servers)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(clientPort),
+	})
+	assert(err, IsNil)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte{0x05, 0x01, 0x00})
+	assert(err, IsNil)
+	method := make([]byte, 2)
+	_, err = io.ReadFull(conn, method)
+	assert(err, IsNil)
+
+	_, err = conn.Write([]byte{0x05, 0x02, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+	assert(err, IsNil)
+	reply := make([]byte, 10)
+	_, err = io.ReadFull(conn, reply)
+	assert(err, IsNil)
+	assert(reply[1], Equals, byte(0x00))
+
+	// The bind times out before any peer connects. The connection to the upstream server must then be closed.
+	assert(<-upstreamDone, Equals, io.EOF)
+}
+
+func TestSocksUDPAssociation(t *testing.T) {
+	assert := With(t)
+
+	udpServer := udp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := udpServer.Start()
+	assert(err, IsNil)
+	defer udpServer.Close()
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:   socks.AuthType_NO_AUTH,
+					Address:    net.NewIPOrDomain(net.LocalHostIP),
+					UdpEnabled: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	assert(err, IsNil)
+	defer CloseAllServers(servers)
+
+	udpConn, err := net.DialUDP("udp", nil, &net.UDPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+	defer udpConn.Close()
+
+	payload := []byte("test payload")
+	request := &protocol.RequestHeader{
+		Address: dest.Address,
+		Port:    dest.Port,
+	}
+	exchange := func() bool {
+		packet, err := socks.EncodeUDPPacket(request, payload)
+		assert(err, IsNil)
+		_, err = udpConn.Write(packet.Bytes())
+		packet.Release()
+		assert(err, IsNil)
+
+		response := make([]byte, 1024)
+		assert(udpConn.SetReadDeadline(time.Now().Add(time.Second)), IsNil)
+		nBytes, err := udpConn.Read(response)
+		if err != nil {
+			return false
+		}
+		b := buf.New()
+		b.Write(response[:nBytes])
+		_, err = socks.DecodeUDPPacket(b)
+		assert(err, IsNil)
+		assert(b.Bytes(), Equals, xor(payload))
+		b.Release()
+		return true
+	}
+
+	// Packets are dropped before the client associates.
+	assert(exchange(), IsFalse)
+	time.Sleep(time.Second)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+
+	_, err = conn.Write([]byte{0x05, 0x01, 0x00})
+	assert(err, IsNil)
+	method := make([]byte, 2)
+	_, err = io.ReadFull(conn, method)
+	assert(err, IsNil)
+	assert(method, Equals, []byte{0x05, 0x00})
+
+	localPort := udpConn.LocalAddr().(*net.UDPAddr).Port
+	_, err = conn.Write([]byte{0x05, 0x03, 0x00, 0x01, 127, 0, 0, 1, byte(localPort >> 8), byte(localPort)})
+	assert(err, IsNil)
+	reply := make([]byte, 10)
+	_, err = io.ReadFull(conn, reply)
+	assert(err, IsNil)
+	assert(reply[1], Equals, byte(0x00))
+	assert(net.PortFromBytes(reply[8:10]), Equals, serverPort)
+
+	assert(exchange(), IsTrue)
+
+	// The association is torn down along with the TCP connection.
+	assert(conn.Close(), IsNil)
+	time.Sleep(time.Second)
+	assert(exchange(), IsFalse)
+}
+
+func TestSocksUDPFullCone(t *testing.T) {
+	assert := With(t)
+
+	udpServer1 := udp.Server{
+		MsgProcessor: xor,
+	}
+	dest1, err := udpServer1.Start()
+	assert(err, IsNil)
+	defer udpServer1.Close()
+
+	udpServer2 := udp.Server{
+		MsgProcessor: xor,
+	}
+	dest2, err := udpServer2.Start()
+	assert(err, IsNil)
+	defer udpServer2.Close()
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:    socks.AuthType_NO_AUTH,
+					Address:     net.NewIPOrDomain(net.LocalHostIP),
+					UdpEnabled:  true,
+					UdpFullCone: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	assert(err, IsNil)
+	defer CloseAllServers(servers)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte{0x05, 0x01, 0x00, 0x05, 0x03, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+	assert(err, IsNil)
+	reply := make([]byte, 12)
+	_, err = io.ReadFull(conn, reply)
+	assert(err, IsNil)
+	assert(reply[3], Equals, byte(0x00))
+
+	udpConn, err := net.DialUDP("udp", nil, &net.UDPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(serverPort),
+	})
+	assert(err, IsNil)
+	defer udpConn.Close()
+
+	for _, dest := range []net.Destination{dest1, dest2, dest1} {
+		payload := []byte("payload to " + dest.String())
+		packet, err := socks.EncodeUDPPacket(&protocol.RequestHeader{Address: dest.Address, Port: dest.Port}, payload)
+		assert(err, IsNil)
+		_, err = udpConn.Write(packet.Bytes())
+		packet.Release()
+		assert(err, IsNil)
+
+		response := make([]byte, 1024)
+		assert(udpConn.SetReadDeadline(time.Now().Add(time.Second*5)), IsNil)
+		nBytes, err := udpConn.Read(response)
+		assert(err, IsNil)
+		b := buf.New()
+		b.Write(response[:nBytes])
+		source, err := socks.DecodeUDPPacket(b)
+		assert(err, IsNil)
+		assert(source.Destination().Port, Equals, dest.Port)
+		assert(b.Bytes(), Equals, xor(payload))
+		b.Release()
+	}
+}
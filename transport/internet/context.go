@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 )
 
 type key int
@@ -13,6 +14,8 @@ const (
 	dialerSrcKey
 	transportSettingsKey
 	securitySettingsKey
+	systemDialerKey
+	proxyProtocolKey
 )
 
 func ContextWithStreamSettings(ctx context.Context, streamSettings *StreamConfig) context.Context {
@@ -53,3 +56,50 @@ func ContextWithSecuritySettings(ctx context.Context, securitySettings interface
 func SecuritySettingsFromContext(ctx context.Context) interface{} {
 	return ctx.Value(securitySettingsKey)
 }
+
+// ContextWithSystemDialer returns a new context in which DialSystem dials through the given SystemDialer instead of the default one.
+func ContextWithSystemDialer(ctx context.Context, dialer SystemDialer) context.Context {
+	return context.WithValue(ctx, systemDialerKey, dialer)
+}
+
+// ContextWithoutDialerSettings returns a new context in which the dialer settings of the current dial, including the
+// SystemDialer, are cleared. Outbound handlers dispatched from within a dial use it to dial with their own settings.
+func ContextWithoutDialerSettings(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, streamSettingsKey, nil)
+	ctx = context.WithValue(ctx, dialerSrcKey, nil)
+	ctx = context.WithValue(ctx, transportSettingsKey, nil)
+	ctx = context.WithValue(ctx, securitySettingsKey, nil)
+	return ContextWithSystemDialer(ctx, nil)
+}
+
+// SystemDialerFromContext returns the SystemDialer in the context, or nil if not set.
+func SystemDialerFromContext(ctx context.Context) SystemDialer {
+	if dialer, ok := ctx.Value(systemDialerKey).(SystemDialer); ok {
+		return dialer
+	}
+	return nil
+}
+
+type proxyProtocolSettings struct {
+	required bool
+	trusted  []*net.IPNet
+}
+
+// ContextWithProxyProtocol returns a new context in which listeners accept PROXY protocol headers on incoming connections
+// from trusted peers. If required is true, connections without a PROXY protocol header are rejected.
+// See proxyproto.NewListener() for the meaning of trusted.
+func ContextWithProxyProtocol(ctx context.Context, required bool, trusted []*net.IPNet) context.Context {
+	return context.WithValue(ctx, proxyProtocolKey, &proxyProtocolSettings{
+		required: required,
+		trusted:  trusted,
+	})
+}
+
+// WrapListener applies connection settings in the context, such as PROXY protocol, to the given listener.
+// Transports call it on their raw TCP listeners, before any TLS or HTTP handling.
+func WrapListener(ctx context.Context, listener net.Listener) net.Listener {
+	if settings, ok := ctx.Value(proxyProtocolKey).(*proxyProtocolSettings); ok {
+		return proxyproto.NewListener(listener, settings.required, settings.trusted)
+	}
+	return listener
+}
@@ -52,6 +52,10 @@ func Dial(ctx context.Context, dest net.Destination) (Connection, error) {
 }
 
 // DialSystem calls system dialer to create a network connection.
+// If a SystemDialer is set in the context, the connection is created by it instead.
 func DialSystem(ctx context.Context, src net.Address, dest net.Destination) (net.Conn, error) {
+	if dialer := SystemDialerFromContext(ctx); dialer != nil {
+		return dialer.Dial(ctx, src, dest)
+	}
 	return effectiveSystemDialer.Dial(ctx, src, dest)
 }
@@ -7,6 +7,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/testing/servers/tcp"
 	. "v2ray.com/core/transport/internet"
+	_ "v2ray.com/core/transport/internet/tcp"
 	. "v2ray.com/ext/assert"
 )
 
@@ -23,3 +24,28 @@ func TestDialWithLocalAddr(t *testing.T) {
 	assert(conn.RemoteAddr().String(), Equals, "127.0.0.1:"+dest.Port.String())
 	conn.Close()
 }
+
+type recordingDialer struct {
+	dest net.Destination
+}
+
+func (d *recordingDialer) Dial(ctx context.Context, src net.Address, dest net.Destination) (net.Conn, error) {
+	d.dest = dest
+	return DefaultSystemDialer{}.Dial(ctx, src, dest)
+}
+
+func TestDialWithContextSystemDialer(t *testing.T) {
+	assert := With(t)
+
+	server := &tcp.Server{}
+	dest, err := server.Start()
+	assert(err, IsNil)
+	defer server.Close()
+
+	dialer := &recordingDialer{}
+	ctx := ContextWithSystemDialer(context.Background(), dialer)
+	conn, err := Dial(ctx, net.TCPDestination(net.LocalHostIP, dest.Port))
+	assert(err, IsNil)
+	assert(dialer.dest.Port, Equals, dest.Port)
+	conn.Close()
+}
@@ -16,8 +16,13 @@ import (
 	"v2ray.com/core/transport/pipe"
 )
 
+type dialerConf struct {
+	net.Destination
+	proxyTag string
+}
+
 var (
-	globalDialerMap    map[net.Destination]*http.Client
+	globalDialerMap    map[dialerConf]*http.Client
 	globalDailerAccess sync.Mutex
 )
 
@@ -26,10 +31,21 @@ func getHTTPClient(ctx context.Context, dest net.Destination) (*http.Client, err
 	defer globalDailerAccess.Unlock()
 
 	if globalDialerMap == nil {
-		globalDialerMap = make(map[net.Destination]*http.Client)
+		globalDialerMap = make(map[dialerConf]*http.Client)
 	}
 
-	if client, found := globalDialerMap[dest]; found {
+	// Connections chained through different proxies can't share a client. Clients of unknown system dialers are not cached.
+	systemDialer := internet.SystemDialerFromContext(ctx)
+	conf := dialerConf{Destination: dest}
+	cacheable := true
+	if systemDialer != nil {
+		if proxyDialer, ok := systemDialer.(internet.ProxySystemDialer); ok {
+			conf.proxyTag = proxyDialer.Tag()
+		} else {
+			cacheable = false
+		}
+	}
+	if client, found := globalDialerMap[conf]; found && cacheable {
 		return client, nil
 	}
 
@@ -53,7 +69,11 @@ func getHTTPClient(ctx context.Context, dest net.Destination) (*http.Client, err
 			}
 			address := net.ParseAddress(rawHost)
 
-			pconn, err := internet.DialSystem(context.Background(), nil, net.TCPDestination(address, port))
+			dialCtx := context.Background()
+			if systemDialer != nil {
+				dialCtx = internet.ContextWithSystemDialer(dialCtx, systemDialer)
+			}
+			pconn, err := internet.DialSystem(dialCtx, nil, net.TCPDestination(address, port))
 			if err != nil {
 				return nil, err
 			}
@@ -66,7 +86,9 @@ func getHTTPClient(ctx context.Context, dest net.Destination) (*http.Client, err
 		Transport: transport,
 	}
 
-	globalDialerMap[dest] = client
+	if cacheable {
+		globalDialerMap[conf] = client
+	}
 	return client, nil
 }
 
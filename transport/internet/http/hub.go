@@ -114,9 +114,14 @@ func Listen(ctx context.Context, address net.Address, port net.Port, handler int
 		Handler:   listener,
 	}
 
+	tcpListener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return nil, newError("failed to listen TCP ", server.Addr).Base(err)
+	}
+
 	listener.server = server
 	go func() {
-		err := server.ListenAndServeTLS("", "")
+		err := server.ServeTLS(internet.WrapListener(ctx, tcpListener), "", "")
 		if err != nil {
 			newError("stoping serving TLS").Base(err).WriteToLog()
 		}
@@ -15,6 +15,14 @@ type SystemDialer interface {
 	Dial(ctx context.Context, source net.Address, destination net.Destination) (net.Conn, error)
 }
 
+// ProxySystemDialer is a SystemDialer that creates connections through outbound handlers.
+type ProxySystemDialer interface {
+	SystemDialer
+
+	// Tag identifies the outbound handlers that connections go through. Dialers with the same tag are interchangeable.
+	Tag() string
+}
+
 type DefaultSystemDialer struct {
 }
 
@@ -13,7 +13,7 @@ import (
 
 // Listener is an internet.Listener that listens for TCP connections.
 type Listener struct {
-	listener   *net.TCPListener
+	listener   net.Listener
 	tlsConfig  *gotls.Config
 	authConfig internet.ConnectionAuthenticator
 	config     *Config
@@ -34,7 +34,7 @@ func ListenTCP(ctx context.Context, address net.Address, port net.Port, handler
 	tcpSettings := getTCPSettingsFromContext(ctx)
 
 	l := &Listener{
-		listener: listener,
+		listener: internet.WrapListener(ctx, listener),
 		config:   tcpSettings,
 		addConn:  handler,
 	}
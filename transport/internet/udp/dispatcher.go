@@ -108,3 +108,96 @@ func handleInput(ctx context.Context, conn *connEntry, callback ResponseCallback
 		}
 	}
 }
+
+// PacketCallback is called with response packets of a FullConeDispatcher, along with their source addresses.
+type PacketCallback func(source net.Destination, payload *buf.Buffer)
+
+// FullConeDispatcher dispatches UDP packets to any destinations through a single full-cone link,
+// so that the outbound is able to send them from a single socket, and responses from any source are passed back.
+type FullConeDispatcher struct {
+	sync.Mutex
+	conn       *connEntry
+	dispatcher core.Dispatcher
+	callback   PacketCallback
+}
+
+// NewFullConeDispatcher creates a new FullConeDispatcher. The callback is called for every response packet.
+func NewFullConeDispatcher(dispatcher core.Dispatcher, callback PacketCallback) *FullConeDispatcher {
+	return &FullConeDispatcher{
+		dispatcher: dispatcher,
+		callback:   callback,
+	}
+}
+
+func (v *FullConeDispatcher) getInboundRay(ctx context.Context, dest net.Destination) (*connEntry, error) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.conn != nil {
+		return v.conn, nil
+	}
+
+	newError("establishing new full-cone connection starting with ", dest).WriteToLog()
+
+	ctx, cancel := context.WithCancel(ContextWithFullCone(ctx))
+	entry := &connEntry{}
+	removeRay := func() {
+		cancel()
+		v.Lock()
+		if v.conn == entry {
+			v.conn = nil
+		}
+		v.Unlock()
+		common.Close(entry.link.Reader)
+		common.Close(entry.link.Writer)
+	}
+	link, err := v.dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	entry.link = link
+	entry.cancel = removeRay
+	entry.timer = signal.CancelAfterInactivity(ctx, removeRay, time.Second*4)
+	v.conn = entry
+
+	go handleInput(ctx, entry, func(b *buf.Buffer) {
+		source, err := DecodePacket(b)
+		if err != nil {
+			newError("invalid full-cone response").Base(err).WriteToLog()
+			b.Release()
+			return
+		}
+		v.callback(source, b)
+	})
+	return entry, nil
+}
+
+// Dispatch sends the payload to the given destination.
+func (v *FullConeDispatcher) Dispatch(ctx context.Context, destination net.Destination, payload *buf.Buffer) {
+	newError("dispatch full-cone request to: ", destination).AtDebug().WithContext(ctx).WriteToLog()
+
+	conn, err := v.getInboundRay(ctx, destination)
+	if err != nil {
+		newError("failed to dispatch full-cone request to ", destination).Base(err).WithContext(ctx).WriteToLog()
+		payload.Release()
+		return
+	}
+	conn.timer.Update()
+	if err := conn.link.Writer.WriteMultiBuffer(buf.NewMultiBufferValue(EncodePacket(payload, destination))); err != nil {
+		newError("failed to write UDP payload").Base(err).WithContext(ctx).WriteToLog()
+		conn.cancel()
+	}
+}
+
+// Close closes the underlying link, if any.
+func (v *FullConeDispatcher) Close() error {
+	v.Lock()
+	conn := v.conn
+	v.Unlock()
+
+	if conn != nil {
+		conn.cancel()
+	}
+	return nil
+}
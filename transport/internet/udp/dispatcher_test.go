@@ -2,6 +2,7 @@ package udp_test
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -74,3 +75,77 @@ func TestSameDestinationDispatching(t *testing.T) {
 	assert(count, Equals, uint32(1))
 	assert(msgCount, Equals, uint32(6))
 }
+
+func TestFullConeDispatching(t *testing.T) {
+	assert := With(t)
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+
+	// Echo every packet back, as if it came from its destination.
+	go func() {
+		for {
+			data, err := uplinkReader.ReadMultiBuffer()
+			if err != nil {
+				break
+			}
+			err = downlinkWriter.WriteMultiBuffer(data)
+			assert(err, IsNil)
+		}
+	}()
+
+	var count uint32
+	td := &TestDispatcher{
+		OnDispatch: func(ctx context.Context, dest net.Destination) (*core.Link, error) {
+			assert(IsFullCone(ctx), IsTrue)
+			atomic.AddUint32(&count, 1)
+			return &core.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+		},
+	}
+
+	var access sync.Mutex
+	responses := make(map[net.Destination]string)
+	dispatcher := NewFullConeDispatcher(td, func(source net.Destination, payload *buf.Buffer) {
+		access.Lock()
+		responses[source] = payload.String()
+		access.Unlock()
+		payload.Release()
+	})
+
+	dest1 := net.UDPDestination(net.LocalHostIP, 53)
+	dest2 := net.UDPDestination(net.DomainAddress("v2ray.com"), 443)
+	for _, dest := range []net.Destination{dest1, dest2} {
+		b := buf.New()
+		b.Write([]byte(dest.String()))
+		dispatcher.Dispatch(context.Background(), dest, b)
+	}
+
+	time.Sleep(time.Second)
+	dispatcher.Close()
+
+	assert(count, Equals, uint32(1))
+	access.Lock()
+	assert(responses[dest1], Equals, dest1.String())
+	assert(responses[dest2], Equals, dest2.String())
+	access.Unlock()
+}
+
+func TestPayloadReader(t *testing.T) {
+	assert := With(t)
+
+	target := net.UDPDestination(net.LocalHostIP, 53)
+	other := net.UDPDestination(net.LocalHostIP, 54)
+
+	var mb buf.MultiBuffer
+	for _, dest := range []net.Destination{other, target} {
+		b := buf.New()
+		b.Write([]byte(dest.String()))
+		mb.Append(EncodePacket(b, dest))
+	}
+	reader, writer := pipe.New()
+	assert(writer.WriteMultiBuffer(mb), IsNil)
+
+	payloads, err := (&PayloadReader{Reader: reader, Target: target}).ReadMultiBuffer()
+	assert(err, IsNil)
+	assert(payloads.String(), Equals, target.String())
+}
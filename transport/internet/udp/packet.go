@@ -0,0 +1,176 @@
+package udp
+
+import (
+	"bytes"
+	"context"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/transport/pipe"
+)
+
+type key int
+
+const fullConeKey key = 0
+
+// ContextWithFullCone returns a context that marks the link being dispatched as full-cone.
+// In a full-cone link, each buffer is a UDP packet prefixed by its address, as encoded by EncodePacket.
+// The address is the destination of packets in requests, and the source of packets in responses.
+func ContextWithFullCone(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullConeKey, true)
+}
+
+// ContextWithoutFullCone returns a context in which the link being dispatched is a plain link, even if the link of
+// the parent context is full-cone.
+func ContextWithoutFullCone(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullConeKey, false)
+}
+
+// IsFullCone returns true if the link of the context is full-cone.
+func IsFullCone(ctx context.Context) bool {
+	fullCone, _ := ctx.Value(fullConeKey).(bool)
+	return fullCone
+}
+
+var addrParser = protocol.NewAddressParser(
+	protocol.AddressFamilyByte(byte(protocol.AddressTypeIPv4), net.AddressFamilyIPv4),
+	protocol.AddressFamilyByte(byte(protocol.AddressTypeDomain), net.AddressFamilyDomain),
+	protocol.AddressFamilyByte(byte(protocol.AddressTypeIPv6), net.AddressFamilyIPv6),
+	protocol.PortThenAddress(),
+)
+
+// EncodePacket returns a new buffer with the payload prefixed by the given address. The payload is released.
+func EncodePacket(payload *buf.Buffer, addr net.Destination) *buf.Buffer {
+	defer payload.Release()
+
+	// 2 bytes port, 1 byte address type, 1 byte domain length and 255 bytes domain at most.
+	b := buf.NewSize(2 + 1 + 1 + 255 + payload.Len())
+	common.Must(addrParser.WriteAddressPort(b, addr.Address, addr.Port))
+	common.Must2(b.Write(payload.Bytes()))
+	return b
+}
+
+// DecodePacket removes the address from a buffer encoded by EncodePacket, and returns the address.
+func DecodePacket(b *buf.Buffer) (net.Destination, error) {
+	addr, port, err := addrParser.ReadAddressPort(nil, b)
+	if err != nil {
+		return net.Destination{}, newError("failed to read packet address").Base(err)
+	}
+	return net.UDPDestination(addr, port), nil
+}
+
+// peekPacket returns the address of a buffer encoded by EncodePacket, and leaves the buffer intact.
+func peekPacket(b *buf.Buffer) (net.Destination, error) {
+	addr, port, err := addrParser.ReadAddressPort(nil, bytes.NewReader(b.Bytes()))
+	if err != nil {
+		return net.Destination{}, newError("failed to read packet address").Base(err)
+	}
+	return net.UDPDestination(addr, port), nil
+}
+
+// FilterReader reads a full-cone link, and drops packets to destinations that are not allowed.
+// Allow is called once for each destination.
+type FilterReader struct {
+	Reader buf.Reader
+	Allow  func(net.Destination) bool
+
+	allowed map[net.Destination]bool
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *FilterReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if r.allowed == nil {
+		r.allowed = make(map[net.Destination]bool)
+	}
+	for {
+		mb, err := r.Reader.ReadMultiBuffer()
+		if err != nil {
+			return nil, err
+		}
+
+		packets := buf.NewMultiBufferCap(int32(len(mb)))
+		for _, b := range mb {
+			dest, err := peekPacket(b)
+			if err != nil {
+				b.Release()
+				continue
+			}
+			allowed, found := r.allowed[dest]
+			if !found {
+				allowed = r.Allow(dest)
+				r.allowed[dest] = allowed
+			}
+			if !allowed {
+				b.Release()
+				continue
+			}
+			packets.Append(b)
+		}
+		if !packets.IsEmpty() {
+			return packets, nil
+		}
+	}
+}
+
+func (r *FilterReader) CloseError() {
+	pipe.CloseError(r.Reader)
+}
+
+// PayloadReader reads a full-cone link as a plain UDP link to Target, for Outbounds that don't support full-cone.
+// Packets to other destinations are dropped.
+type PayloadReader struct {
+	Reader buf.Reader
+	Target net.Destination
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *PayloadReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	for {
+		mb, err := r.Reader.ReadMultiBuffer()
+		if err != nil {
+			return nil, err
+		}
+
+		payloads := buf.NewMultiBufferCap(int32(len(mb)))
+		for _, b := range mb {
+			dest, err := DecodePacket(b)
+			if err != nil || dest != r.Target {
+				b.Release()
+				continue
+			}
+			payloads.Append(b)
+		}
+		if !payloads.IsEmpty() {
+			return payloads, nil
+		}
+	}
+}
+
+func (r *PayloadReader) CloseError() {
+	pipe.CloseError(r.Reader)
+}
+
+// PacketWriter writes payloads of a plain UDP link from Source into a full-cone link.
+type PacketWriter struct {
+	Writer buf.Writer
+	Source net.Destination
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	packets := buf.NewMultiBufferCap(int32(len(mb)))
+	for _, b := range mb {
+		packets.Append(EncodePacket(b, w.Source))
+	}
+	return w.Writer.WriteMultiBuffer(packets)
+}
+
+func (w *PacketWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *PacketWriter) CloseError() {
+	pipe.CloseError(w.Writer)
+}
@@ -42,7 +42,13 @@ func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Req
 	forwardedAddrs := http_proto.ParseXForwardedFor(request.Header)
 	remoteAddr := conn.RemoteAddr()
 	if len(forwardedAddrs) > 0 && forwardedAddrs[0].Family().Either(net.AddressFamilyIPv4, net.AddressFamilyIPv6) {
-		remoteAddr.(*net.TCPAddr).IP = forwardedAddrs[0].IP()
+		// The address may be of any network, as given by the PROXY protocol header.
+		switch addr := remoteAddr.(type) {
+		case *net.TCPAddr:
+			remoteAddr = &net.TCPAddr{IP: forwardedAddrs[0].IP(), Port: addr.Port}
+		case *net.UDPAddr:
+			remoteAddr = &net.UDPAddr{IP: forwardedAddrs[0].IP(), Port: addr.Port}
+		}
 	}
 
 	h.ln.addConn(newConnection(conn, remoteAddr))
@@ -68,26 +74,20 @@ func ListenWS(ctx context.Context, address net.Address, port net.Port, addConn i
 		l.tlsConfig = config.GetTLSConfig()
 	}
 
-	err := l.listenws(address, port)
+	err := l.listenws(ctx, address, port)
 
 	return l, err
 }
 
-func (ln *Listener) listenws(address net.Address, port net.Port) error {
+func (ln *Listener) listenws(ctx context.Context, address net.Address, port net.Port) error {
 	netAddr := address.String() + ":" + strconv.Itoa(int(port.Value()))
-	var listener net.Listener
-	if ln.tlsConfig == nil {
-		l, err := net.Listen("tcp", netAddr)
-		if err != nil {
-			return newError("failed to listen TCP ", netAddr).Base(err)
-		}
-		listener = l
-	} else {
-		l, err := tls.Listen("tcp", netAddr, ln.tlsConfig)
-		if err != nil {
-			return newError("failed to listen TLS ", netAddr).Base(err)
-		}
-		listener = l
+	listener, err := net.Listen("tcp", netAddr)
+	if err != nil {
+		return newError("failed to listen TCP ", netAddr).Base(err)
+	}
+	listener = internet.WrapListener(ctx, listener)
+	if ln.tlsConfig != nil {
+		listener = tls.NewListener(listener, ln.tlsConfig)
 	}
 	ln.listener = listener
 
@@ -1,12 +1,17 @@
 package websocket_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 	"v2ray.com/core/common/protocol/tls/cert"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/tls"
@@ -101,6 +106,49 @@ func TestDialWithRemoteAddr(t *testing.T) {
 	assert(listen.Close(), IsNil)
 }
 
+func TestForwardedAddrWithProxyProtocolUDP(t *testing.T) {
+	assert := With(t)
+
+	remoteAddr := make(chan net.Addr, 1)
+	ctx := internet.ContextWithTransportSettings(context.Background(), &Config{
+		Path: "ws",
+	})
+	listen, err := ListenWS(internet.ContextWithProxyProtocol(ctx, true, nil), net.LocalHostIP, 13149, func(conn internet.Connection) {
+		remoteAddr <- conn.RemoteAddr()
+		conn.Close()
+	})
+	assert(err, IsNil)
+	defer listen.Close()
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{IP: []byte{127, 0, 0, 1}, Port: 13149})
+	assert(err, IsNil)
+	defer conn.Close()
+
+	header := &proxyproto.Header{
+		Version:     2,
+		Source:      net.UDPDestination(net.ParseAddress("10.0.0.1"), 1234),
+		Destination: net.UDPDestination(net.ParseAddress("10.0.0.2"), 53),
+	}
+	b := buf.New()
+	common.Must(header.WriteTo(b))
+	common.Must2(b.Write([]byte("GET /ws HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\nX-Forwarded-For: 1.1.1.1\r\n\r\n")))
+	_, err = conn.Write(b.Bytes())
+	assert(err, IsNil)
+	b.Release()
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	assert(err, IsNil)
+	assert(response.StatusCode, Equals, http.StatusSwitchingProtocols)
+
+	select {
+	case addr := <-remoteAddr:
+		assert(addr.String(), Equals, "1.1.1.1:1234")
+	case <-time.After(time.Second * 5):
+		t.Error("no connection is accepted")
+	}
+}
+
 func Test_listenWSAndDial_TLS(t *testing.T) {
 	assert := With(t)
 
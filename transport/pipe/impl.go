@@ -23,6 +23,7 @@ type pipe struct {
 	data        buf.MultiBuffer
 	readSignal  *signal.Notifier
 	writeSignal *signal.Notifier
+	done        *signal.Done
 	limit       int32
 	state       state
 }
@@ -132,6 +133,7 @@ func (p *pipe) Close() error {
 	p.state = closed
 	p.readSignal.Signal()
 	p.writeSignal.Signal()
+	p.done.Close()
 	return nil
 }
 
@@ -152,4 +154,5 @@ func (p *pipe) CloseError() {
 
 	p.readSignal.Signal()
 	p.writeSignal.Signal()
+	p.done.Close()
 }
@@ -25,6 +25,7 @@ func New(opts ...Option) (*Reader, *Writer) {
 		limit:       defaultLimit,
 		readSignal:  signal.NewNotifier(),
 		writeSignal: signal.NewNotifier(),
+		done:        signal.NewDone(),
 	}
 
 	for _, opt := range opts {
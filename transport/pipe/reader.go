@@ -25,3 +25,8 @@ func (r *Reader) ReadMultiBufferWithTimeout(d time.Duration) (buf.MultiBuffer, e
 func (r *Reader) CloseError() {
 	r.pipe.CloseError()
 }
+
+// Done returns a channel that is closed once the pipe is closed, either normally or with error.
+func (r *Reader) Done() <-chan struct{} {
+	return r.pipe.done.Wait()
+}
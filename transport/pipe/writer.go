@@ -23,3 +23,8 @@ func (w *Writer) Close() error {
 func (w *Writer) CloseError() {
 	w.pipe.CloseError()
 }
+
+// Done returns a channel that is closed once the pipe is closed, either normally or with error.
+func (w *Writer) Done() <-chan struct{} {
+	return w.pipe.done.Wait()
+}